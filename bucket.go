@@ -1,18 +1,249 @@
 package gocassa
 
 import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 )
 
-func bucket(t time.Time, step time.Duration) time.Time {
-	return bucketIter{
-		v:    t,
-		step: step}.Bucket()
+// BucketStrategy decides which bucket, and so which partition, of a time based recipe a time is stored in.
+type BucketStrategy interface {
+	// Bucket returns the start of the bucket t falls in
+	Bucket(t time.Time) time.Time
+	// Next returns the start of the bucket following the one starting at bucket
+	Next(bucket time.Time) time.Time
+	// Prev returns the start of the bucket preceding the one starting at bucket
+	Prev(bucket time.Time) time.Time
+	// Name identifies the strategy in table names, so it must be stable, short (as Cassandra limits table names to 48
+	// characters) and only contain letters, digits and underscores
+	Name() string
+}
+
+// FixedBuckets returns a strategy of buckets of a fixed duration, aligned to the Unix epoch and rounded down to whole
+// seconds. This is the bucketing of recipes created with a bucketSize.
+func FixedBuckets(size time.Duration) BucketStrategy {
+	return fixedBuckets(size)
+}
+
+type fixedBuckets time.Duration
+
+func (b fixedBuckets) step() int64 {
+	step := int64(time.Duration(b) / time.Second)
+	if step < 1 {
+		step = 1
+	}
+	return step
+}
+
+func (b fixedBuckets) Bucket(t time.Time) time.Time {
+	secs := t.Unix()
+	return time.Unix(secs-secs%b.step(), 0)
+}
+
+func (b fixedBuckets) Next(bucket time.Time) time.Time {
+	return b.Bucket(bucket).Add(time.Duration(b.step()) * time.Second)
+}
+
+func (b fixedBuckets) Prev(bucket time.Time) time.Time {
+	return b.Bucket(bucket).Add(-time.Duration(b.step()) * time.Second)
+}
+
+func (b fixedBuckets) Name() string {
+	return time.Duration(b).String()
+}
+
+type calendarUnit int
+
+const (
+	calendarDay calendarUnit = iota
+	calendarWeek
+	calendarMonth
+)
+
+// DailyBuckets returns a strategy of buckets for each calendar day in loc, which follow its daylight saving changes.
+// loc should be a named location (not time.Local), as its name identifies the table.
+func DailyBuckets(loc *time.Location) BucketStrategy {
+	return calendarBuckets{unit: calendarDay, loc: loc}
+}
+
+// WeeklyBuckets returns a strategy of buckets for each calendar week in loc, starting on Mondays.
+func WeeklyBuckets(loc *time.Location) BucketStrategy {
+	return calendarBuckets{unit: calendarWeek, loc: loc}
+}
+
+// MonthlyBuckets returns a strategy of buckets for each calendar month in loc.
+func MonthlyBuckets(loc *time.Location) BucketStrategy {
+	return calendarBuckets{unit: calendarMonth, loc: loc}
+}
+
+type calendarBuckets struct {
+	unit calendarUnit
+	loc  *time.Location
+}
+
+func (b calendarBuckets) Bucket(t time.Time) time.Time {
+	t = t.In(b.loc)
+	switch b.unit {
+	case calendarWeek:
+		day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, b.loc)
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	case calendarMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, b.loc)
+	default:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, b.loc)
+	}
+}
+
+func (b calendarBuckets) Next(bucket time.Time) time.Time {
+	bucket = b.Bucket(bucket)
+	switch b.unit {
+	case calendarWeek:
+		return b.Bucket(bucket.AddDate(0, 0, 7))
+	case calendarMonth:
+		return b.Bucket(bucket.AddDate(0, 1, 0))
+	default:
+		return b.Bucket(bucket.AddDate(0, 0, 1))
+	}
+}
+
+func (b calendarBuckets) Prev(bucket time.Time) time.Time {
+	return b.Bucket(b.Bucket(bucket).Add(-time.Nanosecond))
+}
+
+func (b calendarBuckets) Name() string {
+	unit := map[calendarUnit]string{calendarDay: "daily", calendarWeek: "weekly", calendarMonth: "monthly"}[b.unit]
+	return shortBucketName(unit, b.loc.String())
+}
+
+// LogarithmicBuckets returns a strategy of buckets which grow by factor from origin, the first lasting first. It
+// suits series which are dense around origin and thin out after it. Times before origin are bucketed by first.
+func LogarithmicBuckets(origin time.Time, first time.Duration, factor float64) BucketStrategy {
+	if first < time.Second || factor <= 1 {
+		panic(fmt.Sprintf("invalid logarithmic buckets: first bucket %v must be at least a second and factor %v more than 1", first, factor))
+	}
+	return logarithmicBuckets{origin: time.Unix(origin.Unix(), 0), first: first.Truncate(time.Second), factor: factor}
+}
+
+type logarithmicBuckets struct {
+	origin time.Time
+	first  time.Duration
+	factor float64
+}
+
+// start returns the start of the nth bucket, which is negative before origin
+func (b logarithmicBuckets) start(n int) time.Time {
+	if n <= 0 {
+		return b.origin.Add(time.Duration(n) * b.first)
+	}
+	secs := math.Floor(b.first.Seconds() * (math.Pow(b.factor, float64(n)) - 1) / (b.factor - 1))
+	return b.origin.Add(time.Duration(secs) * time.Second)
+}
+
+// index returns the number of the bucket t falls in
+func (b logarithmicBuckets) index(t time.Time) int {
+	d := t.Sub(b.origin)
+	if d < 0 {
+		return -int((-d + b.first - 1) / b.first)
+	}
+	n := int(math.Floor(math.Log(1+d.Seconds()*(b.factor-1)/b.first.Seconds()) / math.Log(b.factor)))
+	// Correct for any floating point error in the estimate
+	for n > 0 && b.start(n).After(t) {
+		n--
+	}
+	for !b.start(n + 1).After(t) {
+		n++
+	}
+	return n
+}
+
+func (b logarithmicBuckets) Bucket(t time.Time) time.Time { return b.start(b.index(t)) }
+func (b logarithmicBuckets) Next(bucket time.Time) time.Time {
+	return b.start(b.index(bucket) + 1)
+}
+func (b logarithmicBuckets) Prev(bucket time.Time) time.Time {
+	return b.start(b.index(bucket) - 1)
+}
+
+func (b logarithmicBuckets) Name() string {
+	return shortBucketName("log", fmt.Sprintf("%d %s %s", b.origin.Unix(), b.first, strconv.FormatFloat(b.factor, 'f', -1, 64)))
+}
+
+// BucketTier applies a bucket strategy to the times from From.
+type BucketTier struct {
+	From    time.Time
+	Buckets BucketStrategy
+}
+
+// TieredBuckets returns a strategy which changes from one strategy to another over time, for example to use smaller
+// buckets once a series becomes busier. The earliest tier also applies before it starts. As the tiers are part of
+// the table's name, tiers can't be added to an existing table.
+func TieredBuckets(tiers ...BucketTier) BucketStrategy {
+	if len(tiers) == 0 {
+		panic("tiered buckets need at least one tier")
+	}
+	sorted := append([]BucketTier{}, tiers...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].From.Before(sorted[j].From) })
+	return tieredBuckets(sorted)
+}
+
+type tieredBuckets []BucketTier
+
+// tier returns the index of the tier t falls in
+func (b tieredBuckets) tier(t time.Time) int {
+	i := sort.Search(len(b), func(i int) bool { return b[i].From.After(t) })
+	if i == 0 {
+		return 0
+	}
+	return i - 1
+}
+
+func (b tieredBuckets) Bucket(t time.Time) time.Time {
+	i := b.tier(t)
+	bucket := b[i].Buckets.Bucket(t)
+	// The first bucket of a tier starts with the tier
+	if i > 0 && bucket.Before(b[i].From) {
+		return b[i].From
+	}
+	return bucket
+}
+
+func (b tieredBuckets) Next(bucket time.Time) time.Time {
+	bucket = b.Bucket(bucket)
+	i := b.tier(bucket)
+	next := b[i].Buckets.Next(b[i].Buckets.Bucket(bucket))
+	if i+1 < len(b) && !next.Before(b[i+1].From) {
+		return b[i+1].From
+	}
+	return next
+}
+
+func (b tieredBuckets) Prev(bucket time.Time) time.Time {
+	return b.Bucket(b.Bucket(bucket).Add(-time.Nanosecond))
+}
+
+func (b tieredBuckets) Name() string {
+	parts := []string{}
+	for _, tier := range b {
+		parts = append(parts, strconv.FormatInt(tier.From.Unix(), 10), tier.Buckets.Name())
+	}
+	return shortBucketName("tiered", strings.Join(parts, " "))
+}
+
+// shortBucketName returns the name of a kind of strategy followed by a hash of its parameters, so that table names
+// stay short however long the parameters are
+func shortBucketName(kind, params string) string {
+	h := fnv.New32a()
+	h.Write([]byte(params))
+	return fmt.Sprintf("%s_%08x", kind, h.Sum32())
 }
 
 type bucketIter struct {
 	v         time.Time
-	step      time.Duration
+	strategy  BucketStrategy
 	field     string
 	invariant Filter
 }
@@ -22,26 +253,21 @@ func (b bucketIter) String() string {
 }
 
 func (b bucketIter) Bucket() time.Time {
-	step := b.step
-	if step < time.Second {
-		step = time.Second
-	}
-	secs := b.v.Unix()
-	return time.Unix((secs - secs%int64(step/time.Second)), 0)
+	return b.strategy.Bucket(b.v)
 }
 
 func (b bucketIter) Next() Buckets {
 	return bucketIter{
-		v:         b.v.Add(b.step),
-		step:      b.step,
+		v:         b.strategy.Next(b.Bucket()),
+		strategy:  b.strategy,
 		invariant: b.invariant,
 		field:     b.field}
 }
 
 func (b bucketIter) Prev() Buckets {
 	return bucketIter{
-		v:         b.v.Add(-b.step),
-		step:      b.step,
+		v:         b.strategy.Prev(b.Bucket()),
+		strategy:  b.strategy,
 		invariant: b.invariant,
 		field:     b.field}
 }
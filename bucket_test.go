@@ -0,0 +1,116 @@
+package gocassa
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFixedBuckets(t *testing.T) {
+	b := FixedBuckets(time.Hour)
+	ts := time.Date(2021, 3, 28, 12, 30, 0, 0, time.UTC)
+	assert.True(t, b.Bucket(ts).Equal(time.Date(2021, 3, 28, 12, 0, 0, 0, time.UTC)))
+	assert.True(t, b.Next(b.Bucket(ts)).Equal(time.Date(2021, 3, 28, 13, 0, 0, 0, time.UTC)))
+	assert.True(t, b.Prev(b.Bucket(ts)).Equal(time.Date(2021, 3, 28, 11, 0, 0, 0, time.UTC)))
+
+	// Fixed buckets keep the names of tables created with a bucket size
+	ks := NewMockKeySpace()
+	assert.Equal(t, ks.TimeSeriesTable("trips", "Time", "Id", time.Hour, Trip{}).Name(),
+		ks.TimeSeriesTableWithBuckets("trips", "Time", "Id", b, Trip{}).Name())
+	assert.Equal(t, "trips_timeSeries_Time_Id_1h0m0s", ks.TimeSeriesTableWithBuckets("trips", "Time", "Id", b, Trip{}).Name())
+}
+
+func TestCalendarBuckets(t *testing.T) {
+	london, err := time.LoadLocation("Europe/London")
+	require.NoError(t, err)
+
+	// The clocks go forward on the 28th of March 2021, so that day lasts 23 hours
+	daily := DailyBuckets(london)
+	day := daily.Bucket(time.Date(2021, 3, 28, 12, 0, 0, 0, time.UTC))
+	assert.True(t, day.Equal(time.Date(2021, 3, 28, 0, 0, 0, 0, london)))
+	assert.Equal(t, 23*time.Hour, daily.Next(day).Sub(day))
+	assert.True(t, daily.Prev(daily.Next(day)).Equal(day))
+	// Midnight in London is still the previous day in UTC during summer time
+	assert.True(t, daily.Bucket(time.Date(2021, 6, 1, 23, 30, 0, 0, time.UTC)).Equal(time.Date(2021, 6, 2, 0, 0, 0, 0, london)))
+	assert.Equal(t, "daily_be803f4e", daily.Name())
+
+	weekly := WeeklyBuckets(london)
+	week := weekly.Bucket(time.Date(2021, 3, 28, 12, 0, 0, 0, london))
+	assert.True(t, week.Equal(time.Date(2021, 3, 22, 0, 0, 0, 0, london)))
+	assert.True(t, weekly.Next(week).Equal(time.Date(2021, 3, 29, 0, 0, 0, 0, london)))
+	assert.Equal(t, "weekly_be803f4e", weekly.Name())
+
+	monthly := MonthlyBuckets(time.UTC)
+	month := monthly.Bucket(time.Date(2021, 1, 31, 12, 0, 0, 0, time.UTC))
+	assert.True(t, month.Equal(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)))
+	assert.True(t, monthly.Next(month).Equal(time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC)))
+	assert.True(t, monthly.Prev(month).Equal(time.Date(2020, 12, 1, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, "monthly_c06e567d", monthly.Name())
+
+	// Names stay short however long the location's name is, to keep within Cassandra's limit on table names
+	losAngeles, err := time.LoadLocation("America/Los_Angeles")
+	require.NoError(t, err)
+	tbl := NewMockKeySpace().TimeSeriesTableWithBuckets("events", "Time", "Id", DailyBuckets(losAngeles), Trip{})
+	assert.Equal(t, "events_timeSeries_Time_Id_daily_99387cd4", tbl.Name())
+}
+
+func TestLogarithmicBuckets(t *testing.T) {
+	origin := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	b := LogarithmicBuckets(origin, time.Hour, 2)
+
+	// Buckets start 0, 1, 3, 7, 15 hours after origin
+	for hours, expected := range map[int]int{0: 0, 2: 1, 5: 3, 7: 7, 14: 7, 15: 15} {
+		bucket := b.Bucket(origin.Add(time.Duration(hours) * time.Hour))
+		assert.True(t, bucket.Equal(origin.Add(time.Duration(expected)*time.Hour)), "%d hours", hours)
+	}
+	assert.True(t, b.Next(origin.Add(3*time.Hour)).Equal(origin.Add(7*time.Hour)))
+	assert.True(t, b.Prev(origin.Add(3*time.Hour)).Equal(origin.Add(time.Hour)))
+	// Before origin the buckets last as long as the first
+	assert.True(t, b.Bucket(origin.Add(-30*time.Minute)).Equal(origin.Add(-time.Hour)))
+	assert.True(t, b.Prev(origin).Equal(origin.Add(-time.Hour)))
+	assert.Equal(t, "log_aa5d50e2", b.Name())
+
+	assert.Panics(t, func() { LogarithmicBuckets(origin, time.Hour, 1) })
+}
+
+func TestTieredBuckets(t *testing.T) {
+	switchover := time.Date(2021, 1, 1, 6, 0, 0, 0, time.UTC)
+	b := TieredBuckets(
+		BucketTier{From: switchover, Buckets: FixedBuckets(time.Hour)},
+		BucketTier{Buckets: FixedBuckets(24 * time.Hour)},
+	)
+
+	day := b.Bucket(time.Date(2020, 12, 31, 12, 0, 0, 0, time.UTC))
+	assert.True(t, day.Equal(time.Date(2020, 12, 31, 0, 0, 0, 0, time.UTC)))
+	// The last daily bucket is cut short by the hourly tier
+	first := b.Next(b.Next(day))
+	assert.True(t, first.Equal(switchover))
+	assert.True(t, b.Bucket(switchover.Add(-time.Minute)).Equal(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)))
+	assert.True(t, b.Next(first).Equal(switchover.Add(time.Hour)))
+	assert.True(t, b.Prev(first).Equal(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, "tiered_3afa7690", b.Name())
+}
+
+func TestMockMonthlyTimeSeries(t *testing.T) {
+	tbl := NewMockKeySpace().TimeSeriesTableWithBuckets("trips", "Time", "Id", MonthlyBuckets(time.UTC), Trip{})
+	assert.Equal(t, "trips_timeSeries_Time_Id_monthly_c06e567d", tbl.Name())
+
+	start := time.Date(2021, 1, 15, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 4; i++ {
+		require.NoError(t, tbl.Set(Trip{Id: string(rune('a' + i)), Time: start.AddDate(0, i, 0)}).Run())
+	}
+
+	trips := []Trip{}
+	require.NoError(t, tbl.List(start, start.AddDate(0, 2, 0), &trips).Run())
+	require.Len(t, trips, 3)
+	assert.Equal(t, "a", trips[0].Id)
+	assert.Equal(t, "c", trips[2].Id)
+
+	var latest []Trip
+	require.NoError(t, tbl.ListWithLimit(start, start.AddDate(1, 0, 0), SeriesListOptions{Order: DESC, Limit: 1}, &latest).Run())
+	require.Len(t, latest, 1)
+	assert.Equal(t, "d", latest[0].Id)
+}
@@ -0,0 +1,176 @@
+package gocassa
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// CacheEntry is a row of a CacheTable. A Missing entry records that the loader found no value for the key.
+type CacheEntry struct {
+	Key     string
+	Value   []byte
+	Missing bool
+}
+
+// errLoaderPanicked is returned to the callers of Get waiting on a loader which panicked
+var errLoaderPanicked = errors.New("cache loader panicked")
+
+type cacheT struct {
+	t           Table
+	ttl         time.Duration
+	negativeTTL time.Duration
+	loads       *cacheLoads
+}
+
+func (o *cacheT) Table() Table                        { return o.t }
+func (o *cacheT) Create() error                       { return o.Table().Create() }
+func (o *cacheT) CreateIfNotExist() error             { return o.Table().CreateIfNotExist() }
+func (o *cacheT) Name() string                        { return o.Table().Name() }
+func (o *cacheT) Recreate() error                     { return o.Table().Recreate() }
+func (o *cacheT) CreateStatement() (Statement, error) { return o.Table().CreateStatement() }
+func (o *cacheT) CreateIfNotExistStatement() (Statement, error) {
+	return o.Table().CreateIfNotExistStatement()
+}
+func (o *cacheT) AlterOptions() error { return o.Table().AlterOptions() }
+func (o *cacheT) AlterOptionsStatement() (Statement, error) {
+	return o.Table().AlterOptionsStatement()
+}
+
+// write returns the op caching the entry for the TTL of its kind
+func (o *cacheT) write(entry CacheEntry) Op {
+	ttl := o.ttl
+	if entry.Missing {
+		ttl = o.negativeTTL
+	}
+	return o.Table().Set(entry).WithOptions(Options{TTL: ttl})
+}
+
+// read reads the cached entry of key, returning false if there isn't one
+func (o *cacheT) read(ctx context.Context, key string) (CacheEntry, bool, error) {
+	entry := CacheEntry{}
+	err := o.Table().Where(Eq("Key", key)).ReadOne(&entry).RunWithContext(ctx)
+	if _, ok := err.(RowNotFoundError); ok {
+		return entry, false, nil
+	}
+	return entry, err == nil, err
+}
+
+func (o *cacheT) Get(ctx context.Context, key string, loader func() ([]byte, error)) ([]byte, error) {
+	entry, ok, err := o.read(ctx, key)
+	switch {
+	case err != nil:
+		return nil, err
+	case ok && entry.Missing:
+		return nil, ErrValueNotFound
+	case ok:
+		return entry.Value, nil
+	}
+
+	// The load's result is shared with the callers waiting on it, so it's cached without the context of the caller
+	// running it, bounded by the table's Timeout rather than by that caller being cancelled
+	return o.loads.do(ctx, key, func() ([]byte, error) {
+		value, err := loader()
+		switch {
+		case errors.Is(err, ErrValueNotFound) && o.negativeTTL > 0:
+			if werr := o.write(CacheEntry{Key: key, Missing: true}).Run(); werr != nil {
+				return nil, werr
+			}
+			return nil, err
+		case err != nil:
+			return nil, err
+		}
+		return value, o.write(CacheEntry{Key: key, Value: value}).Run()
+	})
+}
+
+func (o *cacheT) Set(key string, value []byte) Op {
+	return o.write(CacheEntry{Key: key, Value: value})
+}
+
+func (o *cacheT) Touch(ctx context.Context, key string) error {
+	entry, ok, err := o.read(ctx, key)
+	switch {
+	case err != nil:
+		return err
+	case !ok:
+		return ErrNotCached
+	}
+	return o.write(entry).RunWithContext(ctx)
+}
+
+func (o *cacheT) Delete(key string) Op {
+	return o.Table().Where(Eq("Key", key)).Delete()
+}
+
+func (o *cacheT) WithOptions(opt Options) CacheTable {
+	return &cacheT{
+		t:           o.Table().WithOptions(opt),
+		ttl:         o.ttl,
+		negativeTTL: o.negativeTTL,
+		loads:       o.loads,
+	}
+}
+
+// cacheLoads deduplicates the concurrent loads of each key, so only one caller runs the loader while the others wait
+// for its result
+type cacheLoads struct {
+	mtx   sync.Mutex
+	loads map[string]*cacheLoad
+}
+
+type cacheLoad struct {
+	done  chan struct{}
+	value []byte
+	err   error
+}
+
+func newCacheLoads() *cacheLoads {
+	return &cacheLoads{loads: map[string]*cacheLoad{}}
+}
+
+// do calls load for key, unless a load of key is already running, in which case its result is returned once it's done
+// or ctx is
+func (c *cacheLoads) do(ctx context.Context, key string, load func() ([]byte, error)) ([]byte, error) {
+	c.mtx.Lock()
+	if l, ok := c.loads[key]; ok {
+		c.mtx.Unlock()
+		select {
+		case <-l.done:
+			return l.value, l.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	l := &cacheLoad{done: make(chan struct{}), err: errLoaderPanicked}
+	c.loads[key] = l
+	c.mtx.Unlock()
+
+	defer func() {
+		c.mtx.Lock()
+		delete(c.loads, key)
+		c.mtx.Unlock()
+		close(l.done)
+	}()
+	l.value, l.err = load()
+	return l.value, l.err
+}
+
+// inFlight returns the number of keys being loaded
+func (c *cacheLoads) inFlight() int {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return len(c.loads)
+}
+
+// checkCacheTTLs panics if the TTLs can't be stored by Cassandra, which truncates TTLs to seconds
+func checkCacheTTLs(ttl, negativeTTL time.Duration) {
+	if ttl < time.Second {
+		panic(fmt.Sprintf("cache TTLs must be at least a second, got %v", ttl))
+	}
+	if negativeTTL != 0 && negativeTTL < time.Second {
+		panic(fmt.Sprintf("cache TTLs must be at least a second, got a negative TTL of %v", negativeTTL))
+	}
+}
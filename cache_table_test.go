@@ -0,0 +1,176 @@
+package gocassa
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheTableStatements(t *testing.T) {
+	var stmts []Statement
+	qe := funcQE{
+		fn: func(opts Options, stmt Statement) error {
+			stmts = append(stmts, stmt)
+			return nil
+		},
+		query: func(stmt Statement, scanner Scanner) error {
+			stmts = append(stmts, stmt)
+			_, err := scanner.ScanIter(newMockIterator(nil, stmt.(SelectStatement).fields))
+			return err
+		},
+	}
+	cache := NewConnection(qe).KeySpace("ks").CacheTable("sessions", time.Hour, time.Minute)
+
+	value, err := cache.Get(context.Background(), "abc", func() ([]byte, error) { return []byte("session"), nil })
+	require.NoError(t, err)
+	assert.Equal(t, []byte("session"), value)
+	require.Len(t, stmts, 2)
+	assert.Equal(t, "SELECT key, missing, value FROM ks.sessions_cache WHERE key = ?", stmts[0].Query())
+	assert.Equal(t, "UPDATE ks.sessions_cache USING TTL ? SET missing = ?, value = ? WHERE key = ?", stmts[1].Query())
+	assert.Equal(t, []interface{}{3600, false, []byte("session"), "abc"}, stmts[1].Values())
+
+	_, err = cache.Get(context.Background(), "def", func() ([]byte, error) { return nil, ErrValueNotFound })
+	assert.Equal(t, ErrValueNotFound, err)
+	require.Len(t, stmts, 4)
+	assert.Equal(t, []interface{}{60, true, []byte(nil), "def"}, stmts[3].Values())
+
+	assert.Panics(t, func() { NewConnection(qe).KeySpace("ks").CacheTable("sessions", time.Millisecond, 0) })
+}
+
+func TestMockCacheTable(t *testing.T) {
+	clock := NewMockClock(time.Date(2020, 3, 4, 5, 6, 7, 0, time.UTC))
+	cache := NewMockKeySpace().CacheTable("sessions", time.Minute, 10*time.Second).WithOptions(Options{Clock: clock})
+	ctx := context.Background()
+
+	loads := 0
+	loader := func(value string, err error) func() ([]byte, error) {
+		return func() ([]byte, error) {
+			loads++
+			if err != nil {
+				return nil, err
+			}
+			return []byte(value), nil
+		}
+	}
+
+	value, err := cache.Get(ctx, "abc", loader("v1", nil))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("v1"), value)
+	value, err = cache.Get(ctx, "abc", loader("v2", nil))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("v1"), value)
+	assert.Equal(t, 1, loads)
+
+	// Touching an entry restarts its TTL
+	clock.Advance(50 * time.Second)
+	require.NoError(t, cache.Touch(ctx, "abc"))
+	clock.Advance(50 * time.Second)
+	value, err = cache.Get(ctx, "abc", loader("v2", nil))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("v1"), value)
+
+	clock.Advance(10 * time.Second)
+	value, err = cache.Get(ctx, "abc", loader("v2", nil))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("v2"), value)
+	assert.Equal(t, 2, loads)
+	assert.Equal(t, ErrNotCached, cache.Touch(ctx, "def"))
+
+	// Values which aren't found are cached for the negative TTL
+	_, err = cache.Get(ctx, "def", loader("", fmt.Errorf("no session: %w", ErrValueNotFound)))
+	assert.True(t, errors.Is(err, ErrValueNotFound))
+	_, err = cache.Get(ctx, "def", loader("v1", nil))
+	assert.Equal(t, ErrValueNotFound, err)
+	assert.Equal(t, 3, loads)
+	clock.Advance(10 * time.Second)
+	value, err = cache.Get(ctx, "def", loader("v1", nil))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("v1"), value)
+
+	// Other errors aren't cached
+	loadErr := errors.New("unavailable")
+	_, err = cache.Get(ctx, "ghi", loader("", loadErr))
+	assert.Equal(t, loadErr, err)
+	value, err = cache.Get(ctx, "ghi", loader("v1", nil))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("v1"), value)
+
+	require.NoError(t, cache.Set("abc", []byte("v3")).Run())
+	value, err = cache.Get(ctx, "abc", loader("v2", nil))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("v3"), value)
+	require.NoError(t, cache.Delete("abc").Run())
+	assert.Equal(t, ErrNotCached, cache.Touch(ctx, "abc"))
+}
+
+func TestMockCacheTableDeduplicatesLoads(t *testing.T) {
+	cache := NewMockKeySpace().CacheTable("sessions", time.Minute, 0)
+	ctx := context.Background()
+
+	loads := 0
+	release := make(chan struct{})
+	loader := func() ([]byte, error) {
+		loads++
+		<-release
+		return []byte("v1"), nil
+	}
+
+	var wg sync.WaitGroup
+	values := make([][]byte, 3)
+	for i := range values {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			value, err := cache.Get(ctx, "abc", loader)
+			assert.NoError(t, err)
+			values[i] = value
+		}(i)
+	}
+	require.Eventually(t, func() bool { return cache.(*cacheT).loads.inFlight() == 1 }, time.Second, time.Millisecond)
+
+	// A caller waiting on another's load gives up when its context is done
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err := cache.Get(cancelled, "abc", loader)
+	assert.Equal(t, context.Canceled, err)
+
+	close(release)
+	wg.Wait()
+	assert.Equal(t, 1, loads)
+	assert.Equal(t, [][]byte{[]byte("v1"), []byte("v1"), []byte("v1")}, values)
+	assert.Equal(t, 0, cache.(*cacheT).loads.inFlight())
+}
+
+func TestCacheTableWritesLoadsWithoutCallerContext(t *testing.T) {
+	var writeCtx context.Context
+	qe := funcQE{
+		fn: func(opts Options, stmt Statement) error {
+			writeCtx = opts.Context
+			return writeCtx.Err()
+		},
+		query: func(stmt Statement, scanner Scanner) error {
+			_, err := scanner.ScanIter(newMockIterator(nil, stmt.(SelectStatement).fields))
+			return err
+		},
+	}
+	cache := NewConnection(qe).KeySpace("ks").CacheTable("sessions", time.Hour, time.Minute).
+		WithOptions(Options{Timeout: time.Minute})
+
+	// The value loaded is shared with the callers waiting on it, so it's cached even if the loading caller gives up
+	ctx, cancel := context.WithCancel(context.Background())
+	value, err := cache.Get(ctx, "abc", func() ([]byte, error) {
+		cancel()
+		return []byte("session"), nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []byte("session"), value)
+	require.NotNil(t, writeCtx)
+	_, ok := writeCtx.Deadline()
+	assert.True(t, ok, "the write should be bounded by the table's Timeout")
+}
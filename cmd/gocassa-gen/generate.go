@@ -0,0 +1,518 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+
+	gocassareflect "github.com/monzo/gocassa/reflect"
+)
+
+const directivePrefix = "//gocassa:table "
+
+// entity is an annotated struct and the tables it is stored in
+type entity struct {
+	Name    string
+	Columns []column
+	// Updatable holds the columns which aren't part of the key of any of the entity's tables
+	Updatable []column
+	Tables    []table
+}
+
+// column is a field of an entity, as stored in Cassandra
+type column struct {
+	// Field is the name of the struct field
+	Field string
+	// Name is the column name, which gocassa takes from the field's cql tag if it has one
+	Name string
+	// Type is the Go type of the field
+	Type string
+	// Const is the name of the generated constant holding the column name
+	Const string
+	// Param is the name of the column when passed as a parameter
+	Param string
+}
+
+// table is a recipe table an entity is stored in
+type table struct {
+	Wrapper    string
+	Recipe     string
+	Name       string
+	Partition  column
+	Clustering column
+}
+
+// generate returns the wrappers of the annotated structs in the Go source file given
+func generate(filename string, src []byte) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	entities := []entity{}
+	packages := map[string]bool{}
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			ts := spec.(*ast.TypeSpec)
+			doc := ts.Doc
+			if doc == nil && len(gen.Specs) == 1 {
+				doc = gen.Doc
+			}
+			directives := tableDirectives(doc)
+			if len(directives) == 0 {
+				continue
+			}
+			st, ok := ts.Type.(*ast.StructType)
+			if !ok {
+				return nil, fmt.Errorf("%s: %s is annotated with a table directive but isn't a struct",
+					fset.Position(ts.Pos()), ts.Name.Name)
+			}
+			e, err := newEntity(fset, ts.Name.Name, st, directives)
+			if err != nil {
+				return nil, err
+			}
+			for _, field := range st.Fields.List {
+				for _, pkg := range referencedPackages(field.Type) {
+					packages[pkg] = true
+				}
+			}
+			entities = append(entities, e)
+		}
+	}
+	if len(entities) == 0 {
+		return nil, fmt.Errorf("%s: no structs are annotated with a table directive", filename)
+	}
+
+	// Imports are grouped as goimports would, with the standard library first
+	stdImports, imports := []string{strconv.Quote("context")}, []string{strconv.Quote("github.com/monzo/gocassa")}
+	for _, imp := range file.Imports {
+		importPath, _ := strconv.Unquote(imp.Path.Value)
+		name := path.Base(importPath)
+		if imp.Name != nil {
+			name = imp.Name.Name
+		}
+		if !packages[name] {
+			continue
+		}
+		spec := strconv.Quote(importPath)
+		if imp.Name != nil {
+			spec = imp.Name.Name + " " + spec
+		}
+		if strings.Contains(strings.Split(importPath, "/")[0], ".") {
+			imports = append(imports, spec)
+		} else {
+			stdImports = append(stdImports, spec)
+		}
+	}
+	sortImports := func(specs []string) {
+		sort.Slice(specs, func(i, j int) bool { return importPath(specs[i]) < importPath(specs[j]) })
+	}
+	sortImports(stdImports)
+	sortImports(imports)
+
+	buf := &bytes.Buffer{}
+	err = fileTemplate.Execute(buf, struct {
+		Source     string
+		Package    string
+		StdImports []string
+		Imports    []string
+		Entities   []entity
+	}{
+		Source:     path.Base(filename),
+		Package:    file.Name.Name,
+		StdImports: stdImports,
+		Imports:    imports,
+		Entities:   entities,
+	})
+	if err != nil {
+		return nil, err
+	}
+	formatted, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %w", err)
+	}
+	return formatted, nil
+}
+
+type directive struct {
+	pos  token.Pos
+	args []string
+}
+
+func tableDirectives(doc *ast.CommentGroup) []directive {
+	if doc == nil {
+		return nil
+	}
+	directives := []directive{}
+	for _, c := range doc.List {
+		if strings.HasPrefix(c.Text, directivePrefix) {
+			directives = append(directives, directive{
+				pos:  c.Pos(),
+				args: strings.Fields(strings.TrimPrefix(c.Text, directivePrefix)),
+			})
+		}
+	}
+	return directives
+}
+
+func newEntity(fset *token.FileSet, name string, st *ast.StructType, directives []directive) (entity, error) {
+	columns, err := structColumns(fset, name, st)
+	if err != nil {
+		return entity{}, err
+	}
+	byName := map[string]column{}
+	for _, c := range columns {
+		byName[c.Name] = c
+	}
+
+	e := entity{Name: name, Columns: columns}
+	keys := map[string]bool{}
+	for _, d := range directives {
+		tbl, err := parseDirective(d.args, byName)
+		if err != nil {
+			return entity{}, fmt.Errorf("%s: %w", fset.Position(d.pos), err)
+		}
+		keys[tbl.Partition.Name] = true
+		if tbl.Clustering.Name != "" {
+			keys[tbl.Clustering.Name] = true
+		}
+		e.Tables = append(e.Tables, tbl)
+	}
+	for _, c := range columns {
+		if !keys[c.Name] {
+			e.Updatable = append(e.Updatable, c)
+		}
+	}
+	return e, nil
+}
+
+// structColumns returns the columns gocassa stores the fields of a struct in. The columns are worked out by the same
+// code gocassa uses at runtime, applied to a struct with the same field names and tags.
+func structColumns(fset *token.FileSet, name string, st *ast.StructType) ([]column, error) {
+	placeholder := reflect.TypeOf((*interface{})(nil)).Elem()
+	fields := []reflect.StructField{}
+	fieldTypes := []string{}
+	for _, field := range st.Fields.List {
+		if len(field.Names) == 0 {
+			return nil, fmt.Errorf("%s: embedded field %s of %s isn't supported",
+				fset.Position(field.Pos()), typeString(field.Type), name)
+		}
+		tag := ""
+		if field.Tag != nil {
+			tag, _ = strconv.Unquote(field.Tag.Value)
+		}
+		for _, ident := range field.Names {
+			if !ident.IsExported() {
+				continue
+			}
+			fields = append(fields, reflect.StructField{
+				Name: ident.Name,
+				Type: placeholder,
+				Tag:  reflect.StructTag(tag),
+			})
+			fieldTypes = append(fieldTypes, typeString(field.Type))
+		}
+	}
+
+	fieldMap, err := gocassareflect.StructFieldMap(reflect.StructOf(fields), false)
+	if err != nil {
+		return nil, err
+	}
+	columns := []column{}
+	for colName, f := range fieldMap {
+		i := f.Index()[0]
+		columns = append(columns, column{
+			Field: fields[i].Name,
+			Name:  colName,
+			Type:  fieldTypes[i],
+			Const: name + "Column" + fields[i].Name,
+			Param: paramName(fields[i].Name),
+		})
+	}
+	index := map[string]int{}
+	for i, f := range fields {
+		index[f.Name] = i
+	}
+	sort.Slice(columns, func(i, j int) bool { return index[columns[i].Field] < index[columns[j].Field] })
+	return columns, nil
+}
+
+func parseDirective(args []string, columns map[string]column) (table, error) {
+	if len(args) < 2 {
+		return table{}, fmt.Errorf("table directive needs a wrapper name and a recipe")
+	}
+	tbl := table{Wrapper: args[0], Recipe: args[1]}
+	if !token.IsIdentifier(tbl.Wrapper) || !ast.IsExported(tbl.Wrapper) {
+		return table{}, fmt.Errorf("wrapper name %q isn't an exported identifier", tbl.Wrapper)
+	}
+
+	opts := map[string]string{}
+	for _, arg := range args[2:] {
+		kv := strings.SplitN(arg, "=", 2)
+		if len(kv) != 2 || kv[1] == "" {
+			return table{}, fmt.Errorf("malformed table option %q, expected key=value", arg)
+		}
+		opts[kv[0]] = kv[1]
+	}
+	allowed := map[string][]string{
+		"map":      {"name", "partition"},
+		"multimap": {"name", "partition", "clustering"},
+	}
+	required, ok := allowed[tbl.Recipe]
+	if !ok {
+		return table{}, fmt.Errorf("unsupported recipe %q, expected map or multimap", tbl.Recipe)
+	}
+	for _, key := range required {
+		if opts[key] == "" {
+			return table{}, fmt.Errorf("%s table %s needs a %s option", tbl.Recipe, tbl.Wrapper, key)
+		}
+	}
+	if len(opts) != len(required) {
+		for key := range opts {
+			if !contains(required, key) {
+				return table{}, fmt.Errorf("unknown option %q for %s table %s", key, tbl.Recipe, tbl.Wrapper)
+			}
+		}
+	}
+
+	tbl.Name = opts["name"]
+	lookup := func(name string) (column, error) {
+		c, ok := columns[name]
+		if !ok {
+			return column{}, fmt.Errorf("table %s is keyed by %s, which isn't a column", tbl.Wrapper, name)
+		}
+		return c, nil
+	}
+	var err error
+	if tbl.Partition, err = lookup(opts["partition"]); err != nil {
+		return table{}, err
+	}
+	if tbl.Recipe == "multimap" {
+		if tbl.Clustering, err = lookup(opts["clustering"]); err != nil {
+			return table{}, err
+		}
+	}
+	return tbl, nil
+}
+
+func contains(ss []string, s string) bool {
+	for _, v := range ss {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// importPath returns the path of an import spec, which may be named
+func importPath(spec string) string {
+	return spec[strings.Index(spec, `"`):]
+}
+
+func typeString(expr ast.Expr) string {
+	return types.ExprString(expr)
+}
+
+// referencedPackages returns the names of the packages a type expression refers to
+func referencedPackages(expr ast.Expr) []string {
+	pkgs := []string{}
+	ast.Inspect(expr, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if ident, ok := sel.X.(*ast.Ident); ok {
+				pkgs = append(pkgs, ident.Name)
+			}
+		}
+		return true
+	})
+	return pkgs
+}
+
+// reservedParams are the names the generated methods use for their own parameters and variables
+var reservedParams = map[string]bool{
+	"ctx": true, "row": true, "rows": true, "limit": true, "update": true, "err": true, "t": true, "u": true, "v": true,
+	"gocassa": true, "context": true,
+}
+
+// paramName converts a field name to a parameter name, eg. "CustomerID" to "customerID" and "ID" to "id"
+func paramName(field string) string {
+	runes := []rune(field)
+	upper := 0
+	for upper < len(runes) && unicode.IsUpper(runes[upper]) {
+		upper++
+	}
+	switch {
+	case upper == len(runes):
+		upper = len(runes)
+	case upper > 1:
+		// Leave the start of the next word capitalised, eg. "URLPath" becomes "urlPath"
+		upper--
+	}
+	for i := 0; i < upper; i++ {
+		runes[i] = unicode.ToLower(runes[i])
+	}
+	name := string(runes)
+	if token.IsKeyword(name) || reservedParams[name] {
+		name += "Value"
+	}
+	return name
+}
+
+var fileTemplate = template.Must(template.New("file").Parse(`// Code generated by gocassa-gen from {{.Source}}. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+{{- range .StdImports}}
+	{{.}}
+{{- end}}
+{{range .Imports}}
+	{{.}}
+{{- end}}
+)
+{{range $e := .Entities}}
+// Column names of the fields of {{$e.Name}}.
+const (
+{{- range $e.Columns}}
+	{{.Const}} = "{{.Name}}"
+{{- end}}
+)
+
+// {{$e.Name}}Update holds the values to update {{$e.Name}} rows with. Fields which are part of the key of any of its
+// tables can't be updated, so have no setter.
+type {{$e.Name}}Update struct {
+	values map[string]interface{}
+}
+
+// New{{$e.Name}}Update returns an empty {{$e.Name}}Update.
+func New{{$e.Name}}Update() *{{$e.Name}}Update {
+	return &{{$e.Name}}Update{values: map[string]interface{}{}}
+}
+{{range $e.Updatable}}
+// Set{{.Field}} sets the {{.Field}} field.
+func (u *{{$e.Name}}Update) Set{{.Field}}(v {{.Type}}) *{{$e.Name}}Update {
+	u.values[{{.Const}}] = v
+	return u
+}
+{{end}}
+// Values returns the columns to update and their new values.
+func (u *{{$e.Name}}Update) Values() map[string]interface{} {
+	return u.values
+}
+{{range $t := $e.Tables}}
+{{- if eq $t.Recipe "map"}}
+// {{$t.Wrapper}} is a typed wrapper of the {{$t.Name}} map table of {{$e.Name}}, keyed by {{$t.Partition.Field}}.
+type {{$t.Wrapper}} struct {
+	table gocassa.MapTable
+}
+
+// New{{$t.Wrapper}} defines the table on the keyspace.
+func New{{$t.Wrapper}}(ks gocassa.KeySpace) {{$t.Wrapper}} {
+	return {{$t.Wrapper}}{table: ks.MapTable("{{$t.Name}}", {{$t.Partition.Const}}, {{$e.Name}}{})}
+}
+
+// Table returns the underlying table.
+func (t {{$t.Wrapper}}) Table() gocassa.MapTable {
+	return t.table
+}
+
+// Set inserts, or replaces, a row.
+func (t {{$t.Wrapper}}) Set(ctx context.Context, row {{$e.Name}}) error {
+	return t.table.Set(row).RunWithContext(ctx)
+}
+
+// Read returns the row with the given key.
+func (t {{$t.Wrapper}}) Read(ctx context.Context, {{$t.Partition.Param}} {{$t.Partition.Type}}) ({{$e.Name}}, error) {
+	row := {{$e.Name}}{}
+	err := t.table.Read({{$t.Partition.Param}}, &row).RunWithContext(ctx)
+	return row, err
+}
+
+// MultiRead returns the rows with the given keys.
+func (t {{$t.Wrapper}}) MultiRead(ctx context.Context, {{$t.Partition.Param}}s ...{{$t.Partition.Type}}) ([]{{$e.Name}}, error) {
+	keys := make([]interface{}, len({{$t.Partition.Param}}s))
+	for i, key := range {{$t.Partition.Param}}s {
+		keys[i] = key
+	}
+	rows := []{{$e.Name}}{}
+	err := t.table.MultiRead(keys, &rows).RunWithContext(ctx)
+	return rows, err
+}
+
+// Update sets the values in update on the row with the given key.
+func (t {{$t.Wrapper}}) Update(ctx context.Context, {{$t.Partition.Param}} {{$t.Partition.Type}}, update *{{$e.Name}}Update) error {
+	return t.table.Update({{$t.Partition.Param}}, update.Values()).RunWithContext(ctx)
+}
+
+// Delete removes the row with the given key.
+func (t {{$t.Wrapper}}) Delete(ctx context.Context, {{$t.Partition.Param}} {{$t.Partition.Type}}) error {
+	return t.table.Delete({{$t.Partition.Param}}).RunWithContext(ctx)
+}
+{{- else}}
+// {{$t.Wrapper}} is a typed wrapper of the {{$t.Name}} multimap table of {{$e.Name}}, partitioned by
+// {{$t.Partition.Field}} and ordered by {{$t.Clustering.Field}}.
+type {{$t.Wrapper}} struct {
+	table gocassa.MultimapTable
+}
+
+// New{{$t.Wrapper}} defines the table on the keyspace.
+func New{{$t.Wrapper}}(ks gocassa.KeySpace) {{$t.Wrapper}} {
+	return {{$t.Wrapper}}{table: ks.MultimapTable("{{$t.Name}}", {{$t.Partition.Const}}, {{$t.Clustering.Const}}, {{$e.Name}}{})}
+}
+
+// Table returns the underlying table.
+func (t {{$t.Wrapper}}) Table() gocassa.MultimapTable {
+	return t.table
+}
+
+// Set inserts, or replaces, a row.
+func (t {{$t.Wrapper}}) Set(ctx context.Context, row {{$e.Name}}) error {
+	return t.table.Set(row).RunWithContext(ctx)
+}
+
+// Read returns the row with the given keys.
+func (t {{$t.Wrapper}}) Read(ctx context.Context, {{$t.Partition.Param}} {{$t.Partition.Type}}, {{$t.Clustering.Param}} {{$t.Clustering.Type}}) ({{$e.Name}}, error) {
+	row := {{$e.Name}}{}
+	err := t.table.Read({{$t.Partition.Param}}, {{$t.Clustering.Param}}, &row).RunWithContext(ctx)
+	return row, err
+}
+
+// List returns up to limit rows of the partition, in clustering order.
+func (t {{$t.Wrapper}}) List(ctx context.Context, {{$t.Partition.Param}} {{$t.Partition.Type}}, limit int) ([]{{$e.Name}}, error) {
+	rows := []{{$e.Name}}{}
+	err := t.table.List({{$t.Partition.Param}}, nil, limit, &rows).RunWithContext(ctx)
+	return rows, err
+}
+
+// Update sets the values in update on the row with the given keys.
+func (t {{$t.Wrapper}}) Update(ctx context.Context, {{$t.Partition.Param}} {{$t.Partition.Type}}, {{$t.Clustering.Param}} {{$t.Clustering.Type}}, update *{{$e.Name}}Update) error {
+	return t.table.Update({{$t.Partition.Param}}, {{$t.Clustering.Param}}, update.Values()).RunWithContext(ctx)
+}
+
+// Delete removes the row with the given keys.
+func (t {{$t.Wrapper}}) Delete(ctx context.Context, {{$t.Partition.Param}} {{$t.Partition.Type}}, {{$t.Clustering.Param}} {{$t.Clustering.Type}}) error {
+	return t.table.Delete({{$t.Partition.Param}}, {{$t.Clustering.Param}}).RunWithContext(ctx)
+}
+
+// DeleteAll removes all the rows of the partition.
+func (t {{$t.Wrapper}}) DeleteAll(ctx context.Context, {{$t.Partition.Param}} {{$t.Partition.Type}}) error {
+	return t.table.DeleteAll({{$t.Partition.Param}}).RunWithContext(ctx)
+}
+{{- end}}
+{{end}}
+{{- end}}`))
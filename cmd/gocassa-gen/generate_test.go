@@ -0,0 +1,97 @@
+package main
+
+import (
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+func TestGenerateGolden(t *testing.T) {
+	inputs, err := filepath.Glob(filepath.Join("testdata", "*.go"))
+	require.NoError(t, err)
+	require.NotEmpty(t, inputs)
+
+	for _, input := range inputs {
+		t.Run(filepath.Base(input), func(t *testing.T) {
+			src, err := ioutil.ReadFile(input)
+			require.NoError(t, err)
+			generated, err := generate(input, src)
+			require.NoError(t, err)
+
+			golden := strings.TrimSuffix(input, ".go") + ".golden"
+			if *update {
+				require.NoError(t, ioutil.WriteFile(golden, generated, 0644))
+			}
+			expected, err := ioutil.ReadFile(golden)
+			require.NoError(t, err)
+			assert.Equal(t, string(expected), string(generated))
+		})
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	cases := map[string]struct {
+		src string
+		err string
+	}{
+		"unknown column": {
+			src: "//gocassa:table SalesByID map name=sales partition=Id\ntype Sale struct {\n\tID string `cql:\"id\"`\n}",
+			err: "table SalesByID is keyed by Id, which isn't a column",
+		},
+		"ignored column": {
+			src: "//gocassa:table SalesByID map name=sales partition=ID\ntype Sale struct {\n\tID string `cql:\"-\"`\n}",
+			err: "table SalesByID is keyed by ID, which isn't a column",
+		},
+		"missing clustering column": {
+			src: "//gocassa:table SalesByID multimap name=sales partition=ID\ntype Sale struct {\n\tID string\n}",
+			err: "multimap table SalesByID needs a clustering option",
+		},
+		"unknown option": {
+			src: "//gocassa:table SalesByID map name=sales partition=ID clustering=ID\ntype Sale struct {\n\tID string\n}",
+			err: `unknown option "clustering" for map table SalesByID`,
+		},
+		"unsupported recipe": {
+			src: "//gocassa:table SalesByID timeseries name=sales partition=ID\ntype Sale struct {\n\tID string\n}",
+			err: `unsupported recipe "timeseries"`,
+		},
+		"unexported wrapper": {
+			src: "//gocassa:table salesByID map name=sales partition=ID\ntype Sale struct {\n\tID string\n}",
+			err: `wrapper name "salesByID" isn't an exported identifier`,
+		},
+		"embedded field": {
+			src: "//gocassa:table SalesByID map name=sales partition=ID\ntype Sale struct {\n\tBase\n\tID string\n}",
+			err: "embedded field Base of Sale isn't supported",
+		},
+		"no directives": {
+			src: "type Sale struct {\n\tID string\n}",
+			err: "no structs are annotated",
+		},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := generate("sales.go", []byte("package sales\n\n"+c.src+"\n"))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), c.err)
+		})
+	}
+}
+
+func TestParamName(t *testing.T) {
+	for field, expected := range map[string]string{
+		"ID":         "id",
+		"CustomerID": "customerID",
+		"URLPath":    "urlPath",
+		"Name":       "name",
+		"Type":       "typeValue",
+		"Limit":      "limitValue",
+	} {
+		assert.Equal(t, expected, paramName(field), field)
+	}
+}
@@ -0,0 +1,60 @@
+// Command gocassa-gen generates strongly typed wrappers of gocassa recipe tables from annotated structs.
+//
+// A struct is annotated with one //gocassa:table directive per table it is stored in. Each directive names the
+// wrapper to generate, the recipe and the table's name and keys, which are given as column names:
+//
+//	//gocassa:table SalesByCustomer multimap name=sales partition=CustomerID clustering=ID
+//	//gocassa:table SalesByID map name=sales partition=ID
+//	type Sale struct {
+//	    ID         string
+//	    CustomerID string
+//	    Price      int
+//	}
+//
+// The map and multimap recipes are supported. For each annotated struct an update builder is generated too, along
+// with constants holding the column name of each field. Run it with go generate:
+//
+//	//go:generate gocassa-gen sales.go
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+func main() {
+	output := flag.String("o", "", "file to write the generated code to (default <input>_gocassa.go)")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: gocassa-gen [-o output] input.go\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	input := flag.Arg(0)
+	if *output == "" {
+		*output = strings.TrimSuffix(input, ".go") + "_gocassa.go"
+	}
+	if err := run(input, *output); err != nil {
+		fmt.Fprintf(os.Stderr, "gocassa-gen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(input, output string) error {
+	src, err := ioutil.ReadFile(input)
+	if err != nil {
+		return err
+	}
+	generated, err := generate(input, src)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(output, generated, 0644)
+}
@@ -0,0 +1,28 @@
+package accounts
+
+import (
+	"strings"
+	stdtime "time"
+)
+
+// Account is stored in a single map table
+//
+//gocassa:table AccountsByID map name=accounts partition=account_id
+type Account struct {
+	AccountID string `cql:"account_id"`
+	Balance   map[string]int64
+	Opened    stdtime.Time
+}
+
+type (
+	//gocassa:table EventsByAccount multimap name=events partition=AccountID clustering=Seq
+	Event struct {
+		AccountID string
+		Seq       int64
+		Type      string
+	}
+)
+
+func normalise(s string) string {
+	return strings.ToLower(s)
+}
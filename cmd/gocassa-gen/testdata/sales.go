@@ -0,0 +1,25 @@
+package sales
+
+import (
+	"time"
+
+	"github.com/gocql/gocql"
+)
+
+//gocassa:table SalesByCustomer multimap name=sales partition=CustomerID clustering=id
+//gocassa:table SalesByID map name=sales partition=id
+type Sale struct {
+	ID         string `cql:"id"`
+	CustomerID string
+	Price      int64
+	Tags       []string
+	Created    time.Time
+	Trace      gocql.UUID
+	Ignored    string `cql:"-"`
+	internal   string
+}
+
+// Customer isn't stored in a table, so nothing is generated for it
+type Customer struct {
+	ID string
+}
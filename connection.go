@@ -2,8 +2,131 @@ package gocassa
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 )
 
+// ReplicationStrategy is the strategy Cassandra uses to place the replicas of a keyspace's data.
+type ReplicationStrategy string
+
+const (
+	// SimpleStrategy places replicas without regard for data centers. It is only suitable for single data center
+	// clusters, such as those used in development.
+	SimpleStrategy ReplicationStrategy = "SimpleStrategy"
+	// NetworkTopologyStrategy places a set number of replicas in each data center.
+	NetworkTopologyStrategy ReplicationStrategy = "NetworkTopologyStrategy"
+)
+
+// KeySpaceOptions configures how a keyspace is replicated.
+type KeySpaceOptions struct {
+	// Strategy is the replication strategy. If empty, NetworkTopologyStrategy is used when DataCenters is set and
+	// SimpleStrategy otherwise
+	Strategy ReplicationStrategy
+	// ReplicationFactor is the number of replicas kept with SimpleStrategy. If zero, one replica is kept
+	ReplicationFactor int
+	// DataCenters is the number of replicas kept in each data center with NetworkTopologyStrategy
+	DataCenters map[string]int
+	// DurableWrites sets whether writes to the keyspace go through the commit log. If nil, Cassandra's default (or,
+	// when altering a keyspace, the current setting) is used
+	DurableWrites *bool
+	// IfNotExists stops an error being returned when creating a keyspace which already exists. It is ignored when
+	// altering a keyspace
+	IfNotExists bool
+}
+
+func (o KeySpaceOptions) strategy() ReplicationStrategy {
+	if o.Strategy != "" {
+		return o.Strategy
+	}
+	if len(o.DataCenters) > 0 {
+		return NetworkTopologyStrategy
+	}
+	return SimpleStrategy
+}
+
+// setsReplication returns whether any of the replication options are set
+func (o KeySpaceOptions) setsReplication() bool {
+	return o.Strategy != "" || o.ReplicationFactor != 0 || len(o.DataCenters) > 0
+}
+
+// replication renders the replication map of the keyspace
+func (o KeySpaceOptions) replication() (string, error) {
+	entries := []string{fmt.Sprintf("'class': '%s'", o.strategy())}
+	switch o.strategy() {
+	case SimpleStrategy:
+		if len(o.DataCenters) > 0 {
+			return "", fmt.Errorf("per data center replication requires %s", NetworkTopologyStrategy)
+		}
+		rf := o.ReplicationFactor
+		if rf == 0 {
+			rf = 1
+		}
+		if rf < 0 {
+			return "", fmt.Errorf("invalid replication factor %d", rf)
+		}
+		entries = append(entries, fmt.Sprintf("'replication_factor': %d", rf))
+	case NetworkTopologyStrategy:
+		if len(o.DataCenters) == 0 {
+			return "", fmt.Errorf("%s requires the replication factor of at least one data center", NetworkTopologyStrategy)
+		}
+		if o.ReplicationFactor != 0 {
+			return "", fmt.Errorf("%s takes a replication factor per data center", NetworkTopologyStrategy)
+		}
+		dcs := make([]string, 0, len(o.DataCenters))
+		for dc := range o.DataCenters {
+			dcs = append(dcs, dc)
+		}
+		sort.Strings(dcs)
+		for _, dc := range dcs {
+			if o.DataCenters[dc] < 0 {
+				return "", fmt.Errorf("invalid replication factor %d for data center %s", o.DataCenters[dc], dc)
+			}
+			entries = append(entries, fmt.Sprintf("%s: %d", cqlStringLiteral(dc), o.DataCenters[dc]))
+		}
+	default:
+		return "", fmt.Errorf("unknown replication strategy %q", o.strategy())
+	}
+	return "{" + strings.Join(entries, ", ") + "}", nil
+}
+
+// KeySpaceStatement returns the statement which creates a keyspace with the given name and options, so it can be
+// reviewed or run manually in cqlsh.
+func KeySpaceStatement(name string, opts KeySpaceOptions) (Statement, error) {
+	replication, err := opts.replication()
+	if err != nil {
+		return nil, fmt.Errorf("can't create keyspace %s: %w", name, err)
+	}
+	createStmt := "CREATE KEYSPACE"
+	if opts.IfNotExists {
+		createStmt = "CREATE KEYSPACE IF NOT EXISTS"
+	}
+	query := fmt.Sprintf("%s %s WITH replication = %s", createStmt, name, replication)
+	if opts.DurableWrites != nil {
+		query += fmt.Sprintf(" AND durable_writes = %t", *opts.DurableWrites)
+	}
+	return cqlStatement{query: query}, nil
+}
+
+// AlterKeySpaceStatement returns the statement which changes the options of an existing keyspace. Replication is
+// only changed if any of the replication options are set.
+func AlterKeySpaceStatement(name string, opts KeySpaceOptions) (Statement, error) {
+	clauses := []string{}
+	if opts.setsReplication() {
+		replication, err := opts.replication()
+		if err != nil {
+			return nil, fmt.Errorf("can't alter keyspace %s: %w", name, err)
+		}
+		clauses = append(clauses, fmt.Sprintf("replication = %s", replication))
+	}
+	if opts.DurableWrites != nil {
+		clauses = append(clauses, fmt.Sprintf("durable_writes = %t", *opts.DurableWrites))
+	}
+	if len(clauses) == 0 {
+		return nil, fmt.Errorf("no keyspace options to alter %s with", name)
+	}
+	return cqlStatement{query: fmt.Sprintf("ALTER KEYSPACE %s WITH %s", name, strings.Join(clauses, " AND "))}, nil
+}
+
 type connection struct {
 	q QueryExecutor
 }
@@ -36,6 +159,24 @@ func (c *connection) CreateKeySpace(name string) error {
 	return c.q.Execute(stmt)
 }
 
+// CreateKeySpaceWithOptions creates a keyspace with the given name, replicated as set out in opts.
+func (c *connection) CreateKeySpaceWithOptions(name string, opts KeySpaceOptions) error {
+	stmt, err := KeySpaceStatement(name, opts)
+	if err != nil {
+		return err
+	}
+	return c.q.Execute(stmt)
+}
+
+// AlterKeySpace changes the replication or durable writes setting of an existing keyspace.
+func (c *connection) AlterKeySpace(name string, opts KeySpaceOptions) error {
+	stmt, err := AlterKeySpaceStatement(name, opts)
+	if err != nil {
+		return err
+	}
+	return c.q.Execute(stmt)
+}
+
 // DropKeySpace drops the keyspace having the given name.
 func (c *connection) DropKeySpace(name string) error {
 	query := fmt.Sprintf("DROP KEYSPACE IF EXISTS %s", name)
@@ -46,8 +187,9 @@ func (c *connection) DropKeySpace(name string) error {
 // KeySpace returns the keyspace having the given name.
 func (c *connection) KeySpace(name string) KeySpace {
 	k := &k{
-		qe:   c.q,
-		name: name,
+		qe:       c.q,
+		name:     name,
+		registry: newTableRegistry(),
 	}
 	k.tableFactory = k
 	return k
@@ -0,0 +1,61 @@
+package gocassa
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeySpaceStatement(t *testing.T) {
+	durable := false
+	cases := []struct {
+		opts     KeySpaceOptions
+		expected string
+	}{
+		{
+			opts:     KeySpaceOptions{},
+			expected: "CREATE KEYSPACE ks WITH replication = {'class': 'SimpleStrategy', 'replication_factor': 1}",
+		},
+		{
+			opts:     KeySpaceOptions{ReplicationFactor: 3, IfNotExists: true},
+			expected: "CREATE KEYSPACE IF NOT EXISTS ks WITH replication = {'class': 'SimpleStrategy', 'replication_factor': 3}",
+		},
+		{
+			opts:     KeySpaceOptions{DataCenters: map[string]int{"eu-west": 3, "eu-central": 2}, DurableWrites: &durable},
+			expected: "CREATE KEYSPACE ks WITH replication = {'class': 'NetworkTopologyStrategy', 'eu-central': 2, 'eu-west': 3} AND durable_writes = false",
+		},
+	}
+	for _, c := range cases {
+		stmt, err := KeySpaceStatement("ks", c.opts)
+		require.NoError(t, err)
+		assert.Equal(t, c.expected, stmt.Query())
+	}
+
+	for _, opts := range []KeySpaceOptions{
+		{Strategy: NetworkTopologyStrategy},
+		{Strategy: NetworkTopologyStrategy, ReplicationFactor: 3, DataCenters: map[string]int{"dc1": 3}},
+		{Strategy: SimpleStrategy, DataCenters: map[string]int{"dc1": 3}},
+		{Strategy: "LocalStrategy"},
+	} {
+		_, err := KeySpaceStatement("ks", opts)
+		assert.Error(t, err, "%+v", opts)
+	}
+}
+
+func TestAlterKeySpace(t *testing.T) {
+	var stmts []Statement
+	conn, ok := NewConnection(funcQE{fn: func(opts Options, stmt Statement) error {
+		stmts = append(stmts, stmt)
+		return nil
+	}}).(KeySpaceAdmin)
+	require.True(t, ok)
+
+	durable := true
+	require.NoError(t, conn.AlterKeySpace("ks", KeySpaceOptions{DataCenters: map[string]int{"dc1": 3}}))
+	require.NoError(t, conn.AlterKeySpace("ks", KeySpaceOptions{DurableWrites: &durable}))
+	assert.Error(t, conn.AlterKeySpace("ks", KeySpaceOptions{}))
+	require.Len(t, stmts, 2)
+	assert.Equal(t, "ALTER KEYSPACE ks WITH replication = {'class': 'NetworkTopologyStrategy', 'dc1': 3}", stmts[0].Query())
+	assert.Equal(t, "ALTER KEYSPACE ks WITH durable_writes = true", stmts[1].Query())
+}
@@ -0,0 +1,150 @@
+package gocassa
+
+import (
+	"fmt"
+	"sort"
+)
+
+type counterT struct {
+	t              Table
+	partitionKeys  []string
+	clusteringKeys []string
+	counterFields  []string
+}
+
+func (o *counterT) Table() Table                        { return o.t }
+func (o *counterT) Create() error                       { return o.Table().Create() }
+func (o *counterT) CreateIfNotExist() error             { return o.Table().CreateIfNotExist() }
+func (o *counterT) Name() string                        { return o.Table().Name() }
+func (o *counterT) Recreate() error                     { return o.Table().Recreate() }
+func (o *counterT) CreateStatement() (Statement, error) { return o.Table().CreateStatement() }
+func (o *counterT) CreateIfNotExistStatement() (Statement, error) {
+	return o.Table().CreateIfNotExistStatement()
+}
+func (o *counterT) AlterOptions() error { return o.Table().AlterOptions() }
+func (o *counterT) AlterOptionsStatement() (Statement, error) {
+	return o.Table().AlterOptionsStatement()
+}
+
+// validateCounterRow checks every column of a counter table's row is either a key or one of its counters, which
+// Cassandra requires of tables with counters
+func validateCounterRow(row map[string]interface{}, partitionKeys, clusteringKeys, counterFields []string) error {
+	keys := map[string]bool{}
+	for _, field := range append(append([]string{}, partitionKeys...), clusteringKeys...) {
+		if _, ok := row[field]; !ok {
+			return fmt.Errorf("key %s is not a field", field)
+		}
+		keys[field] = true
+	}
+	counters := map[string]bool{}
+	for _, field := range counterFields {
+		if keys[field] {
+			return fmt.Errorf("counter %s is also a key", field)
+		}
+		if _, ok := row[field].(Counter); !ok {
+			return fmt.Errorf("counter %s is not a field of type Counter", field)
+		}
+		counters[field] = true
+	}
+
+	fields := make([]string, 0, len(row))
+	for field := range row {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	for _, field := range fields {
+		if !keys[field] && !counters[field] {
+			return fmt.Errorf("field %s is neither a key nor a counter, and a table of counters can't have other columns", field)
+		}
+	}
+	return nil
+}
+
+// counterOp returns an op which is run in a counter batch when it's run as a batch
+func counterOp(op Op) Op {
+	return op.WithOptions(Options{BatchType: CounterBatch})
+}
+
+func (o *counterT) isCounter(field string) bool {
+	for _, f := range o.counterFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// keyRelations returns the relations selecting the rows with the given values of the fields
+func (o *counterT) keyRelations(keys map[string]interface{}, fields ...[]string) ([]Relation, error) {
+	relations := []Relation{}
+	for _, fs := range fields {
+		for _, field := range fs {
+			value, ok := keys[field]
+			if !ok {
+				return nil, fmt.Errorf("missing value for key %s", field)
+			}
+			relations = append(relations, Eq(field, value))
+		}
+	}
+	return relations, nil
+}
+
+func (o *counterT) Increment(keys map[string]interface{}, field string, delta int) Op {
+	return o.IncrementMany(keys, map[string]int{field: delta})
+}
+
+func (o *counterT) IncrementMany(keys map[string]interface{}, deltas map[string]int) Op {
+	relations, err := o.keyRelations(keys, o.partitionKeys, o.clusteringKeys)
+	if err != nil {
+		return errOp{err: err}
+	}
+	m := make(map[string]interface{}, len(deltas))
+	for field, delta := range deltas {
+		if !o.isCounter(field) {
+			return errOp{err: fmt.Errorf("%s is not a counter of %s", field, o.Name())}
+		}
+		m[field] = CounterIncrement(delta)
+	}
+	return counterOp(o.Table().
+		Where(relations...).
+		Update(m))
+}
+
+func (o *counterT) Read(keys map[string]interface{}, pointer interface{}) Op {
+	relations, err := o.keyRelations(keys, o.partitionKeys, o.clusteringKeys)
+	if err != nil {
+		return errOp{err: err}
+	}
+	return o.Table().
+		Where(relations...).
+		ReadOne(pointer)
+}
+
+func (o *counterT) List(partitionKeys map[string]interface{}, pointerToASlice interface{}) Op {
+	relations, err := o.keyRelations(partitionKeys, o.partitionKeys)
+	if err != nil {
+		return errOp{err: err}
+	}
+	return o.Table().
+		Where(relations...).
+		Read(pointerToASlice)
+}
+
+func (o *counterT) Reset(keys map[string]interface{}) Op {
+	relations, err := o.keyRelations(keys, o.partitionKeys, o.clusteringKeys)
+	if err != nil {
+		return errOp{err: err}
+	}
+	return counterOp(o.Table().
+		Where(relations...).
+		Delete())
+}
+
+func (o *counterT) WithOptions(opt Options) CounterTable {
+	return &counterT{
+		t:              o.Table().WithOptions(opt),
+		partitionKeys:  o.partitionKeys,
+		clusteringKeys: o.clusteringKeys,
+		counterFields:  o.counterFields,
+	}
+}
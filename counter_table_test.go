@@ -0,0 +1,71 @@
+package gocassa
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type pageViews struct {
+	Site   string
+	Page   string
+	Views  Counter
+	Unique Counter
+}
+
+func TestCounterTableValidation(t *testing.T) {
+	ks := NewMockKeySpace()
+	require.NotPanics(t, func() {
+		ks.CounterTable("views", []string{"Site"}, []string{"Page"}, []string{"Views", "Unique"}, pageViews{})
+	})
+	require.PanicsWithValue(t, "Invalid counter table views: field Unique is neither a key nor a counter, and a table of counters can't have other columns", func() {
+		ks.CounterTable("views", []string{"Site"}, []string{"Page"}, []string{"Views"}, pageViews{})
+	})
+	require.PanicsWithValue(t, "Invalid counter table views: counter Name is not a field of type Counter", func() {
+		ks.CounterTable("views", []string{"Id"}, nil, []string{"Name"}, Customer{})
+	})
+	require.PanicsWithValue(t, "Invalid counter table views: key Id is not a field", func() {
+		ks.CounterTable("views", []string{"Id"}, nil, []string{"Views", "Unique"}, pageViews{})
+	})
+}
+
+func TestCounterTableStatements(t *testing.T) {
+	var stmts []Statement
+	var opts []Options
+	qe := funcQE{fn: func(o Options, stmt Statement) error {
+		stmts, opts = append(stmts, stmt), append(opts, o)
+		return nil
+	}}
+	tbl := NewConnection(qe).KeySpace("ks").CounterTable("views", []string{"Site"}, []string{"Page"}, []string{"Views", "Unique"}, pageViews{})
+
+	keys := map[string]interface{}{"Site": "monzo.com", "Page": "/"}
+	require.NoError(t, tbl.Increment(keys, "Views", 1).Run())
+	require.Equal(t, "UPDATE ks.views_counter SET Views = Views + ? WHERE site = ? AND page = ?", stmts[0].Query())
+	require.Equal(t, []interface{}{1, "monzo.com", "/"}, stmts[0].Values())
+
+	// Ops on the table are batched as counter updates
+	stmts, opts = nil, nil
+	other := map[string]interface{}{"Site": "monzo.com", "Page": "/about"}
+	op := tbl.Increment(keys, "Views", 1).Add(tbl.Reset(other))
+	require.NoError(t, op.RunLoggedBatchWithContext(context.Background()))
+	require.Equal(t, CounterBatch, opts[0].BatchType)
+	require.Equal(t, "BEGIN COUNTER BATCH\nUPDATE ks.views_counter SET Views = Views + ? WHERE site = ? AND page = ?;\nDELETE FROM ks.views_counter WHERE site = ? AND page = ?;\nAPPLY BATCH", stmts[0].Query())
+
+	// Counter updates can't be batched with other writes, which a counter batch can't hold
+	stmts = nil
+	customers := NewConnection(qe).KeySpace("ks").MapTable("customers", "Id", Customer{})
+	op = tbl.Increment(keys, "Views", 1).Add(customers.Set(Customer{Id: "1", Name: "Joe"}))
+	require.Equal(t, errMixedCounterBatch, op.RunLoggedBatchWithContext(context.Background()))
+	op = customers.Set(Customer{Id: "1", Name: "Joe"}).Add(tbl.Increment(keys, "Views", 1))
+	require.Equal(t, errMixedCounterBatch, op.RunAtomically())
+	require.Empty(t, stmts)
+
+	mock := NewMockKeySpace()
+	mockViews := mock.CounterTable("views", []string{"Site"}, []string{"Page"}, []string{"Views", "Unique"}, pageViews{})
+	op = mockViews.Increment(keys, "Views", 1).Add(mock.MapTable("customers", "Id", Customer{}).Set(Customer{Id: "1"}))
+	require.Equal(t, errMixedCounterBatch, op.RunAtomically())
+
+	require.EqualError(t, tbl.Increment(keys, "Page", 1).Run(), "Page is not a counter of views_counter")
+	require.EqualError(t, tbl.Increment(map[string]interface{}{"Site": "monzo.com"}, "Views", 1).Run(), "missing value for key Page")
+}
@@ -2,8 +2,11 @@ package gocassa
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
 	"strings"
+	"time"
 )
 
 // RowNotFoundError is returned by Reads if the Row is not found.
@@ -21,6 +24,135 @@ func (r RowNotFoundError) Error() string {
 	return fmt.Sprintf("%v:%v: No rows returned", f, r.line)
 }
 
+// TimeoutError is returned when a statement doesn't complete within the Timeout set in its Options, or within its
+// share of a TimeoutBudget.
+type TimeoutError struct {
+	// Statement is the statement which timed out
+	Statement Statement
+	// Timeout is how long the statement was allowed to run for, or the whole TimeoutBudget if it had been used up
+	// before the statement started
+	Timeout time.Duration
+	err     error
+}
+
+func (e TimeoutError) Error() string {
+	return fmt.Sprintf("statement timed out after %v: %s", e.Timeout, e.Statement.Query())
+}
+
+// Unwrap returns the error returned by the QueryExecutor when the statement's context expired.
+func (e TimeoutError) Unwrap() error {
+	return e.err
+}
+
+// ConditionFailedError is returned by conditional writes, such as SetIfNotExists, UpdateIf and DeleteIf, whose
+// condition didn't hold and so which weren't applied.
+type ConditionFailedError struct {
+	// Statement is the conditional statement which wasn't applied
+	Statement Statement
+	// Current holds the columns of the row the condition was checked against, keyed by their lower case names as
+	// Cassandra returns them. It's empty if there is no row.
+	Current map[string]interface{}
+}
+
+func (e ConditionFailedError) Error() string {
+	return fmt.Sprintf("condition not met: %s", e.Statement.Query())
+}
+
+// ConcurrentModificationError is returned by the writes of a table whose rows have a version field when the stored
+// version isn't the one the write was based on, because the row has been written since it was read.
+type ConcurrentModificationError struct {
+	// Expected is the version the write was based on
+	Expected int64
+	// Current is the stored version, or 0 if there's no row
+	Current int64
+}
+
+func (e ConcurrentModificationError) Error() string {
+	return fmt.Sprintf("row was modified concurrently: expected version %d, found %d", e.Expected, e.Current)
+}
+
+// UniqueViolationError is returned by a UniqueIndex when a value is already claimed by another owner.
+type UniqueViolationError struct {
+	// Value is the value which was claimed
+	Value string
+	// Owner is the ID of the entity holding the value
+	Owner string
+}
+
+func (e UniqueViolationError) Error() string {
+	return fmt.Sprintf("%q is already claimed by %s", e.Value, e.Owner)
+}
+
+// ErrLeaseNotHeld is returned by a LeaseTable when no one holds a lease, or when an owner renews a lease which it
+// doesn't hold any more.
+var ErrLeaseNotHeld = errors.New("lease is not held")
+
+// ErrMessageClaimed is returned by a QueueTable when a message is claimed by a consumer whose claim hasn't expired.
+var ErrMessageClaimed = errors.New("message is claimed by another consumer")
+
+// ErrMessageNotFound is returned by a QueueTable when a message doesn't exist, for example because it's been acked.
+var ErrMessageNotFound = errors.New("message not found")
+
+// ErrValueNotFound is returned by the loader of a CacheTable when there's no value for a key. The cache remembers it
+// for its negative TTL, returning it from Get without calling the loader again.
+var ErrValueNotFound = errors.New("value not found")
+
+// ErrNotCached is returned by a CacheTable when a key has no entry.
+var ErrNotCached = errors.New("key is not cached")
+
+// LeaseHeldError is returned by a LeaseTable when a lease is held by another owner.
+type LeaseHeldError struct {
+	// Lease is the lease as it's held
+	Lease Lease
+}
+
+func (e LeaseHeldError) Error() string {
+	return fmt.Sprintf("lease %s is held by %s until %v", e.Lease.Name, e.Lease.Owner, e.Lease.Expires)
+}
+
+// PartialResultError is returned by reads which found no row for some of the keys they were asked for. The rows which
+// were found are still populated.
+type PartialResultError struct {
+	// Missing holds the keys for which no row was found, in the order they were requested
+	Missing []interface{}
+}
+
+func (e PartialResultError) Error() string {
+	return fmt.Sprintf("no rows found for %d of the requested keys: %v", len(e.Missing), e.Missing)
+}
+
+// MultiError is returned by RunConcurrentlyWithContext when any of the operations it runs fail. It maps the position
+// of each failed operation within the Op to the error it returned.
+type MultiError map[int]error
+
+func (e MultiError) Error() string {
+	idxs := e.Indexes()
+	msgs := make([]string, len(idxs))
+	for i, idx := range idxs {
+		msgs[i] = fmt.Sprintf("op %d: %v", idx, e[idx])
+	}
+	return fmt.Sprintf("%d operation(s) failed: %s", len(e), strings.Join(msgs, "; "))
+}
+
+// Indexes returns the positions of the failed operations in ascending order.
+func (e MultiError) Indexes() []int {
+	idxs := make([]int, 0, len(e))
+	for idx := range e {
+		idxs = append(idxs, idx)
+	}
+	sort.Ints(idxs)
+	return idxs
+}
+
+// Unwrap returns the errors of the failed operations, ordered by their position.
+func (e MultiError) Unwrap() []error {
+	errs := make([]error, 0, len(e))
+	for _, idx := range e.Indexes() {
+		errs = append(errs, e[idx])
+	}
+	return errs
+}
+
 // errOp is an Op which represents a known error, which will always return during preflighting (preventing any execution
 // in a multiOp scenario)
 type errOp struct{ err error }
@@ -36,3 +168,7 @@ func (o errOp) WithOptions(_ Options) Op                          { return o }
 func (o errOp) Preflight() error                                  { return o.err }
 func (o errOp) GenerateStatement() Statement                      { return noOpStatement{} }
 func (o errOp) QueryExecutor() QueryExecutor                      { return nil }
+
+func (o errOp) RunConcurrentlyWithContext(_ context.Context, _ int) error {
+	return o.err
+}
@@ -21,6 +21,14 @@ func (f filter) Delete() Op {
 	return newWriteOp(f.t.keySpace.qe, f, deleteOpType, nil)
 }
 
+func (f filter) UpdateIf(m map[string]interface{}, conditions ...Relation) Op {
+	return newConditionalWriteOp(f.t.keySpace.qe, f, updateOpType, m, conditions)
+}
+
+func (f filter) DeleteIf(conditions ...Relation) Op {
+	return newConditionalWriteOp(f.t.keySpace.qe, f, deleteOpType, nil, conditions)
+}
+
 //
 // Reads
 //
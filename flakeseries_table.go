@@ -1,14 +1,8 @@
 package gocassa
 
 import (
-	"errors"
 	"fmt"
-	"strings"
 	"time"
-
-	"github.com/mattheath/base62"
-	"github.com/mattheath/kala/bigflake"
-	"github.com/mattheath/kala/util"
 )
 
 // we have to put the timestamp from the flake ID into a field so that we can
@@ -16,9 +10,11 @@ import (
 const flakeTimestampFieldName = "flake_created"
 
 type flakeSeriesT struct {
-	t          Table
-	idField    string
-	bucketSize time.Duration
+	t       Table
+	idField string
+	buckets BucketStrategy
+	decoder IDTimeDecoder
+	options Options
 }
 
 func (o *flakeSeriesT) Table() Table                        { return o.t }
@@ -30,6 +26,10 @@ func (o *flakeSeriesT) CreateStatement() (Statement, error) { return o.Table().C
 func (o *flakeSeriesT) CreateIfNotExistStatement() (Statement, error) {
 	return o.Table().CreateIfNotExistStatement()
 }
+func (o *flakeSeriesT) AlterOptions() error { return o.Table().AlterOptions() }
+func (o *flakeSeriesT) AlterOptionsStatement() (Statement, error) {
+	return o.Table().AlterOptionsStatement()
+}
 
 func (o *flakeSeriesT) Set(v interface{}) Op {
 	m, ok := toMap(v)
@@ -41,23 +41,23 @@ func (o *flakeSeriesT) Set(v interface{}) Op {
 		panic(fmt.Sprintf("Id field (%s) is not present or is not a string", o.idField))
 	}
 
-	timestamp, err := flakeToTime(id)
+	timestamp, err := idTime(o.decoder, id)
 	if err != nil {
 		return errOp{err: err}
 	}
 
 	m[flakeTimestampFieldName] = timestamp
-	m[bucketFieldName] = bucket(timestamp, o.bucketSize)
+	m[bucketFieldName] = o.buckets.Bucket(timestamp)
 
 	return o.Table().Set(m)
 }
 
 func (o *flakeSeriesT) Update(id string, m map[string]interface{}) Op {
-	timestamp, err := flakeToTime(id)
+	timestamp, err := idTime(o.decoder, id)
 	if err != nil {
 		return errOp{err: err}
 	}
-	bucket := bucket(timestamp, o.bucketSize)
+	bucket := o.buckets.Bucket(timestamp)
 
 	return o.Table().
 		Where(Eq(bucketFieldName, bucket),
@@ -67,11 +67,11 @@ func (o *flakeSeriesT) Update(id string, m map[string]interface{}) Op {
 }
 
 func (o *flakeSeriesT) Delete(id string) Op {
-	timestamp, err := flakeToTime(id)
+	timestamp, err := idTime(o.decoder, id)
 	if err != nil {
 		return errOp{err: err}
 	}
-	bucket := bucket(timestamp, o.bucketSize)
+	bucket := o.buckets.Bucket(timestamp)
 
 	return o.Table().
 		Where(Eq(bucketFieldName, bucket),
@@ -81,11 +81,11 @@ func (o *flakeSeriesT) Delete(id string) Op {
 }
 
 func (o *flakeSeriesT) Read(id string, pointer interface{}) Op {
-	timestamp, err := flakeToTime(id)
+	timestamp, err := idTime(o.decoder, id)
 	if err != nil {
 		return errOp{err: err}
 	}
-	bucket := bucket(timestamp, o.bucketSize)
+	bucket := o.buckets.Bucket(timestamp)
 	return o.Table().
 		Where(Eq(bucketFieldName, bucket),
 			Eq(flakeTimestampFieldName, timestamp),
@@ -98,66 +98,105 @@ func (o *flakeSeriesT) List(startTime, endTime time.Time, pointerToASlice interf
 	for bucket := o.Buckets(startTime); bucket.Bucket().Before(endTime); bucket = bucket.Next() {
 		buckets = append(buckets, bucket.Bucket())
 	}
-	return o.Table().
+	read := o.Table().
 		Where(In(bucketFieldName, buckets...),
 			GTE(flakeTimestampFieldName, startTime),
 			LT(flakeTimestampFieldName, endTime)).
 		Read(pointerToASlice)
+	return mergedSeriesRead(read, o.options, flakeTimestampFieldName, o.order(), pointerToASlice)
+}
+
+func (o *flakeSeriesT) ListWithLimit(startTime, endTime time.Time, opts SeriesListOptions, pointerToASlice interface{}) Op {
+	order := o.order()
+	return seriesWalk{
+		buckets:      o.Buckets,
+		timeField:    flakeTimestampFieldName,
+		idFields:     []string{o.idField},
+		start:        startTime,
+		end:          endTime,
+		endExclusive: true,
+		idOrder:      &order,
+	}.list(opts, pointerToASlice)
+}
+
+func (o *flakeSeriesT) NextPage(opts SeriesListOptions, pointerToASlice interface{}) (SeriesListOptions, bool) {
+	return nextSeriesPage(opts, pointerToASlice, o.order())
 }
 
 func (o *flakeSeriesT) Buckets(start time.Time) Buckets {
 	return bucketIter{
 		v:         start,
-		step:      o.bucketSize,
+		strategy:  o.buckets,
 		field:     bucketFieldName,
 		invariant: o.Table().Where()}
 }
 
 func (o *flakeSeriesT) ListSince(id string, window time.Duration, pointerToASlice interface{}) Op {
-	startTime, err := flakeToTime(id)
+	startTime, err := idTime(o.decoder, id)
 	if err != nil {
 		return errOp{err: err}
 	}
 
-	var endTime time.Time
-	if window == 0 {
-		// no window set - so go up until 5 mins in the future
-		endTime = time.Now().Add(5 * time.Minute)
-	} else {
-		endTime = startTime.Add(window)
+	return seriesReadSince(o.Table(), o.options, window, func(now time.Time) Op {
+		var endTime time.Time
+		if window == 0 {
+			// no window set - so go up until 5 mins in the future
+			endTime = now.Add(5 * time.Minute)
+		} else {
+			endTime = startTime.Add(window)
+		}
+
+		buckets := []interface{}{}
+		for bucket := o.Buckets(startTime); bucket.Bucket().Before(endTime); bucket = bucket.Next() {
+			buckets = append(buckets, bucket.Bucket())
+		}
+
+		read := o.Table().
+			Where(In(bucketFieldName, buckets...),
+				GTE(flakeTimestampFieldName, startTime),
+				LT(flakeTimestampFieldName, endTime)).
+			Read(pointerToASlice)
+		// The rows after id at the same time are selected by the order of the IDs' format, which Cassandra doesn't know
+		read = mergedSeriesRead(read, o.options, flakeTimestampFieldName, o.order(), pointerToASlice)
+		return seriesRowsPast(read, o.order(), SeriesCursor{Time: startTime, ID: id}, ASC, pointerToASlice)
+	})
+}
+
+func (o *flakeSeriesT) ListBefore(id string, window time.Duration, pointerToASlice interface{}) Op {
+	endTime, err := idTime(o.decoder, id)
+	if err != nil {
+		return errOp{err: err}
+	}
+	if window <= 0 {
+		return errOp{err: errNoWindow}
 	}
+	startTime := endTime.Add(-window)
 
 	buckets := []interface{}{}
-	for bucket := o.Buckets(startTime); bucket.Bucket().Before(endTime); bucket = bucket.Next() {
+	for bucket := o.Buckets(startTime); !bucket.Bucket().After(endTime); bucket = bucket.Next() {
 		buckets = append(buckets, bucket.Bucket())
 	}
 
-	return o.Table().
-		Where(In(bucketFieldName, buckets),
+	read := o.Table().
+		Where(In(bucketFieldName, buckets...),
 			GTE(flakeTimestampFieldName, startTime),
-			LT(flakeTimestampFieldName, endTime),
-			GT(o.idField, id)).
+			LTE(flakeTimestampFieldName, endTime)).
 		Read(pointerToASlice)
+	// The rows before id at the same time are selected by the order of the IDs' format, which Cassandra doesn't know
+	read = mergedSeriesRead(read, o.options, flakeTimestampFieldName, o.order(), pointerToASlice)
+	return seriesRowsPast(read, o.order(), SeriesCursor{Time: endTime, ID: id}, DESC, pointerToASlice)
 }
 
-func (o *flakeSeriesT) WithOptions(opt Options) FlakeSeriesTable {
-	return &flakeSeriesT{
-		t:          o.Table().WithOptions(opt),
-		idField:    o.idField,
-		bucketSize: o.bucketSize}
+func (o *flakeSeriesT) order() seriesOrder {
+	return flakeSeriesOrder(o.idField, o.decoder)
 }
 
-func flakeToTime(id string) (time.Time, error) {
-	parts := strings.Split(id, "_")
-
-	if len(parts) < 2 {
-		return time.Time{}, errors.New("Invalid flake id")
+func (o *flakeSeriesT) WithOptions(opt Options) FlakeSeriesTable {
+	return &flakeSeriesT{
+		t:       o.Table().WithOptions(opt),
+		idField: o.idField,
+		buckets: o.buckets,
+		decoder: o.decoder,
+		options: o.options.Merge(opt),
 	}
-
-	intId := base62.DecodeToBigInt(parts[len(parts)-1])
-
-	msTime, _, _ := bigflake.ParseId(intId)
-	timestamp := util.MsInt64ToTime(msTime)
-
-	return timestamp, nil
 }
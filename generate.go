@@ -28,15 +28,15 @@ import (
 // );
 //
 
-func createTableIfNotExist(keySpace, cf string, partitionKeys, colKeys []string, fields []string, values []interface{}, order []ClusteringOrderColumn, compoundKey, compact bool, compressor string) (Statement, error) {
-	return createTableStmt("CREATE TABLE IF NOT EXISTS", keySpace, cf, partitionKeys, colKeys, fields, values, order, compoundKey, compact, compressor)
+func createTableIfNotExist(keySpace, cf string, partitionKeys, colKeys []string, fields []string, values []interface{}, order []ClusteringOrderColumn, compoundKey, compact bool, compressor string, tableOptions *TableOptions) (Statement, error) {
+	return createTableStmt("CREATE TABLE IF NOT EXISTS", keySpace, cf, partitionKeys, colKeys, fields, values, order, compoundKey, compact, compressor, tableOptions)
 }
 
-func createTable(keySpace, cf string, partitionKeys, colKeys []string, fields []string, values []interface{}, order []ClusteringOrderColumn, compoundKey, compact bool, compressor string) (Statement, error) {
-	return createTableStmt("CREATE TABLE", keySpace, cf, partitionKeys, colKeys, fields, values, order, compoundKey, compact, compressor)
+func createTable(keySpace, cf string, partitionKeys, colKeys []string, fields []string, values []interface{}, order []ClusteringOrderColumn, compoundKey, compact bool, compressor string, tableOptions *TableOptions) (Statement, error) {
+	return createTableStmt("CREATE TABLE", keySpace, cf, partitionKeys, colKeys, fields, values, order, compoundKey, compact, compressor, tableOptions)
 }
 
-func createTableStmt(createStmt, keySpace, cf string, partitionKeys, colKeys []string, fields []string, values []interface{}, order []ClusteringOrderColumn, compoundKey, compact bool, compressor string) (Statement, error) {
+func createTableStmt(createStmt, keySpace, cf string, partitionKeys, colKeys []string, fields []string, values []interface{}, order []ClusteringOrderColumn, compoundKey, compact bool, compressor string, tableOptions *TableOptions) (Statement, error) {
 	firstLine := fmt.Sprintf("%s %v.%v (", createStmt, keySpace, cf)
 	fieldLines := []string{}
 	for i, _ := range fields {
@@ -65,32 +65,31 @@ func createTableStmt(createStmt, keySpace, cf string, partitionKeys, colKeys []s
 		")",
 	}
 
+	props := []string{}
 	if len(order) > 0 {
 		orderStrs := make([]string, len(order))
 		for i, o := range order {
 			orderStrs[i] = fmt.Sprintf("%v %v", strings.ToLower(o.Column), o.Direction.String())
 		}
-		orderLine := fmt.Sprintf("WITH CLUSTERING ORDER BY (%v)", strings.Join(orderStrs, ", "))
-		lines = append(lines, orderLine)
+		props = append(props, fmt.Sprintf("CLUSTERING ORDER BY (%v)", strings.Join(orderStrs, ", ")))
 	}
 
 	if compact {
-		compactLineStart := "WITH"
-		if len(order) > 0 {
-			compactLineStart = "AND"
-		}
-		compactLine := fmt.Sprintf("%v COMPACT STORAGE", compactLineStart)
-		lines = append(lines, compactLine)
+		props = append(props, "COMPACT STORAGE")
+	}
+
+	// The legacy compressor is only used if the table options don't configure compression themselves
+	if len(compressor) > 0 && (tableOptions == nil || tableOptions.Compression.empty()) {
+		props = append(props, fmt.Sprintf("compression = {'sstable_compression': '%v'}", compressor))
 	}
 
-	if len(compressor) > 0 {
-		compressionLineStart := "WITH"
-		if len(order) > 0 || compact {
-			compressionLineStart = "AND"
+	if tableOptions != nil {
+		if err := tableOptions.validate(); err != nil {
+			return nil, err
 		}
-		compressionLine := fmt.Sprintf("%v compression = {'sstable_compression': '%v'}", compressionLineStart, compressor)
-		lines = append(lines, compressionLine)
+		props = append(props, tableOptions.properties()...)
 	}
+	lines = append(lines, withClauses(props)...)
 
 	lines = append(lines, ";")
 	qry := strings.Join(lines, "\n")
@@ -44,6 +44,19 @@ func (cb goCQLBackend) ExecuteWithOptions(opts Options, stmt Statement) error {
 	return qu.Exec()
 }
 
+func (cb goCQLBackend) ExecuteConditionallyWithOptions(opts Options, stmt Statement) (bool, map[string]interface{}, error) {
+	qu := cb.session.Query(stmt.Query(), stmt.Values()...)
+	if opts.Consistency != nil {
+		qu = qu.Consistency(*opts.Consistency)
+	}
+	if opts.Context != nil {
+		qu = qu.WithContext(opts.Context)
+	}
+	current := map[string]interface{}{}
+	applied, err := qu.MapScanCAS(current)
+	return applied, current, err
+}
+
 func (cb goCQLBackend) ExecuteAtomically(stmts []Statement) error {
 	return cb.ExecuteAtomicallyWithOptions(Options{}, stmts)
 }
@@ -52,7 +65,28 @@ func (cb goCQLBackend) ExecuteAtomicallyWithOptions(opts Options, stmts []Statem
 	if len(stmts) == 0 {
 		return nil
 	}
-	batch := cb.session.NewBatch(gocql.LoggedBatch)
+	return cb.session.ExecuteBatch(cb.batch(opts, stmts))
+}
+
+func (cb goCQLBackend) ExecuteBatchConditionallyWithOptions(opts Options, stmts []Statement) (bool, map[string]interface{}, error) {
+	current := map[string]interface{}{}
+	applied, iter, err := cb.session.MapExecuteBatchCAS(cb.batch(opts, stmts), current)
+	if err != nil {
+		return false, nil, err
+	}
+	return applied, current, iter.Close()
+}
+
+// batch returns a gocql batch of the statements
+func (cb goCQLBackend) batch(opts Options, stmts []Statement) *gocql.Batch {
+	batchType := gocql.LoggedBatch
+	switch opts.BatchType {
+	case UnloggedBatch:
+		batchType = gocql.UnloggedBatch
+	case CounterBatch:
+		batchType = gocql.CounterBatch
+	}
+	batch := cb.session.NewBatch(batchType)
 	for i := range stmts {
 		stmt := stmts[i]
 		batch.Query(stmt.Query(), stmt.Values()...)
@@ -64,8 +98,7 @@ func (cb goCQLBackend) ExecuteAtomicallyWithOptions(opts Options, stmts []Statem
 	if opts.Context != nil {
 		batch = batch.WithContext(opts.Context)
 	}
-
-	return cb.session.ExecuteBatch(batch)
+	return batch
 }
 
 // GoCQLSessionToQueryExecutor enables you to supply your own gocql session with your custom options
@@ -0,0 +1,233 @@
+package gocassa
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/mattheath/base62"
+	"github.com/mattheath/kala/bigflake"
+	"github.com/mattheath/kala/util"
+)
+
+// IDTimeDecoder reads the time an ID was generated at from the ID itself, which is how a FlakeSeriesTable or
+// MultiFlakeSeriesTable buckets and orders its rows. As it decides which bucket each row is stored in, a table's
+// decoder is passed to the WithBuckets variant of its recipe, and is BigflakeIDs if it's nil.
+//
+// The decoders in this package read IDs with or without a prefix ending in an underscore, such as "user_".
+type IDTimeDecoder interface {
+	// IDTime returns the time the ID was generated at
+	IDTime(id string) (time.Time, error)
+	// CompareIDs returns -1, 0 or 1 as ID a sorts before, the same as or after ID b in the format's own order, which
+	// breaks ties between IDs generated at the same time
+	CompareIDs(a, b string) int
+}
+
+// idPart returns an ID without its prefix
+func idPart(id string) string {
+	return id[strings.LastIndex(id, "_")+1:]
+}
+
+// BigflakeIDs returns the decoder of the "prefix_base62" bigflake IDs generated by github.com/mattheath/kala, which
+// must have a prefix.
+func BigflakeIDs() IDTimeDecoder {
+	return bigflakeIDs{}
+}
+
+type bigflakeIDs struct{}
+
+func (bigflakeIDs) IDTime(id string) (time.Time, error) {
+	return flakeToTime(id)
+}
+
+func (bigflakeIDs) CompareIDs(a, b string) int {
+	return base62.DecodeToBigInt(idPart(a)).Cmp(base62.DecodeToBigInt(idPart(b)))
+}
+
+func flakeToTime(id string) (time.Time, error) {
+	parts := strings.Split(id, "_")
+
+	if len(parts) < 2 {
+		return time.Time{}, errors.New("Invalid flake id")
+	}
+
+	intId := base62.DecodeToBigInt(parts[len(parts)-1])
+
+	msTime, _, _ := bigflake.ParseId(intId)
+	timestamp := util.MsInt64ToTime(msTime)
+
+	return timestamp, nil
+}
+
+const crockfordBase32 = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// ULIDs returns the decoder of ULIDs, whose first 48 bits are the milliseconds since the Unix epoch they were
+// generated at.
+func ULIDs() IDTimeDecoder {
+	return ulids{}
+}
+
+type ulids struct{}
+
+func (ulids) IDTime(id string) (time.Time, error) {
+	s := strings.ToUpper(idPart(id))
+	if len(s) != 26 || s[0] > '7' {
+		return time.Time{}, fmt.Errorf("invalid ULID %q", id)
+	}
+	var ms int64
+	for _, c := range s[:10] {
+		digit := strings.IndexRune(crockfordBase32, c)
+		if digit < 0 {
+			return time.Time{}, fmt.Errorf("invalid ULID %q", id)
+		}
+		ms = ms<<5 | int64(digit)
+	}
+	return time.UnixMilli(ms), nil
+}
+
+// CompareIDs compares ULIDs as strings, as their encoding sorts in the same order as their bits
+func (ulids) CompareIDs(a, b string) int {
+	return strings.Compare(strings.ToUpper(idPart(a)), strings.ToUpper(idPart(b)))
+}
+
+const (
+	base62Digits = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+	// ksuidEpoch is the Unix time KSUID timestamps count seconds from
+	ksuidEpoch = 1400000000
+)
+
+// KSUIDs returns the decoder of KSUIDs, whose first 32 bits are the seconds since 2014-05-13T16:53:20Z they were
+// generated at.
+func KSUIDs() IDTimeDecoder {
+	return ksuids{}
+}
+
+type ksuids struct{}
+
+func (ksuids) IDTime(id string) (time.Time, error) {
+	s := idPart(id)
+	if len(s) != 27 {
+		return time.Time{}, fmt.Errorf("invalid KSUID %q", id)
+	}
+	value := new(big.Int)
+	for _, c := range s {
+		digit := strings.IndexRune(base62Digits, c)
+		if digit < 0 {
+			return time.Time{}, fmt.Errorf("invalid KSUID %q", id)
+		}
+		value.Mul(value, big.NewInt(62)).Add(value, big.NewInt(int64(digit)))
+	}
+	// A KSUID is 160 bits: a 32 bit timestamp followed by 128 bits of payload
+	if value.BitLen() > 160 {
+		return time.Time{}, fmt.Errorf("invalid KSUID %q", id)
+	}
+	return time.Unix(ksuidEpoch+value.Rsh(value, 128).Int64(), 0), nil
+}
+
+// CompareIDs compares KSUIDs as strings, as their fixed length encoding sorts in the same order as their bits
+func (ksuids) CompareIDs(a, b string) int {
+	return strings.Compare(idPart(a), idPart(b))
+}
+
+// SnowflakeIDs returns the decoder of decimal snowflake IDs, whose bits above the lowest 22 are the milliseconds
+// since epoch they were generated at.
+func SnowflakeIDs(epoch time.Time) IDTimeDecoder {
+	return snowflakeIDs{epoch: epoch}
+}
+
+// TwitterSnowflakeIDs returns the decoder of snowflake IDs counting from Twitter's epoch, 2010-11-04T01:42:54.657Z.
+func TwitterSnowflakeIDs() IDTimeDecoder {
+	return SnowflakeIDs(time.UnixMilli(1288834974657))
+}
+
+type snowflakeIDs struct {
+	epoch time.Time
+}
+
+func (d snowflakeIDs) IDTime(id string) (time.Time, error) {
+	n, err := strconv.ParseUint(idPart(id), 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid snowflake ID %q: %v", id, err)
+	}
+	return d.epoch.Add(time.Duration(n>>22) * time.Millisecond), nil
+}
+
+// CompareIDs compares snowflake IDs by their values, as their decimal strings don't sort in order once they differ
+// in length
+func (snowflakeIDs) CompareIDs(a, b string) int {
+	x, errA := strconv.ParseUint(idPart(a), 10, 64)
+	y, errB := strconv.ParseUint(idPart(b), 10, 64)
+	switch {
+	case errA != nil || errB != nil:
+		return strings.Compare(a, b)
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	}
+	return 0
+}
+
+// TimeUUIDs returns the decoder of version 1 UUIDs, as generated by Cassandra's now() and gocql.TimeUUID.
+func TimeUUIDs() IDTimeDecoder {
+	return timeUUIDs{}
+}
+
+type timeUUIDs struct{}
+
+func (timeUUIDs) parse(id string) (gocql.UUID, error) {
+	u, err := gocql.ParseUUID(idPart(id))
+	if err != nil {
+		return u, fmt.Errorf("invalid TimeUUID %q: %v", id, err)
+	}
+	if u.Version() != 1 {
+		return u, fmt.Errorf("invalid TimeUUID %q: version %d UUIDs don't hold a time", id, u.Version())
+	}
+	return u, nil
+}
+
+func (d timeUUIDs) IDTime(id string) (time.Time, error) {
+	u, err := d.parse(id)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return u.Time(), nil
+}
+
+// CompareIDs compares TimeUUIDs by their timestamps and then their clock sequences and nodes, as their strings start
+// with the lowest bits of the timestamp
+func (d timeUUIDs) CompareIDs(a, b string) int {
+	x, errA := d.parse(a)
+	y, errB := d.parse(b)
+	switch {
+	case errA != nil || errB != nil:
+		return strings.Compare(a, b)
+	case x.Timestamp() < y.Timestamp():
+		return -1
+	case x.Timestamp() > y.Timestamp():
+		return 1
+	}
+	return bytes.Compare(x[8:], y[8:])
+}
+
+// idTimeDecoder returns the decoder of the IDs of a flake series, which is BigflakeIDs unless one is given
+func idTimeDecoder(decoder IDTimeDecoder) IDTimeDecoder {
+	if decoder != nil {
+		return decoder
+	}
+	return BigflakeIDs()
+}
+
+// idTime returns the time of an ID as it's stored in the flake_created column, which holds milliseconds
+func idTime(decoder IDTimeDecoder, id string) (time.Time, error) {
+	timestamp, err := decoder.IDTime(id)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return timestamp.Truncate(time.Millisecond), nil
+}
@@ -0,0 +1,123 @@
+package gocassa
+
+import (
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// timeUUID returns a version 1 UUID with the timestamp ts, counted in 100ns intervals since 1582-10-15
+func timeUUID(ts uint64) string {
+	var u gocql.UUID
+	binary.BigEndian.PutUint32(u[0:], uint32(ts))
+	binary.BigEndian.PutUint16(u[4:], uint16(ts>>32))
+	binary.BigEndian.PutUint16(u[6:], uint16(ts>>48)&0x0fff|0x1000)
+	u[8] = 0x80
+	return u.String()
+}
+
+func TestIDTimeDecoders(t *testing.T) {
+	now := time.Date(2020, 3, 4, 5, 6, 7, 8e6, time.UTC)
+	for _, tc := range []struct {
+		name    string
+		decoder IDTimeDecoder
+		id      string
+		time    time.Time
+	}{
+		{"bigflake", BigflakeIDs(), timeToFlake(t, "2006 Jan 2 15:04:00"), parse("2006 Jan 2 15:04:00")},
+		{"ULID", ULIDs(), "01ARZ3NDEKTSV4RRFFQ69G5FAV", time.UnixMilli(1469922850259)},
+		{"prefixed ULID", ULIDs(), "user_01arz3ndektsv4rrffq69g5fav", time.UnixMilli(1469922850259)},
+		{"KSUID", KSUIDs(), "0ujtsYcgvSTl8PAuAdqWYSMnLOv", time.Unix(1507608047, 0)},
+		{"snowflake", TwitterSnowflakeIDs(), "1541815603606036480", time.UnixMilli(1656432460105)},
+		{"TimeUUID", TimeUUIDs(), gocql.UUIDFromTime(now).String(), now},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ts, err := tc.decoder.IDTime(tc.id)
+			require.NoError(t, err)
+			assert.True(t, tc.time.Equal(ts), "expected %v, got %v", tc.time, ts)
+		})
+	}
+
+	for _, tc := range []struct {
+		decoder IDTimeDecoder
+		id      string
+	}{
+		{BigflakeIDs(), "unprefixed"},
+		{ULIDs(), "01ARZ3NDEK"},
+		{ULIDs(), "81ARZ3NDEKTSV4RRFFQ69G5FAV"},
+		{KSUIDs(), "0ujtsYcgvSTl8PAuAdqWYSMnLO-"},
+		{TwitterSnowflakeIDs(), "-1"},
+		{TimeUUIDs(), gocql.UUID{}.String()},
+	} {
+		_, err := tc.decoder.IDTime(tc.id)
+		assert.Error(t, err, tc.id)
+	}
+}
+
+func TestIDTimeDecodersCompareIDs(t *testing.T) {
+	// Decimal strings of different lengths don't sort by their values
+	assert.Equal(t, -1, TwitterSnowflakeIDs().CompareIDs("999", "1000"))
+	assert.Equal(t, 0, TwitterSnowflakeIDs().CompareIDs("1000", "1000"))
+	assert.Equal(t, 1, ULIDs().CompareIDs("01ARZ3NDEKTSV4RRFFQ69G5FAW", "01arz3ndektsv4rrffq69g5fav"))
+
+	// The string of a TimeUUID starts with the lowest bits of its timestamp
+	before, after := timeUUID(32180000<<32-1), timeUUID(32180000<<32)
+	require.True(t, after < before)
+	assert.Equal(t, -1, TimeUUIDs().CompareIDs(before, after))
+	assert.Equal(t, 1, TimeUUIDs().CompareIDs(after, before))
+}
+
+func TestMockFlakeSeriesIDTimeDecoder(t *testing.T) {
+	tbl := NewMockKeySpace().FlakeSeriesTableWithBuckets("trips", "Id", FixedBuckets(time.Minute), TimeUUIDs(), Trip{})
+
+	// Both IDs are from the same millisecond, but sort the other way round as strings
+	before, after := timeUUID(32180001<<32-1), timeUUID(32180001<<32)
+	earlier := timeUUID(32180001<<32 - 1e7)
+	for _, id := range []string{after, earlier, before} {
+		require.NoError(t, tbl.Set(Trip{Id: id}).Run())
+	}
+
+	var trip Trip
+	require.NoError(t, tbl.Read(before, &trip).Run())
+	assert.Equal(t, before, trip.Id)
+
+	start, err := TimeUUIDs().IDTime(earlier)
+	require.NoError(t, err)
+	start = start.Truncate(time.Millisecond)
+	var trips []Trip
+	require.NoError(t, tbl.List(start, start.Add(time.Minute), &trips).Run())
+	assert.Equal(t, []Trip{{Id: earlier}, {Id: before}, {Id: after}}, trips)
+
+	trips = nil
+	require.NoError(t, tbl.ListSince(before, time.Minute, &trips).Run())
+	assert.Equal(t, []Trip{{Id: after}}, trips)
+
+	trips = nil
+	require.NoError(t, tbl.ListSince(earlier, time.Minute, &trips).Run())
+	assert.Equal(t, []Trip{{Id: before}, {Id: after}}, trips)
+
+	// Pages are in the same order as List, even when they split the rows at the same time
+	for _, order := range []ColumnDirection{ASC, DESC} {
+		var paged []Trip
+		opts := SeriesListOptions{Limit: 1, Order: order}
+		for i := 0; i < 4; i++ {
+			var page []Trip
+			require.NoError(t, tbl.ListWithLimit(start, start.Add(time.Minute), opts, &page).Run())
+			paged = append(paged, page...)
+			next, ok := tbl.NextPage(opts, &page)
+			if !ok {
+				break
+			}
+			opts = next
+		}
+		expected := []Trip{{Id: earlier}, {Id: before}, {Id: after}}
+		if order == DESC {
+			expected = []Trip{{Id: after}, {Id: before}, {Id: earlier}}
+		}
+		assert.Equal(t, expected, paged, "order %v", order)
+	}
+}
@@ -0,0 +1,240 @@
+package gocassa
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// EntityIndexTable is a recipe table an IndexedEntity can keep copies of the entity in, keyed by one of its fields.
+// It's either a MultimapTable (sharded or not) partitioned by the field and clustered by the entity's ID, or a
+// MapTable keyed by the field. As a MapTable holds one entity for each value of the field, the values of the field
+// must be unique among the entities, which a UniqueIndex can ensure.
+type EntityIndexTable interface {
+	Set(rowStruct interface{}) Op
+	Table() Table
+}
+
+// EntityIndex is a secondary view of an IndexedEntity: a recipe table holding copies of the entity keyed by one of its
+// fields.
+type EntityIndex struct {
+	// Name identifies the index to ReadBy
+	Name string
+	// Field is the field of the entity the index is keyed by
+	Field string
+	Table EntityIndexTable
+}
+
+// indexTable holds the ops of an index's table which an IndexedEntity uses, whichever recipe the table is
+type indexTable struct {
+	set    func(row map[string]interface{}) Op
+	delete func(key, id interface{}) Op
+	read   func(key, pointerToASlice interface{}) Op
+}
+
+// newIndexTable returns the ops of the index's table. Only the version of the entity in the primary table is checked,
+// so the indexes are written with plain writes even if their rows have a version field.
+func newIndexTable(index EntityIndex) indexTable {
+	switch tbl := index.Table.(type) {
+	case MultimapTable:
+		if u, ok := tbl.(unversionedMultimap); ok {
+			tbl = u.unversioned()
+		}
+		return indexTable{
+			set:    func(row map[string]interface{}) Op { return tbl.Set(row) },
+			delete: tbl.Delete,
+			read: func(key, pointerToASlice interface{}) Op {
+				return tbl.List(key, nil, 0, pointerToASlice)
+			},
+		}
+	case MapTable:
+		if u, ok := tbl.(unversionedMap); ok {
+			tbl = u.unversioned()
+		}
+		return indexTable{
+			set:    func(row map[string]interface{}) Op { return tbl.Set(row) },
+			delete: func(key, id interface{}) Op { return tbl.Delete(key) },
+			read: func(key, pointerToASlice interface{}) Op {
+				return tbl.MultiRead([]interface{}{key}, pointerToASlice)
+			},
+		}
+	}
+	panic(fmt.Sprintf("Index %s must be a MultimapTable or a MapTable, not %T", index.Name, index.Table))
+}
+
+type indexedEntity struct {
+	primary MapTable
+	idField string
+	rowType reflect.Type
+	indexes []EntityIndex
+	tables  []indexTable
+	// versionField is the version field of the entity, if it has one
+	versionField string
+}
+
+// NewIndexedEntity returns an IndexedEntity stored in the primary MapTable, keyed by idField, and in the indexes. The
+// rowDefinition is the type the stored version of an entity is read into when it's changed.
+func NewIndexedEntity(primary MapTable, idField string, rowDefinition interface{}, indexes ...EntityIndex) IndexedEntity {
+	rowType := structType(rowDefinition)
+	seen := map[string]bool{}
+	tables := make([]indexTable, len(indexes))
+	for i, index := range indexes {
+		if seen[index.Name] {
+			panic(fmt.Sprintf("Index %s is defined more than once", index.Name))
+		}
+		seen[index.Name] = true
+		tables[i] = newIndexTable(index)
+	}
+	return &indexedEntity{
+		primary:      primary,
+		idField:      idField,
+		rowType:      rowType,
+		indexes:      indexes,
+		tables:       tables,
+		versionField: versionField(rowDefinition),
+	}
+}
+
+func (e *indexedEntity) Primary() MapTable {
+	return e.primary
+}
+
+// previous reads the stored version of the entity with the given ID, returning nil if there isn't one
+func (e *indexedEntity) previous(opts Options, id interface{}) (map[string]interface{}, error) {
+	return readMapRow(e.primary, opts, id, e.rowType)
+}
+
+// structType returns the struct type of rowDefinition, which may be a pointer to it
+func structType(rowDefinition interface{}) reflect.Type {
+	rowType := reflect.TypeOf(rowDefinition)
+	for rowType != nil && rowType.Kind() == reflect.Ptr {
+		rowType = rowType.Elem()
+	}
+	if rowType == nil || rowType.Kind() != reflect.Struct {
+		panic("Unrecognized row type")
+	}
+	return rowType
+}
+
+// readMapRow reads the row of tbl with the given key into a new value of rowType, returning it as a map or nil if
+// there isn't one
+func readMapRow(tbl MapTable, opts Options, id interface{}, rowType reflect.Type) (map[string]interface{}, error) {
+	row := reflect.New(rowType)
+	if err := tbl.Read(id, row.Interface()).WithOptions(opts).Run(); err != nil {
+		if _, ok := err.(RowNotFoundError); ok {
+			return nil, nil
+		}
+		return nil, err
+	}
+	m, ok := toMap(row.Interface())
+	if !ok {
+		return nil, fmt.Errorf("can't convert %v to a map", row.Type())
+	}
+	return m, nil
+}
+
+func (e *indexedEntity) Set(row interface{}) Op {
+	m, ok := toMap(row)
+	if !ok {
+		panic("Can't set: not able to convert")
+	}
+	id, ok := m[e.idField]
+	if !ok {
+		panic(fmt.Sprintf("Id field (%s) is not present", e.idField))
+	}
+
+	qe := e.primary.Set(row).QueryExecutor()
+	return newLazyOp(qe, func(opts Options) error {
+		prev, err := e.previous(opts, id)
+		if err != nil {
+			return err
+		}
+
+		indexRow, err := e.indexRow(m)
+		if err != nil {
+			return err
+		}
+		ops := []Op{e.primary.Set(row)}
+		for i, index := range e.indexes {
+			key := m[index.Field]
+			if prevKey := prev[index.Field]; !isUnsetKey(prevKey) && !reflect.DeepEqual(prevKey, key) {
+				ops = append(ops, e.tables[i].delete(prevKey, id))
+			}
+			if !isUnsetKey(key) {
+				ops = append(ops, e.tables[i].set(indexRow))
+			}
+		}
+		return runEntityWrites(ops, opts)
+	})
+}
+
+// isUnsetKey returns whether the value of an indexed field is unset (nil or ""), in which case the entity has no row in
+// the index, just as ListOfEqualRelations leaves unset fields out of a query
+func isUnsetKey(key interface{}) bool {
+	return key == nil || key == ""
+}
+
+// indexRow returns the copy of the entity m written to the indexes, which holds the version the primary table's
+// write stores if the entity is versioned
+func (e *indexedEntity) indexRow(m map[string]interface{}) (map[string]interface{}, error) {
+	if e.versionField == "" {
+		return m, nil
+	}
+	next, _, err := nextVersion(m[e.versionField])
+	if err != nil {
+		return nil, err
+	}
+	row := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		row[k] = v
+	}
+	row[e.versionField] = next
+	return row, nil
+}
+
+// runEntityWrites runs the writes to the primary table and indexes, the first of which is the write to the primary
+// table, as a batch. A versioned write to the primary table can't be batched with writes to other tables, so it's
+// made on its own first and the indexes are only written once it's been applied.
+func runEntityWrites(ops []Op, opts Options) error {
+	primary := ops[0].WithOptions(opts)
+	if isConditional(primary.GenerateStatement()) {
+		if err := primary.Run(); err != nil {
+			return err
+		}
+		ops = ops[1:]
+	}
+	if len(ops) == 0 {
+		return nil
+	}
+	return ops[0].Add(ops[1:]...).WithOptions(opts).RunAtomically()
+}
+
+func (e *indexedEntity) Delete(id interface{}) Op {
+	qe := e.primary.Delete(id).QueryExecutor()
+	return newLazyOp(qe, func(opts Options) error {
+		prev, err := e.previous(opts, id)
+		if err != nil || prev == nil {
+			return err
+		}
+
+		op := e.primary.Delete(id)
+		for i, index := range e.indexes {
+			if key := prev[index.Field]; !isUnsetKey(key) {
+				op = op.Add(e.tables[i].delete(key, id))
+			}
+		}
+		return op.WithOptions(opts).RunAtomically()
+	})
+}
+
+func (e *indexedEntity) Read(id, pointer interface{}) Op {
+	return e.primary.Read(id, pointer)
+}
+
+func (e *indexedEntity) ReadBy(index string, key, pointerToASlice interface{}) Op {
+	for i, ei := range e.indexes {
+		if ei.Name == index {
+			return e.tables[i].read(key, pointerToASlice)
+		}
+	}
+	return errOp{err: fmt.Errorf("no index named %s", index)}
+}
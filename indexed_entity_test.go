@@ -0,0 +1,167 @@
+package gocassa
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type member struct {
+	Id    string
+	Email string
+	Team  string
+}
+
+func newMemberEntity(ks KeySpace) IndexedEntity {
+	return NewIndexedEntity(ks.MapTable("members", "Id", member{}), "Id", member{},
+		EntityIndex{Name: "email", Field: "Email", Table: ks.MultimapTable("members", "Email", "Id", member{})},
+		EntityIndex{Name: "team", Field: "Team", Table: ks.MultimapTable("members", "Team", "Id", member{})},
+	)
+}
+
+func TestMockIndexedEntity(t *testing.T) {
+	members := newMemberEntity(NewMockKeySpace())
+
+	require.NoError(t, members.Set(member{Id: "1", Email: "joe@example.com", Team: "payments"}).Run())
+	require.NoError(t, members.Set(member{Id: "2", Email: "jane@example.com", Team: "payments"}).Run())
+
+	var res []member
+	require.NoError(t, members.ReadBy("team", "payments", &res).Run())
+	assert.Equal(t, []member{
+		{Id: "1", Email: "joe@example.com", Team: "payments"},
+		{Id: "2", Email: "jane@example.com", Team: "payments"},
+	}, res)
+
+	// Changing an indexed field moves the entity between partitions of the index
+	require.NoError(t, members.Set(member{Id: "1", Email: "joe@example.org", Team: "payments"}).Run())
+	res = nil
+	require.NoError(t, members.ReadBy("email", "joe@example.com", &res).Run())
+	assert.Empty(t, res)
+	require.NoError(t, members.ReadBy("email", "joe@example.org", &res).Run())
+	assert.Equal(t, []member{{Id: "1", Email: "joe@example.org", Team: "payments"}}, res)
+
+	res = nil
+	require.NoError(t, members.ReadBy("team", "payments", &res).Run())
+	assert.Equal(t, []member{
+		{Id: "1", Email: "joe@example.org", Team: "payments"},
+		{Id: "2", Email: "jane@example.com", Team: "payments"},
+	}, res)
+
+	// Deleting the entity removes it from every index
+	require.NoError(t, members.Delete("1").Run())
+	var m member
+	assert.IsType(t, RowNotFoundError{}, members.Read("1", &m).Run())
+	res = nil
+	require.NoError(t, members.ReadBy("email", "joe@example.org", &res).Run())
+	assert.Empty(t, res)
+	require.NoError(t, members.ReadBy("team", "payments", &res).Run())
+	assert.Equal(t, []member{{Id: "2", Email: "jane@example.com", Team: "payments"}}, res)
+
+	// Deleting an entity which doesn't exist does nothing
+	require.NoError(t, members.Delete("1").Run())
+
+	// As they read before they write, the ops can't be added to a batch
+	assert.Equal(t, errLazyBatch, members.Set(member{Id: "3"}).Add(members.Delete("2")).RunAtomically())
+	require.NoError(t, members.Read("2", &m).Run())
+
+	assert.EqualError(t, members.ReadBy("name", "Joe", &res).Run(), "no index named name")
+}
+
+func TestIndexedEntityStatements(t *testing.T) {
+	var stmts []Statement
+	var opts []Options
+	prev := []map[string]interface{}{{"Id": "1", "Email": "joe@example.com", "Team": "payments"}}
+	qe := funcQE{
+		fn: func(o Options, stmt Statement) error {
+			stmts, opts = append(stmts, stmt), append(opts, o)
+			return nil
+		},
+		query: func(stmt Statement, scanner Scanner) error {
+			_, err := scanner.ScanIter(newMockIterator(prev, stmt.(SelectStatement).fields))
+			return err
+		},
+	}
+	members := newMemberEntity(NewConnection(qe).KeySpace("ks"))
+
+	// The changes to every table are written in a single logged batch
+	require.NoError(t, members.Set(member{Id: "1", Email: "joe@example.org", Team: "payments"}).Run())
+	require.Len(t, stmts, 1)
+	assert.Equal(t, LoggedBatch, opts[0].BatchType)
+	assert.Equal(t, "BEGIN BATCH\n"+
+		"UPDATE ks.members_map_Id SET email = ?, team = ? WHERE id = ?;\n"+
+		"DELETE FROM ks.members_multimap_Email_Id WHERE email = ? AND id = ?;\n"+
+		"UPDATE ks.members_multimap_Email_Id SET team = ? WHERE email = ? AND id = ?;\n"+
+		"UPDATE ks.members_multimap_Team_Id SET email = ? WHERE team = ? AND id = ?;\n"+
+		"APPLY BATCH", stmts[0].Query())
+
+	stmts, opts = nil, nil
+	require.NoError(t, members.Delete("1").Run())
+	require.Len(t, stmts, 1)
+	assert.Equal(t, "BEGIN BATCH\n"+
+		"DELETE FROM ks.members_map_Id WHERE id = ?;\n"+
+		"DELETE FROM ks.members_multimap_Email_Id WHERE email = ? AND id = ?;\n"+
+		"DELETE FROM ks.members_multimap_Team_Id WHERE team = ? AND id = ?;\n"+
+		"APPLY BATCH", stmts[0].Query())
+	assert.Equal(t, []interface{}{"1", "joe@example.com", "1", "payments", "1"}, stmts[0].Values())
+
+	assert.Panics(t, func() {
+		NewIndexedEntity(members.Primary(), "Id", member{},
+			EntityIndex{Name: "email", Field: "Email"}, EntityIndex{Name: "email", Field: "Email"})
+	})
+}
+
+func TestMockIndexedEntityTables(t *testing.T) {
+	ks := NewMockKeySpace()
+	members := NewIndexedEntity(ks.MapTable("members", "Id", member{}), "Id", member{},
+		EntityIndex{Name: "email", Field: "Email", Table: ks.MapTable("members", "Email", member{})},
+		EntityIndex{Name: "team", Field: "Team", Table: ks.ShardedMultimapTable("members", "Team", "Id", 4, member{})},
+	)
+
+	require.NoError(t, members.Set(member{Id: "1", Email: "joe@example.com", Team: "payments"}).Run())
+	require.NoError(t, members.Set(member{Id: "2", Email: "jane@example.com", Team: "payments"}).Run())
+	require.NoError(t, members.Set(member{Id: "1", Email: "joe@example.org", Team: "payments"}).Run())
+
+	var res []member
+	require.NoError(t, members.ReadBy("email", "joe@example.org", &res).Run())
+	assert.Equal(t, []member{{Id: "1", Email: "joe@example.org", Team: "payments"}}, res)
+	require.NoError(t, members.ReadBy("email", "joe@example.com", &res).Run())
+	assert.Empty(t, res)
+	require.NoError(t, members.ReadBy("team", "payments", &res).Run())
+	assert.ElementsMatch(t, []member{
+		{Id: "1", Email: "joe@example.org", Team: "payments"},
+		{Id: "2", Email: "jane@example.com", Team: "payments"},
+	}, res)
+
+	require.NoError(t, members.Delete("1").Run())
+	require.NoError(t, members.ReadBy("email", "joe@example.org", &res).Run())
+	assert.Empty(t, res)
+	require.NoError(t, members.ReadBy("team", "payments", &res).Run())
+	assert.Equal(t, []member{{Id: "2", Email: "jane@example.com", Team: "payments"}}, res)
+
+	assert.PanicsWithValue(t, "Index team must be a MultimapTable or a MapTable, not *gocassa.multimapMkT", func() {
+		NewIndexedEntity(members.Primary(), "Id", member{},
+			EntityIndex{Name: "team", Field: "Team", Table: ks.MultimapMultiKeyTable("members", []string{"Team"}, []string{"Id"}, member{})})
+	})
+}
+
+func TestIndexedEntityUnsetFields(t *testing.T) {
+	members := newMemberEntity(NewMockKeySpace())
+
+	// An entity whose indexed field is empty has no row in that index
+	require.NoError(t, members.Set(member{Id: "1", Team: "payments"}).Run())
+	var res []member
+	require.NoError(t, members.ReadBy("email", "", &res).Run())
+	assert.Empty(t, res)
+
+	require.NoError(t, members.Set(member{Id: "1", Email: "joe@example.com", Team: "payments"}).Run())
+	require.NoError(t, members.ReadBy("email", "joe@example.com", &res).Run())
+	assert.Len(t, res, 1)
+
+	// Clearing the field removes its row
+	require.NoError(t, members.Set(member{Id: "1", Team: "payments"}).Run())
+	require.NoError(t, members.ReadBy("email", "joe@example.com", &res).Run())
+	assert.Empty(t, res)
+	require.NoError(t, members.ReadBy("email", "", &res).Run())
+	assert.Empty(t, res)
+}
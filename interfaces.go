@@ -13,6 +13,17 @@ type Connection interface {
 	KeySpace(name string) KeySpace
 }
 
+// KeySpaceAdmin manages the replication of keyspaces. The Connections returned by this package, and MockConnection,
+// implement it, so a Connection can be type asserted to it. It isn't part of Connection so that other implementations
+// of Connection don't have to implement it.
+type KeySpaceAdmin interface {
+	// CreateKeySpaceWithOptions creates a keyspace replicated as set out in the options. Use KeySpaceStatement to
+	// review the CQL which is run.
+	CreateKeySpaceWithOptions(name string, opts KeySpaceOptions) error
+	// AlterKeySpace changes the replication or durable writes setting of an existing keyspace
+	AlterKeySpace(name string, opts KeySpaceOptions) error
+}
+
 // KeySpace is used to obtain tables from.
 type KeySpace interface {
 	// MapTable is a simple key-value store.
@@ -23,6 +34,16 @@ type KeySpace interface {
 		The ordering within a partition is determined using the clusteringKey.
 	*/
 	MultimapTable(prefixForTableName, partitionKey, clusteringKey string, rowDefinition interface{}) MultimapTable
+	/*
+		ShardedMultimapTable is a MultimapTable whose partitions are split into shards, for partitions which grow too
+		big for a single Cassandra partition. A row's shard is a hash of its clustering key, stored in a shard column
+		which is part of the partition key. Lists read every shard of a partition concurrently and merge the rows in
+		order. The number of shards is part of the table's name, as the shards of its rows would change with it, so
+		resharding means copying partitions to a table with the new number of shards. Integer clustering keys find the
+		same shard whatever their Go type, but keys of other types must be passed as the Go type they were written as.
+		CopyMultimapPartition copies partitions from an unsharded table or one with a different number of shards.
+	*/
+	ShardedMultimapTable(prefixForTableName, partitionKey, clusteringKey string, shards int, rowDefinition interface{}) MultimapTable
 	/*
 		MultimapMultiKeyTable lets you list rows based on several fields equality but allows for more than one partitionKey.
 		It uses the partitionKeys for partitioning the data.
@@ -57,6 +78,45 @@ type KeySpace interface {
 	*/
 	FlakeSeriesTable(prefixForTableName, flakeIDField string, bucketSize time.Duration, rowDefinition interface{}) FlakeSeriesTable
 	MultiFlakeSeriesTable(prefixForTableName, partitionKey, flakeIDField string, bucketSize time.Duration, rowDefinition interface{}) MultiFlakeSeriesTable
+	/*
+		MultiKeyFlakeSeriesTable is a cross between FlakeSeries and MultimapMultikey tables.
+		The partitionKeys and the bucket field make up the composite partitionKey.
+		bucketSize is used to determine for what duration the data will be stored on the same partition.
+	*/
+	MultiKeyFlakeSeriesTable(prefixForTableName string, partitionKeys []string, flakeIDField string, bucketSize time.Duration, rowDefinition interface{}) MultiKeyFlakeSeriesTable
+	/*
+		The WithBuckets variants of the time based recipes store the data in the buckets of a BucketStrategy, such as
+		calendar months, rather than buckets of a fixed duration. The strategy's name is part of the table's name, so
+		FixedBuckets(bucketSize) gives the same table as passing bucketSize.
+	*/
+	TimeSeriesTableWithBuckets(prefixForTableName, timeField, clusteringKey string, buckets BucketStrategy, rowDefinition interface{}) TimeSeriesTable
+	MultiTimeSeriesTableWithBuckets(prefixForTableName, partitionKey, timeField, clusteringKey string, buckets BucketStrategy, rowDefinition interface{}) MultiTimeSeriesTable
+	MultiKeyTimeSeriesTableWithBuckets(prefixForTableName string, partitionKeys []string, timeField string, clusteringKeys []string, buckets BucketStrategy, rowDefinition interface{}) MultiKeyTimeSeriesTable
+	/*
+		The flake series variants also take the IDTimeDecoder reading the times of the table's IDs, which decides
+		the buckets its rows are stored in. If it's nil the IDs are read as bigflakes.
+	*/
+	FlakeSeriesTableWithBuckets(prefixForTableName, flakeIDField string, buckets BucketStrategy, decoder IDTimeDecoder, rowDefinition interface{}) FlakeSeriesTable
+	MultiFlakeSeriesTableWithBuckets(prefixForTableName, partitionKey, flakeIDField string, buckets BucketStrategy, decoder IDTimeDecoder, rowDefinition interface{}) MultiFlakeSeriesTable
+	MultiKeyFlakeSeriesTableWithBuckets(prefixForTableName string, partitionKeys []string, flakeIDField string, buckets BucketStrategy, decoder IDTimeDecoder, rowDefinition interface{}) MultiKeyFlakeSeriesTable
+	/*
+		CounterTable holds counters, which are addressed by the values of the partitionKeys and clusteringKeys.
+		Every other field of the row must be one of the counterFields, of type Counter.
+	*/
+	CounterTable(prefixForTableName string, partitionKeys, clusteringKeys, counterFields []string, rowDefinition interface{}) CounterTable
+	/*
+		UniqueIndex keeps the values of uniqueField unique among the rows of the primary table, which is keyed by
+		idField. Values are claimed in a lookup table of their own. Both fields must be strings.
+	*/
+	UniqueIndex(prefixForTableName string, primary MapTable, idField, uniqueField string, rowDefinition interface{}) UniqueIndex
+	// LeaseTable holds leases, which are held by one owner at a time until they expire.
+	LeaseTable(prefixForTableName string) LeaseTable
+	// QueueTable holds a queue of messages split into shards, whose messages are stored in the buckets of a
+	// MultiTimeSeriesTable.
+	QueueTable(prefixForTableName string, buckets BucketStrategy) QueueTable
+	// CacheTable is a read-through cache, whose entries expire after ttl. The loader finding no value for a key is
+	// cached for negativeTTL, or not at all if it's zero. TTLs are at least a second.
+	CacheTable(prefixForTableName string, ttl, negativeTTL time.Duration) CacheTable
 	Table(prefixForTableName string, rowDefinition interface{}, keys Keys) Table
 	// DebugMode enables/disables debug mode depending on the value of the input boolean.
 	// When DebugMode is enabled, all built CQL statements are printe to stdout.
@@ -67,6 +127,28 @@ type KeySpace interface {
 	Tables() ([]string, error)
 	// Exists returns whether the specified column family exists within the keyspace
 	Exists(string) (bool, error)
+	// TableMetadata returns the columns, primary key, indexes and options of the specified column family. If it
+	// doesn't exist, a TableNotFoundError is returned
+	TableMetadata(string) (TableMetadata, error)
+	// RegisteredTables returns the tables defined on this keyspace, ordered by name. Only the tables created by the
+	// keyspace's constructors are registered, unless a copy of one is registered with RegisterTable
+	RegisteredTables() []Table
+	// RegisterTable adds a copy of a table made with WithOptions, such as one with another name or table options, to
+	// the tables defined on this keyspace. It replaces any definition of a table with the same name and keys. For a
+	// recipe, register its Table()
+	RegisterTable(Table)
+	// Migrator returns a Migrator which brings the live schema of the tables defined on this keyspace in line with
+	// their definitions
+	Migrator() *Migrator
+	// CreateAllIfNotExist creates every table defined on this keyspace which doesn't exist already. If two tables
+	// resolve to the same name but have different keys, a TableConflictError is returned and nothing is created
+	CreateAllIfNotExist(ctx context.Context) error
+	// DropAll drops every table defined on this keyspace
+	DropAll() error
+	// SchemaCQL returns a CQL script creating every table defined on this keyspace, ordered by table name, so the
+	// schema can be reviewed. If two tables resolve to the same name but have different keys, a TableConflictError
+	// is returned
+	SchemaCQL() (string, error)
 }
 
 //
@@ -74,6 +156,12 @@ type KeySpace interface {
 //
 
 // MapTable gives you basic CRUD functionality. If you need fancier ways to query your data set have a look at the other tables.
+//
+// If the row type has a field tagged with the "version" option, such as `cql:"version,version"`, Set and Update are
+// compare-and-set on it: they're only applied if the stored version is the one in the row or values, and bump it.
+// Otherwise they fail with a ConcurrentModificationError. A row with version 0 is only set if it doesn't exist yet or
+// has no version, like rows written before the version field was added.
+// These writes are lightweight transactions, so they can only be batched with writes to the same partition.
 type MapTable interface {
 	// Set Inserts, or Replaces your row with the supplied struct. Be aware that what is not in your struct
 	// will be deleted. To only overwrite some of the fields, Update()
@@ -81,6 +169,8 @@ type MapTable interface {
 	Update(partitionKey interface{}, valuesToUpdate map[string]interface{}) Op
 	Delete(partitionKey interface{}) Op
 	Read(partitionKey, pointer interface{}) Op
+	// MultiRead populates the provided pointer to a slice with the rows for the keys provided. How the rows are
+	// fetched is determined by the MultiRead strategy in the table's Options.
 	MultiRead(partitionKeys []interface{}, pointerToASlice interface{}) Op
 	WithOptions(Options) MapTable
 	Table() Table
@@ -91,6 +181,8 @@ type MapTable interface {
 // Multimap recipe
 //
 
+// MultimapTable stores rows partitioned by one field and clustered by another. Like a MapTable, its writes are
+// compare-and-set if the row type has a version field.
 type MultimapTable interface {
 	// Set Inserts, or Replaces your row with the supplied struct. Be aware that what is not in your struct
 	// will be deleted. To only overwrite some of the fields, Update()
@@ -101,6 +193,12 @@ type MultimapTable interface {
 	// List populates the provided pointer to a slice with the results matching the keys provided.
 	// To disable the limit, set limit to 0
 	List(partitionKey, clusteringKey interface{}, limit int, pointerToASlice interface{}) Op
+	// ListRange populates the provided pointer to a slice with the rows of the partition within the bounds of opts,
+	// in the order it specifies
+	ListRange(partitionKey interface{}, opts ListOptions, pointerToASlice interface{}) Op
+	// NextPage returns the options listing the page of rows following those read by a ListRange with opts, and
+	// whether there may be any more rows
+	NextPage(opts ListOptions, pointerToASlice interface{}) (ListOptions, bool)
 	Read(partitionKey, clusteringKey, pointer interface{}) Op
 	WithOptions(Options) MultimapTable
 	Table() Table
@@ -117,6 +215,12 @@ type MultimapMkTable interface {
 	// List populates the provided pointer to a slice with the results matching the keys provided.
 	// To disable the limit, set limit to 0
 	List(v, startId map[string]interface{}, limit int, pointerToASlice interface{}) Op
+	// ListRange populates the provided pointer to a slice with the rows of the partition within the bounds of opts,
+	// in the order it specifies. Bounds are maps of clustering column values
+	ListRange(v map[string]interface{}, opts ListOptions, pointerToASlice interface{}) Op
+	// NextPage returns the options listing the page of rows following those read by a ListRange with opts, and
+	// whether there may be any more rows
+	NextPage(opts ListOptions, pointerToASlice interface{}) (ListOptions, bool)
 	Read(v, id map[string]interface{}, pointer interface{}) Op
 	MultiRead(v, id map[string]interface{}, pointerToASlice interface{}) Op
 	WithOptions(Options) MultimapMkTable
@@ -139,6 +243,19 @@ type TimeSeriesTable interface {
 	Read(timeStamp time.Time, id, pointer interface{}) Op
 	List(start, end time.Time, pointerToASlice interface{}) Op
 	Buckets(start time.Time) Buckets
+	// ListWithLimit populates the provided pointer to a slice with up to opts.Limit rows between start and end,
+	// walking the buckets one at a time in the order opts specifies, so DESC lists the latest rows
+	ListWithLimit(start, end time.Time, opts SeriesListOptions, pointerToASlice interface{}) Op
+	// NextPage returns the options resuming a ListWithLimit with opts after the rows it read, and whether there may
+	// be any more rows
+	NextPage(opts SeriesListOptions, pointerToASlice interface{}) (SeriesListOptions, bool)
+	// ListSince populates the provided pointer to a slice with the rows after the row at timeStamp with the given ID,
+	// within the time window. The rows at timeStamp itself are ordered by their IDs, so none are skipped. If the time
+	// window is zero then it lists up until 5 minutes in the future
+	ListSince(timeStamp time.Time, id interface{}, window time.Duration, pointerToASlice interface{}) Op
+	// ListBefore populates the provided pointer to a slice with the rows before the row at timeStamp with the given
+	// ID, within the time window, which must be set
+	ListBefore(timeStamp time.Time, id interface{}, window time.Duration, pointerToASlice interface{}) Op
 	WithOptions(Options) TimeSeriesTable
 	Table() Table
 	TableChanger
@@ -159,6 +276,19 @@ type MultiTimeSeriesTable interface {
 	Read(v interface{}, timeStamp time.Time, id, pointer interface{}) Op
 	List(v interface{}, start, end time.Time, pointerToASlice interface{}) Op
 	Buckets(v interface{}, start time.Time) Buckets
+	// ListWithLimit populates the provided pointer to a slice with up to opts.Limit rows between start and end,
+	// walking the buckets one at a time in the order opts specifies, so DESC lists the latest rows
+	ListWithLimit(v interface{}, start, end time.Time, opts SeriesListOptions, pointerToASlice interface{}) Op
+	// NextPage returns the options resuming a ListWithLimit with opts after the rows it read, and whether there may
+	// be any more rows
+	NextPage(opts SeriesListOptions, pointerToASlice interface{}) (SeriesListOptions, bool)
+	// ListSince populates the provided pointer to a slice with the rows after the row at timeStamp with the given ID,
+	// within the time window. The rows at timeStamp itself are ordered by their IDs, so none are skipped. If the time
+	// window is zero then it lists up until 5 minutes in the future
+	ListSince(v interface{}, timeStamp time.Time, id interface{}, window time.Duration, pointerToASlice interface{}) Op
+	// ListBefore populates the provided pointer to a slice with the rows before the row at timeStamp with the given
+	// ID, within the time window, which must be set
+	ListBefore(v interface{}, timeStamp time.Time, id interface{}, window time.Duration, pointerToASlice interface{}) Op
 	WithOptions(Options) MultiTimeSeriesTable
 	Table() Table
 	TableChanger
@@ -176,6 +306,19 @@ type MultiKeyTimeSeriesTable interface {
 	Read(v map[string]interface{}, timeStamp time.Time, id map[string]interface{}, pointer interface{}) Op
 	List(v map[string]interface{}, start, end time.Time, pointerToASlice interface{}) Op
 	Buckets(v map[string]interface{}, start time.Time) Buckets
+	// ListWithLimit populates the provided pointer to a slice with up to opts.Limit rows between start and end,
+	// walking the buckets one at a time in the order opts specifies, so DESC lists the latest rows
+	ListWithLimit(v map[string]interface{}, start, end time.Time, opts SeriesListOptions, pointerToASlice interface{}) Op
+	// NextPage returns the options resuming a ListWithLimit with opts after the rows it read, and whether there may
+	// be any more rows
+	NextPage(opts SeriesListOptions, pointerToASlice interface{}) (SeriesListOptions, bool)
+	// ListSince populates the provided pointer to a slice with the rows after the row at timeStamp with the given ID,
+	// within the time window. The rows at timeStamp itself are ordered by their IDs, so none are skipped. If the time
+	// window is zero then it lists up until 5 minutes in the future
+	ListSince(v map[string]interface{}, timeStamp time.Time, id map[string]interface{}, window time.Duration, pointerToASlice interface{}) Op
+	// ListBefore populates the provided pointer to a slice with the rows before the row at timeStamp with the given
+	// ID, within the time window, which must be set
+	ListBefore(v map[string]interface{}, timeStamp time.Time, id map[string]interface{}, window time.Duration, pointerToASlice interface{}) Op
 	WithOptions(Options) MultiKeyTimeSeriesTable
 	Table() Table
 	TableChanger
@@ -190,9 +333,18 @@ type FlakeSeriesTable interface {
 	Read(id string, pointer interface{}) Op
 	List(start, end time.Time, pointerToASlice interface{}) Op
 	Buckets(start time.Time) Buckets
+	// ListWithLimit populates the provided pointer to a slice with up to opts.Limit rows between start and end,
+	// walking the buckets one at a time in the order opts specifies, so DESC lists the latest rows
+	ListWithLimit(start, end time.Time, opts SeriesListOptions, pointerToASlice interface{}) Op
+	// NextPage returns the options resuming a ListWithLimit with opts after the rows it read, and whether there may
+	// be any more rows
+	NextPage(opts SeriesListOptions, pointerToASlice interface{}) (SeriesListOptions, bool)
 	// ListSince queries the flakeSeries for the items after the specified ID but within the time window,
 	// if the time window is zero then it lists up until 5 minutes in the future
 	ListSince(id string, window time.Duration, pointerToASlice interface{}) Op
+	// ListBefore queries the flakeSeries for the items before the specified ID but within the time window, which
+	// must be set
+	ListBefore(id string, window time.Duration, pointerToASlice interface{}) Op
 	WithOptions(Options) FlakeSeriesTable
 	Table() Table
 	TableChanger
@@ -207,14 +359,187 @@ type MultiFlakeSeriesTable interface {
 	Read(v interface{}, id string, pointer interface{}) Op
 	List(v interface{}, start, end time.Time, pointerToASlice interface{}) Op
 	Buckets(v interface{}, start time.Time) Buckets
+	// ListWithLimit populates the provided pointer to a slice with up to opts.Limit rows between start and end,
+	// walking the buckets one at a time in the order opts specifies, so DESC lists the latest rows
+	ListWithLimit(v interface{}, start, end time.Time, opts SeriesListOptions, pointerToASlice interface{}) Op
+	// NextPage returns the options resuming a ListWithLimit with opts after the rows it read, and whether there may
+	// be any more rows
+	NextPage(opts SeriesListOptions, pointerToASlice interface{}) (SeriesListOptions, bool)
 	// ListSince queries the flakeSeries for the items after the specified ID but within the time window,
 	// if the time window is zero then it lists up until 5 minutes in the future
 	ListSince(v interface{}, id string, window time.Duration, pointerToASlice interface{}) Op
+	// ListBefore queries the flakeSeries for the items before the specified ID but within the time window, which
+	// must be set
+	ListBefore(v interface{}, id string, window time.Duration, pointerToASlice interface{}) Op
 	WithOptions(Options) MultiFlakeSeriesTable
 	Table() Table
 	TableChanger
 }
 
+// MultiKeyFlakeSeriesTable is a cross between FlakeSeries and MultimapMkTable tables. Its series are selected by a
+// map of the values of every partition key.
+type MultiKeyFlakeSeriesTable interface {
+	// Set Inserts, or Replaces your row with the supplied struct. Be aware that what is not in your struct
+	// will be deleted. To only overwrite some of the fields, Update()
+	Set(rowStruct interface{}) Op
+	Update(v map[string]interface{}, id string, valuesToUpdate map[string]interface{}) Op
+	Delete(v map[string]interface{}, id string) Op
+	Read(v map[string]interface{}, id string, pointer interface{}) Op
+	List(v map[string]interface{}, start, end time.Time, pointerToASlice interface{}) Op
+	Buckets(v map[string]interface{}, start time.Time) Buckets
+	// ListWithLimit populates the provided pointer to a slice with up to opts.Limit rows between start and end,
+	// walking the buckets one at a time in the order opts specifies, so DESC lists the latest rows
+	ListWithLimit(v map[string]interface{}, start, end time.Time, opts SeriesListOptions, pointerToASlice interface{}) Op
+	// NextPage returns the options resuming a ListWithLimit with opts after the rows it read, and whether there may
+	// be any more rows
+	NextPage(opts SeriesListOptions, pointerToASlice interface{}) (SeriesListOptions, bool)
+	// ListSince queries the flakeSeries for the items after the specified ID but within the time window,
+	// if the time window is zero then it lists up until 5 minutes in the future
+	ListSince(v map[string]interface{}, id string, window time.Duration, pointerToASlice interface{}) Op
+	// ListBefore queries the flakeSeries for the items before the specified ID but within the time window, which
+	// must be set
+	ListBefore(v map[string]interface{}, id string, window time.Duration, pointerToASlice interface{}) Op
+	WithOptions(Options) MultiKeyFlakeSeriesTable
+	Table() Table
+	TableChanger
+}
+
+// CounterTable is a table of counters. Its ops are run in a counter batch by RunLoggedBatchWithContext, so they can
+// only be batched with other counter updates: running them in a batch with other writes returns an error.
+type CounterTable interface {
+	// Increment adds delta, which may be negative, to a counter of the row with the given key values
+	Increment(keys map[string]interface{}, counterField string, delta int) Op
+	// IncrementMany adds to several counters of the row with the given key values at once
+	IncrementMany(keys map[string]interface{}, deltas map[string]int) Op
+	Read(keys map[string]interface{}, pointer interface{}) Op
+	// List reads every row of the partition with the given partition key values
+	List(partitionKeys map[string]interface{}, pointerToASlice interface{}) Op
+	// Reset deletes the row with the given key values, so its counters count from zero again. Cassandra can't order
+	// a delete with increments made at about the same time, so the row should be quiet when it's reset
+	Reset(keys map[string]interface{}) Op
+	WithOptions(Options) CounterTable
+	Table() Table
+	TableChanger
+}
+
+// IndexedEntity keeps an entity in a primary MapTable and in secondary recipe tables keyed by its other fields (see
+// EntityIndexTable), so it can be read by them. Set and Delete read the stored version of the entity to find the index rows
+// which are out of date, and then write every table in a single logged batch. Concurrent changes to the same entity
+// may leave stale index rows behind. If the primary table's rows have a version field, its versioned write can't be
+// batched with writes to other tables, so it's made first and the indexes are only written once it's applied.
+type IndexedEntity interface {
+	// Set writes the entity to every table, and deletes the rows of the indexes whose field has changed. The entity
+	// isn't written to the indexes whose field is unset (nil or ""). As it reads the entity before it writes, the op
+	// can't be added to a batch.
+	Set(rowStruct interface{}) Op
+	// Delete removes the entity with the given ID from every table. As it reads the entity before it deletes it, the
+	// op can't be added to a batch.
+	Delete(id interface{}) Op
+	// Read reads the entity with the given ID from the primary table
+	Read(id, pointer interface{}) Op
+	// ReadBy reads the entities whose indexed field has the given value from the named index
+	ReadBy(index string, key, pointerToASlice interface{}) Op
+	Primary() MapTable
+}
+
+// UniqueIndex keeps the values of a field unique among the entities of a primary MapTable. Before an entity is
+// written, its value is claimed for its ID in a lookup table with a lightweight transaction, and the value it held
+// before is released after. A failure in between leaves an orphaned claim, which Orphans and Repair find and release.
+type UniqueIndex interface {
+	// Set claims the entity's value, writes the entity, then releases the value it held before. If another entity
+	// holds the value, the Op fails with a UniqueViolationError and the entity isn't written. Empty values aren't
+	// claimed
+	Set(rowStruct interface{}) Op
+	// Delete removes the entity with the given ID and releases its value
+	Delete(id string) Op
+	// Read reads the entity with the given ID from the primary table
+	Read(id string, pointer interface{}) Op
+	// ReadClaim reads the claim on value
+	ReadClaim(value string, claim *UniqueClaim) Op
+	// Claim claims value for owner, failing with a UniqueViolationError if another owner holds it
+	Claim(value, owner string) Op
+	// Release releases owner's claim on value, if it holds it
+	Release(value, owner string) Op
+	// Orphans lists the claims made more than olderThan ago whose owner doesn't exist or holds another value. It
+	// reads the whole lookup table
+	Orphans(olderThan time.Duration, claims *[]UniqueClaim) Op
+	// Repair releases the orphaned claims made more than olderThan ago. olderThan should be well beyond how long a
+	// Set takes, so the claims of entities which are still being written aren't released
+	Repair(olderThan time.Duration) Op
+	Primary() MapTable
+	// Claims returns the lookup table
+	Claims() Table
+}
+
+// LeaseTable holds named leases, such as those of leaders elected among workers. A lease is held by one owner at a
+// time, until it expires or is released. Leases are taken and renewed with lightweight transactions and are written
+// with a TTL, so Cassandra removes them once they expire. TTLs are at least a second.
+type LeaseTable interface {
+	// Acquire takes the lease for owner for ttl. If another owner holds it, a LeaseHeldError is returned. Acquiring
+	// a lease the owner holds already renews it
+	Acquire(ctx context.Context, name, owner string, ttl time.Duration) error
+	// Renew extends owner's lease to ttl from now. If owner doesn't hold the lease any more, ErrLeaseNotHeld or a
+	// LeaseHeldError is returned
+	Renew(ctx context.Context, name, owner string, ttl time.Duration) error
+	// Release gives up owner's lease. Releasing a lease the owner doesn't hold does nothing
+	Release(ctx context.Context, name, owner string) error
+	// Holder reads who holds the lease and until when, returning ErrLeaseNotHeld if no one does
+	Holder(ctx context.Context, name string) (Lease, error)
+	// KeepRenewed renews owner's lease for ttl every interval in the background, until ctx is done or a renewal
+	// fails. The returned channel receives the error a renewal failed with, and is closed when renewing stops. The
+	// lease isn't released when ctx is done
+	KeepRenewed(ctx context.Context, name, owner string, ttl, interval time.Duration) <-chan error
+	WithOptions(Options) LeaseTable
+	Table() Table
+	TableChanger
+}
+
+// QueueTable is a durable queue of messages, split into shards which are each read in the order their messages were
+// enqueued. Enqueue is a plain write, so messages can be enqueued in the same logged batch as the writes they
+// announce. Consumers poll a shard from their checkpoint, claim the messages they handle for a visibility timeout
+// using lightweight transactions, and ack them once they're done.
+type QueueTable interface {
+	// Enqueue adds a message with the payload to the shard
+	Enqueue(shard string, payload []byte) Op
+	// Poll populates messages with up to limit of the unclaimed messages of the shard after since, or all of them if
+	// limit isn't positive. A since without an ID starts from its time
+	Poll(shard string, since SeriesCursor, limit int, messages *[]QueueMessage) Op
+	// Claim hides the message from Poll for timeout. If another claim on it hasn't expired, it fails with
+	// ErrMessageClaimed, and if it's been acked, with ErrMessageNotFound
+	Claim(shard, id, consumer string, timeout time.Duration) Op
+	// Ack deletes the message
+	Ack(shard, id string) Op
+	// Checkpoint records that consumer has handled the messages of msg's shard up to msg
+	Checkpoint(consumer string, msg QueueMessage) Op
+	// ReadCheckpoint reads consumer's checkpoint of the shard
+	ReadCheckpoint(consumer, shard string, checkpoint *QueueCheckpoint) Op
+	// Messages returns the table the messages are stored in
+	Messages() MultiTimeSeriesTable
+	// Checkpoints returns the table the checkpoints are stored in
+	Checkpoints() MapTable
+	WithOptions(Options) QueueTable
+}
+
+// CacheTable is a read-through cache of encoded values, keyed by strings. Entries are written with a TTL, so Cassandra
+// removes them once they expire. Use TypedCache to cache values of other types.
+type CacheTable interface {
+	// Get returns the cached value of key. On a miss, the loader is called and the value it loads is cached. Callers
+	// of Get missing the same key at the same time share one call of the loader. If the loader returns an error
+	// wrapping ErrValueNotFound, that's cached for the negative TTL, during which Get returns ErrValueNotFound without
+	// calling the loader. A loaded value is written to the cache even if the context of the caller which loaded it is
+	// done, within the table's Timeout. If it can't be written, it's returned with the error
+	Get(ctx context.Context, key string, loader func() ([]byte, error)) ([]byte, error)
+	// Set caches the value of key
+	Set(key string, value []byte) Op
+	// Touch restarts the TTL of key's entry, returning ErrNotCached if there isn't one
+	Touch(ctx context.Context, key string) error
+	// Delete removes key's entry
+	Delete(key string) Op
+	WithOptions(Options) CacheTable
+	Table() Table
+	TableChanger
+}
+
 //
 // Raw CQL
 //
@@ -226,6 +551,14 @@ type Filter interface {
 	Update(valuesToUpdate map[string]interface{}) Op // Probably this is danger zone (can't be implemented efficiently) on a selectuinb with more than 1 document
 	// Delete all rows matching the filter.
 	Delete() Op
+	// UpdateIf updates the single row matching the filter if it meets the conditions, or if it exists when there are
+	// none. Otherwise the Op fails with a ConditionFailedError. It's run as a lightweight transaction, so it can only
+	// be batched with writes to the same partition.
+	UpdateIf(valuesToUpdate map[string]interface{}, conditions ...Relation) Op
+	// DeleteIf deletes the single row matching the filter if it meets the conditions, or if it exists when there are
+	// none. Otherwise the Op fails with a ConditionFailedError. It's run as a lightweight transaction, so it can only
+	// be batched with writes to the same partition.
+	DeleteIf(conditions ...Relation) Op
 	// Reads all results. Make sure you pass in a pointer to a slice.
 	Read(pointerToASlice interface{}) Op
 	// ReadOne reads a single result. Make sure you pass in a pointer.
@@ -264,6 +597,11 @@ type Op interface {
 	RunAtomically() error
 	// Deprecated: The name "RunAtomically" is a misnomer, and "RunLoggedBatchWithContext" should be used instead
 	RunAtomicallyWithContext(context.Context) error
+	// RunConcurrentlyWithContext runs the operations making up this Op concurrently, with at most maxParallel in
+	// flight at once (or all of them, if maxParallel isn't positive). The first failure cancels the context of the
+	// operations still running and stops any more from starting. The errors of the operations which failed are
+	// returned as a MultiError.
+	RunConcurrentlyWithContext(ctx context.Context, maxParallel int) error
 	// Add an other Op to this one.
 	Add(...Op) Op
 	// WithOptions lets you specify `Op` level `Options`.
@@ -302,6 +640,11 @@ type TableChanger interface {
 	// Recreate drops the table if exists and creates it again.
 	// This is useful for test purposes only.
 	Recreate() error
+	// AlterOptions updates the properties of the existing table to the TableOptions set in its Options. Properties
+	// which aren't set are left unchanged.
+	AlterOptions() error
+	// AlterOptionsStatement returns you the CQL query which AlterOptions runs
+	AlterOptionsStatement() (Statement, error)
 	// Name returns the name of the table, as in C*
 	Name() string
 }
@@ -313,6 +656,10 @@ type Table interface {
 	// Set Inserts, or Replaces your row with the supplied struct. Be aware that what is not in your struct
 	// will be deleted. To only overwrite some of the fields, use Query.Update.
 	Set(rowStruct interface{}) Op
+	// SetIfNotExists inserts the row only if there is no row with its keys already. Otherwise the Op fails with a
+	// ConditionFailedError holding the existing row. It's run as a lightweight transaction, so it can only be batched
+	// with writes to the same partition.
+	SetIfNotExists(rowStruct interface{}) Op
 	// Where accepts a bunch of realtions and returns a filter. See the documentation for Relation and Filter to understand what that means.
 	Where(relations ...Relation) Filter // Because we provide selections
 	// Name returns the underlying table name, as stored in C*
@@ -379,6 +726,17 @@ type QueryExecutor interface {
 	ExecuteAtomicallyWithOptions(opts Options, stmts []Statement) error
 }
 
+// ConditionalQueryExecutor is implemented by QueryExecutors which can execute conditional statements (lightweight
+// transactions). The QueryExecutor of a connection must implement it to run SetIfNotExists, UpdateIf and DeleteIf.
+type ConditionalQueryExecutor interface {
+	// ExecuteConditionallyWithOptions executes a conditional DML query, returning whether it was applied. If it
+	// wasn't, the columns of the row its condition was checked against are returned keyed by their lower case names.
+	ExecuteConditionallyWithOptions(opts Options, stmt Statement) (applied bool, current map[string]interface{}, err error)
+	// ExecuteBatchConditionallyWithOptions executes a batch of DML queries, at least one of which is conditional, like
+	// ExecuteConditionallyWithOptions. The batch is applied as a whole or not at all.
+	ExecuteBatchConditionallyWithOptions(opts Options, stmts []Statement) (applied bool, current map[string]interface{}, err error)
+}
+
 type Counter int
 
 // Buckets is an iterator over a timeseries' buckets
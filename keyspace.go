@@ -1,6 +1,7 @@
 package gocassa
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"time"
@@ -15,6 +16,7 @@ type k struct {
 	name         string
 	debugMode    bool
 	tableFactory tableFactory
+	registry     *tableRegistry
 }
 
 // Connect to a certain keyspace directly. Same as using Connect().KeySpace(keySpaceName)
@@ -46,11 +48,13 @@ func (k *k) NewTable(name string, entity interface{}, fields map[string]interfac
 		return k.tableFactory.NewTable(name, entity, fields, keys)
 	} else {
 		ti := newTableInfo(k.name, name, keys, entity, fields)
-		return &t{
+		tbl := &t{
 			keySpace: k,
 			info:     ti,
 			options:  Options{},
 		}
+		k.registry.register(tbl, keys)
+		return tbl
 	}
 }
 
@@ -63,7 +67,8 @@ func (k *k) MapTable(name, id string, row interface{}) MapTable {
 		t: k.NewTable(fmt.Sprintf("%s_map_%s", name, id), row, m, Keys{
 			PartitionKeys: []string{id},
 		}),
-		idField: id,
+		idField:      id,
+		versionField: mustBeVersionField(row),
 	}
 }
 
@@ -83,6 +88,31 @@ func (k *k) MultimapTable(name, fieldToIndexBy, id string, row interface{}) Mult
 		}),
 		idField:        id,
 		fieldToIndexBy: fieldToIndexBy,
+		versionField:   mustBeVersionField(row),
+	}
+}
+
+func (k *k) ShardedMultimapTable(name, fieldToIndexBy, id string, shards int, row interface{}) MultimapTable {
+	if shards < 1 {
+		panic(fmt.Sprintf("A sharded table needs at least one shard, got %d", shards))
+	}
+	m, ok := toMap(row)
+	if !ok {
+		panic("Unrecognized row type")
+	}
+	if _, ok := m[shardFieldName]; ok {
+		panic(fmt.Sprintf("Field %s is used by the sharded table", shardFieldName))
+	}
+	m[shardFieldName] = 0
+	return &shardedMultimapT{
+		t: k.NewTable(fmt.Sprintf("%s_multimap_%s_%s_sharded%d", name, fieldToIndexBy, id, shards), row, m, Keys{
+			PartitionKeys:     []string{fieldToIndexBy, shardFieldName},
+			ClusteringColumns: []string{id},
+		}),
+		idField:        id,
+		fieldToIndexBy: fieldToIndexBy,
+		shards:         shards,
+		versionField:   mustBeVersionField(row),
 	}
 }
 
@@ -102,41 +132,53 @@ func (k *k) MultimapMultiKeyTable(name string, fieldToIndexBy, id []string, row
 }
 
 func (k *k) TimeSeriesTable(name, timeField, idField string, bucketSize time.Duration, row interface{}) TimeSeriesTable {
+	return k.TimeSeriesTableWithBuckets(name, timeField, idField, FixedBuckets(bucketSize), row)
+}
+
+func (k *k) TimeSeriesTableWithBuckets(name, timeField, idField string, buckets BucketStrategy, row interface{}) TimeSeriesTable {
 	m, ok := toMap(row)
 	if !ok {
 		panic("Unrecognized row type")
 	}
 	m[bucketFieldName] = time.Now()
 	return &timeSeriesT{
-		t: k.NewTable(fmt.Sprintf("%s_timeSeries_%s_%s_%s", name, timeField, idField, bucketSize), row, m, Keys{
+		t: k.NewTable(fmt.Sprintf("%s_timeSeries_%s_%s_%s", name, timeField, idField, buckets.Name()), row, m, Keys{
 			PartitionKeys:     []string{bucketFieldName},
 			ClusteringColumns: []string{timeField, idField},
 		}),
-		timeField:  timeField,
-		idField:    idField,
-		bucketSize: bucketSize,
+		timeField: timeField,
+		idField:   idField,
+		buckets:   buckets,
 	}
 }
 
 func (k *k) MultiTimeSeriesTable(name, indexField, timeField, idField string, bucketSize time.Duration, row interface{}) MultiTimeSeriesTable {
+	return k.MultiTimeSeriesTableWithBuckets(name, indexField, timeField, idField, FixedBuckets(bucketSize), row)
+}
+
+func (k *k) MultiTimeSeriesTableWithBuckets(name, indexField, timeField, idField string, buckets BucketStrategy, row interface{}) MultiTimeSeriesTable {
 	m, ok := toMap(row)
 	if !ok {
 		panic("Unrecognized row type")
 	}
 	m[bucketFieldName] = time.Now()
 	return &multiTimeSeriesT{
-		t: k.NewTable(fmt.Sprintf("%s_multiTimeSeries_%s_%s_%s_%s", name, indexField, timeField, idField, bucketSize.String()), row, m, Keys{
+		t: k.NewTable(fmt.Sprintf("%s_multiTimeSeries_%s_%s_%s_%s", name, indexField, timeField, idField, buckets.Name()), row, m, Keys{
 			PartitionKeys:     []string{indexField, bucketFieldName},
 			ClusteringColumns: []string{timeField, idField},
 		}),
 		indexField: indexField,
 		timeField:  timeField,
 		idField:    idField,
-		bucketSize: bucketSize,
+		buckets:    buckets,
 	}
 }
 
 func (k *k) MultiKeyTimeSeriesTable(name string, indexFields []string, timeField string, idFields []string, bucketSize time.Duration, row interface{}) MultiKeyTimeSeriesTable {
+	return k.MultiKeyTimeSeriesTableWithBuckets(name, indexFields, timeField, idFields, FixedBuckets(bucketSize), row)
+}
+
+func (k *k) MultiKeyTimeSeriesTableWithBuckets(name string, indexFields []string, timeField string, idFields []string, buckets BucketStrategy, row interface{}) MultiKeyTimeSeriesTable {
 	m, ok := toMap(row)
 	if !ok {
 		panic("Unrecognized row type")
@@ -149,18 +191,22 @@ func (k *k) MultiKeyTimeSeriesTable(name string, indexFields []string, timeField
 
 	m[bucketFieldName] = time.Now()
 	return &multiKeyTimeSeriesT{
-		t: k.NewTable(fmt.Sprintf("%s_multiKeyTimeSeries_%s_%s", name, timeField, bucketSize.String()), row, m, Keys{
+		t: k.NewTable(fmt.Sprintf("%s_multiKeyTimeSeries_%s_%s", name, timeField, buckets.Name()), row, m, Keys{
 			PartitionKeys:     partitionKeys,
 			ClusteringColumns: clusteringColumns,
 		}),
 		indexFields: indexFields,
 		timeField:   timeField,
 		idFields:    idFields,
-		bucketSize:  bucketSize,
+		buckets:     buckets,
 	}
 }
 
 func (k *k) FlakeSeriesTable(name, idField string, bucketSize time.Duration, row interface{}) FlakeSeriesTable {
+	return k.FlakeSeriesTableWithBuckets(name, idField, FixedBuckets(bucketSize), nil, row)
+}
+
+func (k *k) FlakeSeriesTableWithBuckets(name, idField string, buckets BucketStrategy, decoder IDTimeDecoder, row interface{}) FlakeSeriesTable {
 	m, ok := toMap(row)
 	if !ok {
 		panic("Unrecognized row type")
@@ -168,16 +214,21 @@ func (k *k) FlakeSeriesTable(name, idField string, bucketSize time.Duration, row
 	m[flakeTimestampFieldName] = time.Now()
 	m[bucketFieldName] = time.Now()
 	return &flakeSeriesT{
-		t: k.NewTable(fmt.Sprintf("%s_flakeSeries_%s_%s", name, idField, bucketSize.String()), row, m, Keys{
+		t: k.NewTable(fmt.Sprintf("%s_flakeSeries_%s_%s", name, idField, buckets.Name()), row, m, Keys{
 			PartitionKeys:     []string{bucketFieldName},
 			ClusteringColumns: []string{flakeTimestampFieldName, idField},
 		}),
-		idField:    idField,
-		bucketSize: bucketSize,
+		idField: idField,
+		buckets: buckets,
+		decoder: idTimeDecoder(decoder),
 	}
 }
 
 func (k *k) MultiFlakeSeriesTable(name, indexField, idField string, bucketSize time.Duration, row interface{}) MultiFlakeSeriesTable {
+	return k.MultiFlakeSeriesTableWithBuckets(name, indexField, idField, FixedBuckets(bucketSize), nil, row)
+}
+
+func (k *k) MultiFlakeSeriesTableWithBuckets(name, indexField, idField string, buckets BucketStrategy, decoder IDTimeDecoder, row interface{}) MultiFlakeSeriesTable {
 	m, ok := toMap(row)
 	if !ok {
 		panic("Unrecognized row type")
@@ -185,16 +236,116 @@ func (k *k) MultiFlakeSeriesTable(name, indexField, idField string, bucketSize t
 	m[flakeTimestampFieldName] = time.Now()
 	m[bucketFieldName] = time.Now()
 	return &multiFlakeSeriesT{
-		t: k.NewTable(fmt.Sprintf("%s_multiflakeSeries_%s_%s_%s", name, indexField, idField, bucketSize.String()), row, m, Keys{
+		t: k.NewTable(fmt.Sprintf("%s_multiflakeSeries_%s_%s_%s", name, indexField, idField, buckets.Name()), row, m, Keys{
 			PartitionKeys:     []string{indexField, bucketFieldName},
 			ClusteringColumns: []string{flakeTimestampFieldName, idField},
 		}),
 		idField:    idField,
-		bucketSize: bucketSize,
+		buckets:    buckets,
+		decoder:    idTimeDecoder(decoder),
 		indexField: indexField,
 	}
 }
 
+func (k *k) MultiKeyFlakeSeriesTable(name string, indexFields []string, idField string, bucketSize time.Duration, row interface{}) MultiKeyFlakeSeriesTable {
+	return k.MultiKeyFlakeSeriesTableWithBuckets(name, indexFields, idField, FixedBuckets(bucketSize), nil, row)
+}
+
+func (k *k) MultiKeyFlakeSeriesTableWithBuckets(name string, indexFields []string, idField string, buckets BucketStrategy, decoder IDTimeDecoder, row interface{}) MultiKeyFlakeSeriesTable {
+	m, ok := toMap(row)
+	if !ok {
+		panic("Unrecognized row type")
+	}
+	m[flakeTimestampFieldName] = time.Now()
+	m[bucketFieldName] = time.Now()
+
+	partitionKeys := append(append([]string{}, indexFields...), bucketFieldName)
+	return &multiKeyFlakeSeriesT{
+		t: k.NewTable(fmt.Sprintf("%s_multiKeyFlakeSeries_%s_%s", name, idField, buckets.Name()), row, m, Keys{
+			PartitionKeys:     partitionKeys,
+			ClusteringColumns: []string{flakeTimestampFieldName, idField},
+		}),
+		indexFields: indexFields,
+		idField:     idField,
+		buckets:     buckets,
+		decoder:     idTimeDecoder(decoder),
+	}
+}
+
+func (k *k) CounterTable(name string, partitionKeys, clusteringKeys, counterFields []string, row interface{}) CounterTable {
+	m, ok := toMap(row)
+	if !ok {
+		panic("Unrecognized row type")
+	}
+	if err := validateCounterRow(m, partitionKeys, clusteringKeys, counterFields); err != nil {
+		panic(fmt.Sprintf("Invalid counter table %s: %v", name, err))
+	}
+	return &counterT{
+		t: k.NewTable(fmt.Sprintf("%s_counter", name), row, m, Keys{
+			PartitionKeys:     partitionKeys,
+			ClusteringColumns: clusteringKeys,
+		}),
+		partitionKeys:  partitionKeys,
+		clusteringKeys: clusteringKeys,
+		counterFields:  counterFields,
+	}
+}
+
+func (k *k) UniqueIndex(name string, primary MapTable, idField, uniqueField string, row interface{}) UniqueIndex {
+	m, ok := toMap(row)
+	if !ok {
+		panic("Unrecognized row type")
+	}
+	for _, field := range []string{idField, uniqueField} {
+		if _, ok := m[field].(string); !ok {
+			panic(fmt.Sprintf("Invalid unique index %s: field %s is not present or is not a string", name, field))
+		}
+	}
+	claim := UniqueClaim{}
+	fields, _ := toMap(claim)
+	return &uniqueIndexT{
+		claims: k.NewTable(fmt.Sprintf("%s_unique_%s", name, uniqueField), claim, fields, Keys{
+			PartitionKeys: []string{"Value"},
+		}),
+		primary:     primary,
+		idField:     idField,
+		uniqueField: uniqueField,
+		rowType:     structType(row),
+	}
+}
+
+func (k *k) LeaseTable(name string) LeaseTable {
+	lease := Lease{}
+	fields, _ := toMap(lease)
+	return &leaseT{
+		t: k.NewTable(fmt.Sprintf("%s_lease", name), lease, fields, Keys{
+			PartitionKeys: []string{"Name"},
+		}),
+	}
+}
+
+func (k *k) QueueTable(name string, buckets BucketStrategy) QueueTable {
+	return &queueT{
+		messages:    k.MultiTimeSeriesTableWithBuckets(name+"_queue", "Shard", "Enqueued", "Id", buckets, QueueMessage{}),
+		checkpoints: k.MapTable(name+"_checkpoint", "Id", QueueCheckpoint{}),
+		buckets:     buckets,
+	}
+}
+
+func (k *k) CacheTable(name string, ttl, negativeTTL time.Duration) CacheTable {
+	checkCacheTTLs(ttl, negativeTTL)
+	entry := CacheEntry{}
+	fields, _ := toMap(entry)
+	return &cacheT{
+		t: k.NewTable(fmt.Sprintf("%s_cache", name), entry, fields, Keys{
+			PartitionKeys: []string{"Key"},
+		}),
+		ttl:         ttl,
+		negativeTTL: negativeTTL,
+		loads:       newCacheLoads(),
+	}
+}
+
 type tableInfoMarshal struct {
 	TableName string `cql:"table_name"`
 }
@@ -224,6 +375,11 @@ func (k *k) Tables() ([]string, error) {
 	return ret, nil
 }
 
+// TableMetadata returns the columns, indexes and options of a table, as read from the keyspace's system_schema
+func (k *k) TableMetadata(cf string) (TableMetadata, error) {
+	return systemSchema{qe: k.qe}.TableMetadata(k.name, cf)
+}
+
 func (k *k) Exists(cf string) (bool, error) {
 	ts, err := k.Tables()
 	if err != nil {
@@ -243,6 +399,87 @@ func (k *k) DropTable(cf string) error {
 	return k.qe.Execute(stmt)
 }
 
+// RegisteredTables returns the tables defined on the keyspace, ordered by name. Tables which are defined more than
+// once with the same keys are only returned once.
+func (k *k) RegisteredTables() []Table {
+	defs := k.registry.definitions()
+	tables := make([]Table, len(defs))
+	for i, def := range defs {
+		tables[i] = def.table
+	}
+	return tables
+}
+
+// RegisterTable adds a copy of a table made with WithOptions to the tables defined on the keyspace, replacing any
+// definition of a table with the same name and keys
+func (k *k) RegisterTable(table Table) {
+	keyed, ok := table.(interface{ tableKeys() Keys })
+	if !ok {
+		panic(fmt.Sprintf("Can't register table %s: it wasn't defined on a keyspace", table.Name()))
+	}
+	k.registry.register(table, keyed.tableKeys())
+}
+
+// Migrator returns a Migrator for the tables defined on the keyspace, which reads their live schema from Cassandra's
+// system_schema tables
+func (k *k) Migrator() *Migrator {
+	return &Migrator{
+		keySpace: k.name,
+		tables:   func() ([]Table, error) { return k.registry.tables(k.name) },
+		source:   systemSchema{qe: k.qe},
+		qe:       k.qe,
+	}
+}
+
+// CreateAllIfNotExist creates each of the tables defined on the keyspace which doesn't exist already
+func (k *k) CreateAllIfNotExist(ctx context.Context) error {
+	tables, err := k.registry.tables(k.name)
+	if err != nil {
+		return err
+	}
+	for _, tbl := range tables {
+		stmt, err := tbl.CreateIfNotExistStatement()
+		if err != nil {
+			return err
+		}
+		if err := k.qe.ExecuteWithOptions(Options{Context: ctx}, stmt); err != nil {
+			return fmt.Errorf("creating %s.%s: %w", k.name, tbl.Name(), err)
+		}
+	}
+	return nil
+}
+
+// DropAll drops each of the tables defined on the keyspace
+func (k *k) DropAll() error {
+	dropped := map[string]bool{}
+	for _, tbl := range k.RegisteredTables() {
+		name := strings.ToLower(tbl.Name())
+		if dropped[name] {
+			continue
+		}
+		if err := k.DropTable(tbl.Name()); err != nil {
+			return err
+		}
+		dropped[name] = true
+	}
+	return nil
+}
+
+// SchemaCQL returns a CQL script creating each of the tables defined on the keyspace, ordered by table name
+func (k *k) SchemaCQL() (string, error) {
+	tables, err := k.registry.tables(k.name)
+	if err != nil {
+		return "", err
+	}
+	stmts := make([]Statement, len(tables))
+	for i, tbl := range tables {
+		if stmts[i], err = tbl.CreateIfNotExistStatement(); err != nil {
+			return "", err
+		}
+	}
+	return schemaCQL(stmts), nil
+}
+
 func (k *k) Name() string {
 	return k.name
 }
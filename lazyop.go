@@ -0,0 +1,103 @@
+package gocassa
+
+import "context"
+
+// lazyOp is an Op whose statements can't be known until it runs, for example because they depend on the results of
+// earlier queries. run is called with the op's options each time it is executed, and is responsible for building and
+// running the underlying ops itself. As there are no statements a lazy op could add to a batch, running one in a
+// batch fails.
+type lazyOp struct {
+	options Options
+	qe      QueryExecutor
+	run     func(opts Options) error
+	// inner returns the op the lazy op wraps given its options, if it runs a single op and works on its results. Its
+	// statement describes the lazy op.
+	inner func(opts Options) Op
+}
+
+func newLazyOp(qe QueryExecutor, run func(Options) error) lazyOp {
+	return lazyOp{
+		qe:  qe,
+		run: run,
+	}
+}
+
+// wrapLazyOp returns a lazy op which runs inner within run, and is described by inner's statement
+func wrapLazyOp(inner Op, run func(Options) error) lazyOp {
+	return lazyOp{
+		qe:    inner.QueryExecutor(),
+		run:   run,
+		inner: func(Options) Op { return inner },
+	}
+}
+
+// buildLazyOp returns a lazy op which runs the op build returns given its options, for ops which depend on their
+// options in ways the options of the op built can't express. It's described by the op built.
+func buildLazyOp(qe QueryExecutor, build func(Options) Op) lazyOp {
+	return lazyOp{
+		qe: qe,
+		run: func(opts Options) error {
+			return build(opts).WithOptions(opts).Run()
+		},
+		inner: build,
+	}
+}
+
+func (o lazyOp) Run() error {
+	return o.run(o.options)
+}
+
+func (o lazyOp) RunWithContext(ctx context.Context) error {
+	return o.WithOptions(Options{Context: ctx}).Run()
+}
+
+func (o lazyOp) RunAtomically() error {
+	return o.Run()
+}
+
+func (o lazyOp) RunLoggedBatchWithContext(ctx context.Context) error {
+	return o.WithOptions(Options{Context: ctx}).Run()
+}
+
+func (o lazyOp) RunAtomicallyWithContext(ctx context.Context) error {
+	return o.RunLoggedBatchWithContext(ctx)
+}
+
+func (o lazyOp) RunConcurrentlyWithContext(ctx context.Context, maxParallel int) error {
+	return multiOp{o}.RunConcurrentlyWithContext(ctx, maxParallel)
+}
+
+func (o lazyOp) Add(ops ...Op) Op {
+	return multiOp{o}.Add(ops...)
+}
+
+func (o lazyOp) Options() Options {
+	return o.options
+}
+
+func (o lazyOp) WithOptions(opts Options) Op {
+	return lazyOp{
+		options: o.options.Merge(opts),
+		qe:      o.qe,
+		run:     o.run,
+		inner:   o.inner,
+	}
+}
+
+func (o lazyOp) Preflight() error {
+	if o.inner == nil {
+		return nil
+	}
+	return o.inner(o.options).WithOptions(o.options).Preflight()
+}
+
+func (o lazyOp) GenerateStatement() Statement {
+	if o.inner == nil {
+		return noOpStatement{}
+	}
+	return o.inner(o.options).WithOptions(o.options).GenerateStatement()
+}
+
+func (o lazyOp) QueryExecutor() QueryExecutor {
+	return o.qe
+}
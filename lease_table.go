@@ -0,0 +1,192 @@
+package gocassa
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Clock tells the time. A LeaseTable reads its clock from its options, so tests can control when leases expire.
+type Clock interface {
+	Now() time.Time
+	// After returns a channel which receives the time once d has passed
+	After(d time.Duration) <-chan time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time                         { return time.Now() }
+func (systemClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// SystemClock returns the Clock which tells the time of the system.
+func SystemClock() Clock {
+	return systemClock{}
+}
+
+// Lease is a row of a LeaseTable, recording which owner holds a lease and until when.
+type Lease struct {
+	Name    string
+	Owner   string
+	Expires time.Time
+}
+
+type leaseT struct {
+	t       Table
+	options Options
+}
+
+func (o *leaseT) Table() Table                        { return o.t }
+func (o *leaseT) Create() error                       { return o.Table().Create() }
+func (o *leaseT) CreateIfNotExist() error             { return o.Table().CreateIfNotExist() }
+func (o *leaseT) Name() string                        { return o.Table().Name() }
+func (o *leaseT) Recreate() error                     { return o.Table().Recreate() }
+func (o *leaseT) CreateStatement() (Statement, error) { return o.Table().CreateStatement() }
+func (o *leaseT) CreateIfNotExistStatement() (Statement, error) {
+	return o.Table().CreateIfNotExistStatement()
+}
+func (o *leaseT) AlterOptions() error { return o.Table().AlterOptions() }
+func (o *leaseT) AlterOptionsStatement() (Statement, error) {
+	return o.Table().AlterOptionsStatement()
+}
+
+func (o *leaseT) clock() Clock {
+	if o.options.Clock == nil {
+		return SystemClock()
+	}
+	return o.options.Clock
+}
+
+// expiry returns when a lease taken now for ttl expires, truncated to the precision Cassandra stores times with
+func (o *leaseT) expiry(ttl time.Duration) (time.Time, Options, error) {
+	if ttl < time.Second {
+		return time.Time{}, Options{}, fmt.Errorf("lease TTLs must be at least a second, got %v", ttl)
+	}
+	return o.clock().Now().Add(ttl).Truncate(time.Millisecond), Options{TTL: ttl}, nil
+}
+
+// currentLease returns the lease held when a conditional write on it failed, and whether it's still held
+func (o *leaseT) currentLease(name string, err ConditionFailedError) (Lease, bool) {
+	lease := Lease{Name: name}
+	if owner, ok := err.Current["owner"].(string); ok {
+		lease.Owner = owner
+	}
+	if expires, ok := err.Current["expires"].(time.Time); ok {
+		lease.Expires = expires
+	}
+	return lease, lease.Owner != "" && lease.Expires.After(o.clock().Now())
+}
+
+// heldError returns the error for a conditional write on the lease which failed with err
+func (o *leaseT) heldError(name string, err ConditionFailedError) error {
+	if lease, held := o.currentLease(name, err); held {
+		return LeaseHeldError{Lease: lease}
+	}
+	return ErrLeaseNotHeld
+}
+
+func (o *leaseT) Acquire(ctx context.Context, name, owner string, ttl time.Duration) error {
+	expires, opts, err := o.expiry(ttl)
+	if err != nil {
+		return err
+	}
+	err = o.Table().SetIfNotExists(Lease{Name: name, Owner: owner, Expires: expires}).WithOptions(opts).RunWithContext(ctx)
+	cerr, ok := err.(ConditionFailedError)
+	if !ok {
+		return err
+	}
+
+	current, held := o.currentLease(name, cerr)
+	switch {
+	case held && current.Owner == owner:
+		return o.Renew(ctx, name, owner, ttl)
+	case held:
+		return LeaseHeldError{Lease: current}
+	}
+	// The lease has expired, but its row is still there (its TTL may not have passed yet, by Cassandra's clock). It's
+	// taken over as long as no one else has already
+	err = o.Table().
+		Where(Eq("Name", name)).
+		UpdateIf(map[string]interface{}{"Owner": owner, "Expires": expires}, Eq("Owner", current.Owner), Eq("Expires", current.Expires)).
+		WithOptions(opts).
+		RunWithContext(ctx)
+	if cerr, ok := err.(ConditionFailedError); ok {
+		return o.heldError(name, cerr)
+	}
+	return err
+}
+
+func (o *leaseT) Renew(ctx context.Context, name, owner string, ttl time.Duration) error {
+	expires, opts, err := o.expiry(ttl)
+	if err != nil {
+		return err
+	}
+	err = o.Table().
+		Where(Eq("Name", name)).
+		UpdateIf(map[string]interface{}{"Owner": owner, "Expires": expires}, Eq("Owner", owner), GT("Expires", o.clock().Now())).
+		WithOptions(opts).
+		RunWithContext(ctx)
+	if cerr, ok := err.(ConditionFailedError); ok {
+		return o.heldError(name, cerr)
+	}
+	return err
+}
+
+func (o *leaseT) Release(ctx context.Context, name, owner string) error {
+	err := o.Table().
+		Where(Eq("Name", name)).
+		DeleteIf(Eq("Owner", owner)).
+		RunWithContext(ctx)
+	if _, ok := err.(ConditionFailedError); ok {
+		return nil
+	}
+	return err
+}
+
+func (o *leaseT) Holder(ctx context.Context, name string) (Lease, error) {
+	var lease Lease
+	err := o.Table().
+		Where(Eq("Name", name)).
+		ReadOne(&lease).
+		RunWithContext(ctx)
+	if _, ok := err.(RowNotFoundError); ok {
+		return Lease{}, ErrLeaseNotHeld
+	} else if err != nil {
+		return Lease{}, err
+	}
+	if !lease.Expires.After(o.clock().Now()) {
+		return Lease{}, ErrLeaseNotHeld
+	}
+	return lease, nil
+}
+
+func (o *leaseT) KeepRenewed(ctx context.Context, name, owner string, ttl, interval time.Duration) <-chan error {
+	done := make(chan error, 1)
+	clock := o.clock()
+	go func() {
+		defer close(done)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-clock.After(interval):
+			}
+			if err := o.Renew(ctx, name, owner, ttl); err != nil {
+				// The renewal was interrupted rather than refused
+				if ctx.Err() != nil && errors.Is(err, ctx.Err()) {
+					return
+				}
+				done <- err
+				return
+			}
+		}
+	}()
+	return done
+}
+
+func (o *leaseT) WithOptions(opt Options) LeaseTable {
+	return &leaseT{
+		t:       o.Table().WithOptions(opt),
+		options: o.options.Merge(opt),
+	}
+}
@@ -0,0 +1,110 @@
+package gocassa
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLeaseTableStatements(t *testing.T) {
+	var stmts []Statement
+	qe := casQE{
+		funcQE: funcQE{fn: func(opts Options, stmt Statement) error { return nil }},
+		cas: func(stmt Statement) (bool, map[string]interface{}, error) {
+			stmts = append(stmts, stmt)
+			return true, nil, nil
+		},
+	}
+	now := time.Date(2020, 3, 4, 5, 6, 7, 0, time.UTC)
+	leases := NewConnection(qe).KeySpace("ks").LeaseTable("leaders").
+		WithOptions(Options{Clock: NewMockClock(now)})
+	ctx := context.Background()
+
+	require.NoError(t, leases.Acquire(ctx, "billing", "worker-1", time.Minute))
+	assert.Equal(t, "INSERT INTO ks.leaders_lease (expires, name, owner) VALUES (?, ?, ?) IF NOT EXISTS USING TTL ?", stmts[0].Query())
+	assert.Equal(t, []interface{}{now.Add(time.Minute), "billing", "worker-1", 60}, stmts[0].Values())
+
+	require.NoError(t, leases.Renew(ctx, "billing", "worker-1", time.Minute))
+	assert.Equal(t, "UPDATE ks.leaders_lease USING TTL ? SET Expires = ?, Owner = ? WHERE name = ? IF owner = ? AND expires > ?", stmts[1].Query())
+	assert.Equal(t, []interface{}{60, now.Add(time.Minute), "worker-1", "billing", "worker-1", now}, stmts[1].Values())
+
+	require.NoError(t, leases.Release(ctx, "billing", "worker-1"))
+	assert.Equal(t, "DELETE FROM ks.leaders_lease WHERE name = ? IF owner = ?", stmts[2].Query())
+
+	assert.Error(t, leases.Acquire(ctx, "billing", "worker-1", time.Millisecond))
+	assert.Len(t, stmts, 3)
+}
+
+func TestMockLeaseTable(t *testing.T) {
+	clock := NewMockClock(time.Date(2020, 3, 4, 5, 6, 7, 0, time.UTC))
+	leases := NewMockKeySpace().LeaseTable("leaders").WithOptions(Options{Clock: clock})
+	ctx := context.Background()
+
+	_, err := leases.Holder(ctx, "billing")
+	assert.Equal(t, ErrLeaseNotHeld, err)
+
+	require.NoError(t, leases.Acquire(ctx, "billing", "worker-1", time.Minute))
+	held := Lease{Name: "billing", Owner: "worker-1", Expires: clock.Now().Add(time.Minute)}
+	assert.Equal(t, LeaseHeldError{Lease: held}, leases.Acquire(ctx, "billing", "worker-2", time.Minute))
+	lease, err := leases.Holder(ctx, "billing")
+	require.NoError(t, err)
+	assert.Equal(t, held, lease)
+
+	// Acquiring a lease again renews it
+	clock.Advance(30 * time.Second)
+	require.NoError(t, leases.Acquire(ctx, "billing", "worker-1", time.Minute))
+	lease, err = leases.Holder(ctx, "billing")
+	require.NoError(t, err)
+	assert.Equal(t, clock.Now().Add(time.Minute), lease.Expires)
+
+	// Once it expires, the lease can be taken by another owner, and the previous owner can't renew it
+	clock.Advance(time.Minute)
+	_, err = leases.Holder(ctx, "billing")
+	assert.Equal(t, ErrLeaseNotHeld, err)
+	assert.Equal(t, ErrLeaseNotHeld, leases.Renew(ctx, "billing", "worker-1", time.Minute))
+	require.NoError(t, leases.Acquire(ctx, "billing", "worker-2", time.Minute))
+	assert.IsType(t, LeaseHeldError{}, leases.Renew(ctx, "billing", "worker-1", time.Minute))
+
+	// Only the owner can release the lease
+	require.NoError(t, leases.Release(ctx, "billing", "worker-1"))
+	lease, err = leases.Holder(ctx, "billing")
+	require.NoError(t, err)
+	assert.Equal(t, "worker-2", lease.Owner)
+	require.NoError(t, leases.Release(ctx, "billing", "worker-2"))
+	_, err = leases.Holder(ctx, "billing")
+	assert.Equal(t, ErrLeaseNotHeld, err)
+}
+
+func TestMockLeaseTableKeepRenewed(t *testing.T) {
+	clock := NewMockClock(time.Date(2020, 3, 4, 5, 6, 7, 0, time.UTC))
+	leases := NewMockKeySpace().LeaseTable("leaders").WithOptions(Options{Clock: clock})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	require.NoError(t, leases.Acquire(ctx, "billing", "worker-1", time.Minute))
+	done := leases.KeepRenewed(ctx, "billing", "worker-1", time.Minute, 20*time.Second)
+	for i := 0; i < 5; i++ {
+		require.Eventually(t, func() bool { return clock.waiting() == 1 }, time.Second, time.Millisecond)
+		clock.Advance(20 * time.Second)
+	}
+	require.Eventually(t, func() bool { return clock.waiting() == 1 }, time.Second, time.Millisecond)
+	lease, err := leases.Holder(ctx, "billing")
+	require.NoError(t, err)
+	assert.Equal(t, clock.Now().Add(time.Minute), lease.Expires)
+
+	// Renewing stops when the context is done
+	cancel()
+	_, ok := <-done
+	assert.False(t, ok)
+
+	// A renewal which fails stops renewing and is reported
+	require.NoError(t, leases.Release(context.Background(), "billing", "worker-1"))
+	done = leases.KeepRenewed(context.Background(), "billing", "worker-1", time.Minute, 20*time.Second)
+	// The timer of the renewals which were stopped is still waiting too
+	require.Eventually(t, func() bool { return clock.waiting() == 2 }, time.Second, time.Millisecond)
+	clock.Advance(20 * time.Second)
+	assert.Equal(t, ErrLeaseNotHeld, <-done)
+}
@@ -0,0 +1,179 @@
+package gocassa
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	r "github.com/monzo/gocassa/reflect"
+)
+
+// ListBound bounds the clustering keys of the rows listed by a ListRange.
+type ListBound struct {
+	// Key is the clustering key value of the bound. For a MultimapMkTable it is a map of clustering column values,
+	// which may leave out trailing columns to bound by a prefix of the clustering key.
+	Key interface{}
+	// Exclusive leaves rows with the key itself out of the listing
+	Exclusive bool
+}
+
+// InclusiveBound returns a bound which includes the row with the given key.
+func InclusiveBound(key interface{}) *ListBound {
+	return &ListBound{Key: key}
+}
+
+// ExclusiveBound returns a bound which excludes the row with the given key.
+func ExclusiveBound(key interface{}) *ListBound {
+	return &ListBound{Key: key, Exclusive: true}
+}
+
+// ListOptions specifies the rows of a partition listed by a ListRange, and the order they are listed in.
+type ListOptions struct {
+	// Start bounds the first row listed and End the last, so when listing in DESC order Start is the upper bound of
+	// the clustering key. A nil bound lists from (or to) the edge of the partition.
+	Start, End *ListBound
+	// Order is the direction rows are listed in by their clustering key
+	Order ColumnDirection
+	// Limit is the maximum number of rows listed, or 0 for no limit
+	Limit int
+}
+
+// relations returns the relations selecting the clustering keys within the bounds
+func (o ListOptions) relations(clusteringKeys []string) ([]Relation, error) {
+	var rels []Relation
+	for _, b := range []struct {
+		bound *ListBound
+		lower bool
+	}{
+		{o.Start, o.Order == ASC},
+		{o.End, o.Order == DESC},
+	} {
+		if b.bound == nil {
+			continue
+		}
+		rel, err := b.bound.relation(clusteringKeys, b.lower)
+		if err != nil {
+			return nil, err
+		}
+		rels = append(rels, rel)
+	}
+	return rels, nil
+}
+
+// clusteringOrder returns the order listing the clustering keys in the direction of the options
+func (o ListOptions) clusteringOrder(clusteringKeys []string) []ClusteringOrderColumn {
+	order := make([]ClusteringOrderColumn, len(clusteringKeys))
+	for i, key := range clusteringKeys {
+		order[i] = ClusteringOrderColumn{Direction: o.Order, Column: key}
+	}
+	return order
+}
+
+// next returns the options listing the rows following the rows in pointerToASlice, and whether there may be any
+func (o ListOptions) next(clusteringKeys []string, pointerToASlice interface{}) (ListOptions, bool) {
+	rows := reflect.Indirect(reflect.ValueOf(pointerToASlice))
+	if rows.Kind() != reflect.Slice || o.Limit <= 0 || rows.Len() < o.Limit {
+		return o, false
+	}
+	last, ok := rowToMap(rows.Index(rows.Len() - 1).Interface())
+	if !ok {
+		return o, false
+	}
+
+	key := make(map[string]interface{}, len(clusteringKeys))
+	for _, field := range clusteringKeys {
+		value, ok := columnValue(last, field)
+		if !ok {
+			return o, false
+		}
+		key[field] = value
+	}
+
+	next := o
+	if len(clusteringKeys) == 1 {
+		next.Start = ExclusiveBound(key[clusteringKeys[0]])
+	} else {
+		next.Start = ExclusiveBound(key)
+	}
+	return next, true
+}
+
+// relation returns the relation selecting the clustering keys on the given side of the bound
+func (b ListBound) relation(clusteringKeys []string, lower bool) (Relation, error) {
+	fields, values := clusteringKeys, []interface{}{b.Key}
+	if key, ok := b.Key.(map[string]interface{}); ok {
+		fields, values = nil, nil
+		for _, field := range clusteringKeys {
+			value, ok := key[field]
+			if !ok {
+				break
+			}
+			fields, values = append(fields, field), append(values, value)
+		}
+		if len(fields) != len(key) {
+			return Relation{}, fmt.Errorf("list bound %v isn't a prefix of the clustering key %v", b.Key, clusteringKeys)
+		}
+	} else if len(clusteringKeys) != 1 {
+		return Relation{}, fmt.Errorf("list bound of a table clustered by %v must be a map of their values", clusteringKeys)
+	}
+	if len(fields) == 0 {
+		return Relation{}, fmt.Errorf("list bound has no clustering key values")
+	}
+
+	// Cassandra won't restrict a column with both single column and tuple relations, so the bounds of a clustering key
+	// of several columns are always tuples, even those on a prefix of one column
+	if len(clusteringKeys) == 1 {
+		switch {
+		case lower && b.Exclusive:
+			return GT(fields[0], values[0]), nil
+		case lower:
+			return GTE(fields[0], values[0]), nil
+		case b.Exclusive:
+			return LT(fields[0], values[0]), nil
+		default:
+			return LTE(fields[0], values[0]), nil
+		}
+	}
+
+	tuple := "(" + strings.Join(fields, ", ") + ")"
+	switch {
+	case lower && b.Exclusive:
+		return TupleGT(tuple, values...), nil
+	case lower:
+		return TupleGTE(tuple, values...), nil
+	case b.Exclusive:
+		return TupleLT(tuple, values...), nil
+	default:
+		return TupleLTE(tuple, values...), nil
+	}
+}
+
+// rowToMap returns the columns of a row read into a struct, or a map
+func rowToMap(row interface{}) (map[string]interface{}, bool) {
+	if m, ok := row.(map[string]interface{}); ok {
+		return m, true
+	}
+	return r.StructToMap(row)
+}
+
+// columnValue returns the value of a column, which is matched case insensitively as Cassandra does
+func columnValue(row map[string]interface{}, column string) (interface{}, bool) {
+	if value, ok := row[column]; ok {
+		return value, true
+	}
+	for name, value := range row {
+		if strings.EqualFold(name, column) {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+// tupleFields returns the columns of a tuple relation's field, such as "(a, b)"
+func tupleFields(field string) []string {
+	fields := strings.Split(strings.Trim(strings.TrimSpace(field), "()"), ",")
+	for i := range fields {
+		fields[i] = strings.TrimSpace(fields[i])
+	}
+	return fields
+}
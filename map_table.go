@@ -1,8 +1,16 @@
 package gocassa
 
+import (
+	"fmt"
+	"reflect"
+)
+
 type mapT struct {
 	t       Table
 	idField string
+	// versionField is the field holding the version of the rows, if their type has one
+	versionField string
+	options      Options
 }
 
 func (m *mapT) Table() Table                        { return m.t }
@@ -14,14 +22,28 @@ func (m *mapT) CreateStatement() (Statement, error) { return m.Table().CreateSta
 func (m *mapT) CreateIfNotExistStatement() (Statement, error) {
 	return m.Table().CreateIfNotExistStatement()
 }
+func (m *mapT) AlterOptions() error { return m.Table().AlterOptions() }
+func (m *mapT) AlterOptionsStatement() (Statement, error) {
+	return m.Table().AlterOptionsStatement()
+}
 
 func (m *mapT) Update(id interface{}, ma map[string]interface{}) Op {
+	if m.versionField != "" {
+		return versionedUpdate(m.Table(), []Relation{Eq(m.idField, id)}, m.versionField, ma)
+	}
 	return m.Table().
 		Where(Eq(m.idField, id)).
 		Update(ma)
 }
 
 func (m *mapT) Set(v interface{}) Op {
+	if m.versionField != "" {
+		row, ok := toMap(v)
+		if !ok {
+			panic("Can't set: not able to convert")
+		}
+		return versionedSet(m.Table(), []string{m.idField}, m.versionField, row)
+	}
 	return m.Table().
 		Set(v)
 }
@@ -39,14 +61,73 @@ func (m *mapT) Read(id, pointer interface{}) Op {
 }
 
 func (m *mapT) MultiRead(ids []interface{}, pointerToASlice interface{}) Op {
+	if m.options.MultiRead == MultiReadFanOut {
+		return m.fanOutRead(ids, pointerToASlice)
+	}
 	return m.Table().
 		Where(In(m.idField, ids...)).
 		Read(pointerToASlice)
 }
 
+// fanOutRead reads each of ids with its own single-partition query, running them concurrently, and gathers the rows
+// into pointerToASlice in the order the ids were given.
+func (m *mapT) fanOutRead(ids []interface{}, pointerToASlice interface{}) Op {
+	if err := allocateNilReference(pointerToASlice); err != nil {
+		return errOp{err: err}
+	}
+	sliceType := getNonPtrType(reflect.TypeOf(pointerToASlice))
+	if sliceType.Kind() != reflect.Slice {
+		return errOp{err: fmt.Errorf("can't read into %T: expected a pointer to a slice", pointerToASlice)}
+	}
+
+	qe := m.Table().Where(In(m.idField, ids...)).Read(pointerToASlice).QueryExecutor()
+	return newLazyOp(qe, func(opts Options) error {
+		// The reads are built afresh each time the op runs, so that runs of it don't share results
+		results := make([]reflect.Value, len(ids))
+		reads := Noop()
+		for i, id := range ids {
+			results[i] = reflect.New(sliceType)
+			reads = reads.Add(m.Table().Where(Eq(m.idField, id)).Read(results[i].Interface()))
+		}
+		parallelism := m.options.Merge(opts).Parallelism
+		if err := reads.WithOptions(opts).RunConcurrentlyWithContext(opts.Context, parallelism); err != nil {
+			return err
+		}
+
+		rows := reflect.MakeSlice(sliceType, 0, len(ids))
+		var missing []interface{}
+		for i, result := range results {
+			if result.Elem().Len() == 0 {
+				missing = append(missing, ids[i])
+				continue
+			}
+			rows = reflect.AppendSlice(rows, result.Elem())
+		}
+
+		out := reflect.ValueOf(pointerToASlice)
+		for out.Kind() == reflect.Ptr {
+			out = out.Elem()
+		}
+		out.Set(rows)
+		if len(missing) > 0 {
+			return PartialResultError{Missing: missing}
+		}
+		return nil
+	})
+}
+
 func (m *mapT) WithOptions(o Options) MapTable {
 	return &mapT{
-		t:       m.Table().WithOptions(o),
-		idField: m.idField,
+		t:            m.Table().WithOptions(o),
+		idField:      m.idField,
+		versionField: m.versionField,
+		options:      m.options.Merge(o),
 	}
 }
+
+// unversioned returns a copy of the table whose writes aren't compare-and-set on the rows' version field
+func (m *mapT) unversioned() MapTable {
+	c := *m
+	c.versionField = ""
+	return &c
+}
@@ -1,8 +1,13 @@
 package gocassa
 
 import (
+	"context"
 	"reflect"
+	"sync"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestMapTable(t *testing.T) {
@@ -102,3 +107,31 @@ func TestMapTableMultiRead(t *testing.T) {
 		t.Fatalf("Expected to find jane, got %v", (*customers)[1])
 	}
 }
+
+func TestMapTableMultiReadFanOutStatements(t *testing.T) {
+	var (
+		mtx     sync.Mutex
+		queries []string
+		values  []interface{}
+	)
+	qe := funcQE{fn: func(opts Options, stmt Statement) error {
+		mtx.Lock()
+		defer mtx.Unlock()
+		queries = append(queries, stmt.Query())
+		values = append(values, stmt.Values()...)
+		return nil
+	}}
+	tbl := NewConnection(qe).KeySpace("ks").MapTable("customers", "Id", Customer{})
+
+	// Each key is read with its own single-partition query, rather than one IN query
+	res := []Customer{}
+	err := tbl.WithOptions(Options{MultiRead: MultiReadFanOut}).
+		MultiRead([]interface{}{"1", "2", "3"}, &res).
+		RunWithContext(context.Background())
+	assert.Equal(t, PartialResultError{Missing: []interface{}{"1", "2", "3"}}, err)
+	require.Len(t, queries, 3)
+	for _, query := range queries {
+		assert.Equal(t, "SELECT id, name FROM ks.customers_map_Id WHERE id = ?", query)
+	}
+	assert.ElementsMatch(t, []interface{}{"1", "2", "3"}, values)
+}
@@ -0,0 +1,261 @@
+package gocassa
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ColumnKind describes the role a column plays in its table's primary key.
+type ColumnKind string
+
+const (
+	PartitionKeyColumn ColumnKind = "partition_key"
+	ClusteringColumn   ColumnKind = "clustering"
+	RegularColumn      ColumnKind = "regular"
+	StaticColumn       ColumnKind = "static"
+)
+
+// ColumnMetadata describes a column of a table as it exists in Cassandra.
+type ColumnMetadata struct {
+	Name string
+	// Type is the CQL type of the column, eg. "text" or "map<text, int>"
+	Type string
+	Kind ColumnKind
+	// Position is the index of the column within the partition key or clustering columns. It is -1 for other columns
+	Position int
+	// ClusteringOrder is the order rows are stored in by a clustering column
+	ClusteringOrder ColumnDirection
+}
+
+// IsStatic returns whether the column is shared by all rows of a partition.
+func (c ColumnMetadata) IsStatic() bool {
+	return c.Kind == StaticColumn
+}
+
+// IndexMetadata describes a secondary index of a table.
+type IndexMetadata struct {
+	Name string
+	// Kind is one of "COMPOSITES", "KEYS" or "CUSTOM"
+	Kind string
+	// Options holds the index's options, including its "target" column
+	Options map[string]string
+}
+
+// TableMetadata describes a table as it exists in Cassandra.
+type TableMetadata struct {
+	KeySpace string
+	Name     string
+	// Columns holds all the table's columns, ordered by name
+	Columns []ColumnMetadata
+	// PartitionKey holds the names of the partition key columns, in order
+	PartitionKey []string
+	// ClusteringColumns holds the clustering columns and the order rows are stored in by each, in order
+	ClusteringColumns []ClusteringOrderColumn
+	Indexes           []IndexMetadata
+	Options           TableOptions
+}
+
+// TableNotFoundError is returned when asked for the metadata of a table which doesn't exist.
+type TableNotFoundError struct {
+	KeySpace string
+	Table    string
+}
+
+func (e TableNotFoundError) Error() string {
+	return fmt.Sprintf("table %s.%s does not exist", e.KeySpace, e.Table)
+}
+
+type columnMetadataMarshal struct {
+	ColumnName      string `cql:"column_name"`
+	Type            string `cql:"type"`
+	Kind            string `cql:"kind"`
+	Position        int    `cql:"position"`
+	ClusteringOrder string `cql:"clustering_order"`
+}
+
+type indexMetadataMarshal struct {
+	IndexName string            `cql:"index_name"`
+	Kind      string            `cql:"kind"`
+	Options   map[string]string `cql:"options"`
+}
+
+type tableOptionsMarshal struct {
+	BloomFilterFPChance float64           `cql:"bloom_filter_fp_chance"`
+	Caching             map[string]string `cql:"caching"`
+	Comment             string            `cql:"comment"`
+	Compaction          map[string]string `cql:"compaction"`
+	Compression         map[string]string `cql:"compression"`
+	DefaultTimeToLive   int               `cql:"default_time_to_live"`
+	GCGraceSeconds      int               `cql:"gc_grace_seconds"`
+}
+
+// systemSchema is a SchemaSource which reads Cassandra's system_schema tables
+type systemSchema struct {
+	qe QueryExecutor
+}
+
+func (s systemSchema) TableColumns(keySpace, table string) ([]ColumnMetadata, error) {
+	if s.qe == nil {
+		return nil, fmt.Errorf("no query executor configured")
+	}
+
+	res := []columnMetadataMarshal{}
+	stmt := SelectStatement{
+		keyspace: "system_schema",
+		table:    "columns",
+		fields:   []string{"column_name", "type", "kind", "position", "clustering_order"},
+		where: []Relation{
+			Eq("keyspace_name", keySpace),
+			Eq("table_name", strings.ToLower(table)),
+		},
+	}
+	if err := s.qe.Query(stmt, NewScanner(stmt, &res)); err != nil {
+		return nil, err
+	}
+
+	ret := make([]ColumnMetadata, 0, len(res))
+	for _, v := range res {
+		ret = append(ret, ColumnMetadata{
+			Name:     v.ColumnName,
+			Type:     v.Type,
+			Kind:     ColumnKind(v.Kind),
+			Position: v.Position,
+			// Only descending columns need marking, as the zero value is ascending
+			ClusteringOrder: ColumnDirection(strings.ToLower(v.ClusteringOrder) == "desc"),
+		})
+	}
+	return ret, nil
+}
+
+// TableMetadata reads the columns, indexes and options of a table. If the table doesn't exist, a TableNotFoundError
+// is returned.
+func (s systemSchema) TableMetadata(keySpace, table string) (TableMetadata, error) {
+	columns, err := s.TableColumns(keySpace, table)
+	if err != nil {
+		return TableMetadata{}, err
+	}
+	if len(columns) == 0 {
+		return TableMetadata{}, TableNotFoundError{KeySpace: keySpace, Table: table}
+	}
+
+	where := []Relation{
+		Eq("keyspace_name", keySpace),
+		Eq("table_name", strings.ToLower(table)),
+	}
+	indexes := []indexMetadataMarshal{}
+	stmt := SelectStatement{
+		keyspace: "system_schema",
+		table:    "indexes",
+		fields:   []string{"index_name", "kind", "options"},
+		where:    where,
+	}
+	if err := s.qe.Query(stmt, NewScanner(stmt, &indexes)); err != nil {
+		return TableMetadata{}, err
+	}
+
+	options := []tableOptionsMarshal{}
+	stmt = SelectStatement{
+		keyspace: "system_schema",
+		table:    "tables",
+		fields: []string{"bloom_filter_fp_chance", "caching", "comment", "compaction", "compression",
+			"default_time_to_live", "gc_grace_seconds"},
+		where: where,
+	}
+	if err := s.qe.Query(stmt, NewScanner(stmt, &options)); err != nil {
+		return TableMetadata{}, err
+	}
+
+	md := newTableMetadata(keySpace, table, columns)
+	for _, idx := range indexes {
+		md.Indexes = append(md.Indexes, IndexMetadata{
+			Name:    idx.IndexName,
+			Kind:    idx.Kind,
+			Options: idx.Options,
+		})
+	}
+	if len(options) > 0 {
+		md.Options = options[0].tableOptions()
+	}
+	return md, nil
+}
+
+// newTableMetadata returns the metadata of a table with the given columns, deriving its primary key from them
+func newTableMetadata(keySpace, table string, columns []ColumnMetadata) TableMetadata {
+	sorted := make([]ColumnMetadata, len(columns))
+	copy(sorted, columns)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+
+	md := TableMetadata{
+		KeySpace:          keySpace,
+		Name:              table,
+		Columns:           sorted,
+		PartitionKey:      keyColumns(columns, PartitionKeyColumn),
+		ClusteringColumns: []ClusteringOrderColumn{},
+		Indexes:           []IndexMetadata{},
+	}
+	directions := map[string]ColumnDirection{}
+	for _, c := range columns {
+		directions[c.Name] = c.ClusteringOrder
+	}
+	for _, name := range keyColumns(columns, ClusteringColumn) {
+		md.ClusteringColumns = append(md.ClusteringColumns, ClusteringOrderColumn{
+			Column:    name,
+			Direction: directions[name],
+		})
+	}
+	return md
+}
+
+// tableOptions converts the options stored in system_schema.tables into TableOptions
+func (m tableOptionsMarshal) tableOptions() TableOptions {
+	gcGrace := time.Duration(m.GCGraceSeconds) * time.Second
+	opts := TableOptions{
+		DefaultTTL:          time.Duration(m.DefaultTimeToLive) * time.Second,
+		GCGrace:             &gcGrace,
+		BloomFilterFPChance: m.BloomFilterFPChance,
+		Comment:             m.Comment,
+	}
+	if len(m.Caching) > 0 {
+		opts.Caching = &CachingOptions{
+			Keys:             m.Caching["keys"],
+			RowsPerPartition: m.Caching["rows_per_partition"],
+		}
+	}
+	if len(m.Compaction) > 0 {
+		opts.Compaction = &CompactionOptions{Class: m.Compaction["class"], Options: map[string]string{}}
+		for k, v := range m.Compaction {
+			if k != "class" {
+				opts.Compaction.Options[k] = v
+			}
+		}
+	}
+	if len(m.Compression) > 0 {
+		opts.Compression = &CompressionOptions{
+			Class:    m.Compression["class"],
+			Disabled: m.Compression["enabled"] == "false",
+		}
+		if chunkLength, err := strconv.Atoi(m.Compression["chunk_length_in_kb"]); err == nil {
+			opts.Compression.ChunkLengthKB = chunkLength
+		}
+	}
+	return opts
+}
+
+// keyColumns returns the names of the columns of the given kind, ordered by their position in the key
+func keyColumns(columns []ColumnMetadata, kind ColumnKind) []string {
+	keyCols := []ColumnMetadata{}
+	for _, c := range columns {
+		if c.Kind == kind {
+			keyCols = append(keyCols, c)
+		}
+	}
+	sort.Slice(keyCols, func(i, j int) bool { return keyCols[i].Position < keyCols[j].Position })
+	names := make([]string, len(keyCols))
+	for i, c := range keyCols {
+		names[i] = c.Name
+	}
+	return names
+}
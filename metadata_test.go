@@ -0,0 +1,106 @@
+package gocassa
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// schemaQE is a QueryExecutor which answers selects from the system_schema tables with the rows it holds for them
+func schemaQE(rows map[string][]map[string]interface{}) QueryExecutor {
+	return funcQE{
+		fn: func(opts Options, stmt Statement) error {
+			return errors.New("unexpected statement: " + stmt.Query())
+		},
+		query: func(stmt Statement, scanner Scanner) error {
+			sel := stmt.(SelectStatement)
+			_, err := scanner.ScanIter(newMockIterator(rows[sel.table], sel.fields))
+			return err
+		},
+	}
+}
+
+func TestTableMetadata(t *testing.T) {
+	ks := NewConnection(schemaQE(map[string][]map[string]interface{}{
+		"columns": {
+			{"column_name": "id", "type": "text", "kind": "partition_key", "position": 0, "clustering_order": "none"},
+			{"column_name": "tag", "type": "text", "kind": "clustering", "position": 0, "clustering_order": "desc"},
+			{"column_name": "name", "type": "text", "kind": "regular", "position": -1, "clustering_order": "none"},
+			{"column_name": "region", "type": "text", "kind": "static", "position": -1, "clustering_order": "none"},
+		},
+		"indexes": {
+			{"index_name": "customers_name_idx", "kind": "COMPOSITES", "options": map[string]string{"target": "name"}},
+		},
+		"tables": {
+			{
+				"bloom_filter_fp_chance": 0.01,
+				"caching":                map[string]string{"keys": "ALL", "rows_per_partition": "NONE"},
+				"comment":                "",
+				"compaction":             map[string]string{"class": "org.apache.cassandra.db.compaction.SizeTieredCompactionStrategy", "max_threshold": "32"},
+				"compression":            map[string]string{"class": "org.apache.cassandra.io.compress.LZ4Compressor", "chunk_length_in_kb": "64"},
+				"default_time_to_live":   3600,
+				"gc_grace_seconds":       864000,
+			},
+		},
+	})).KeySpace("ks")
+
+	md, err := ks.TableMetadata("customers")
+	require.NoError(t, err)
+	assert.Equal(t, "ks", md.KeySpace)
+	assert.Equal(t, []string{"id"}, md.PartitionKey)
+	assert.Equal(t, []ClusteringOrderColumn{{Column: "tag", Direction: DESC}}, md.ClusteringColumns)
+	require.Len(t, md.Columns, 4)
+	assert.Equal(t, []string{"id", "name", "region", "tag"}, []string{md.Columns[0].Name, md.Columns[1].Name, md.Columns[2].Name, md.Columns[3].Name})
+	assert.True(t, md.Columns[2].IsStatic())
+	assert.Equal(t, []IndexMetadata{{Name: "customers_name_idx", Kind: "COMPOSITES", Options: map[string]string{"target": "name"}}}, md.Indexes)
+
+	gcGrace := 10 * 24 * time.Hour
+	assert.Equal(t, TableOptions{
+		Compaction: &CompactionOptions{
+			Class:   "org.apache.cassandra.db.compaction.SizeTieredCompactionStrategy",
+			Options: map[string]string{"max_threshold": "32"},
+		},
+		Compression:         &CompressionOptions{Class: "org.apache.cassandra.io.compress.LZ4Compressor", ChunkLengthKB: 64},
+		DefaultTTL:          time.Hour,
+		GCGrace:             &gcGrace,
+		Caching:             &CachingOptions{Keys: "ALL", RowsPerPartition: "NONE"},
+		BloomFilterFPChance: 0.01,
+	}, md.Options)
+}
+
+func TestTableMetadataNotFound(t *testing.T) {
+	ks := NewConnection(schemaQE(nil)).KeySpace("ks")
+	_, err := ks.TableMetadata("customers")
+	assert.Equal(t, TableNotFoundError{KeySpace: "ks", Table: "customers"}, err)
+}
+
+func TestMockTableMetadata(t *testing.T) {
+	ks := NewMockKeySpace()
+	ks.Table("customers", Customer2{}, Keys{
+		PartitionKeys:     []string{"Name"},
+		ClusteringColumns: []string{"Tag", "Id"},
+	})
+	ks.MapTable("customers", "Id", Customer{})
+
+	md, err := ks.TableMetadata("customers__Name__Tag_Id")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"name"}, md.PartitionKey)
+	assert.Equal(t, []ClusteringOrderColumn{{Column: "tag", Direction: ASC}, {Column: "id", Direction: ASC}}, md.ClusteringColumns)
+	assert.Equal(t, []ColumnMetadata{
+		{Name: "id", Type: "varchar", Kind: ClusteringColumn, Position: 1},
+		{Name: "name", Type: "varchar", Kind: PartitionKeyColumn, Position: 0},
+		{Name: "tag", Type: "varchar", Kind: ClusteringColumn, Position: 0},
+	}, md.Columns)
+
+	md, err = ks.TableMetadata("customers_map_Id")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"id"}, md.PartitionKey)
+	assert.Empty(t, md.ClusteringColumns)
+
+	_, err = ks.TableMetadata("orders")
+	var notFound TableNotFoundError
+	assert.True(t, errors.As(err, &notFound))
+}
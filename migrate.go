@@ -0,0 +1,233 @@
+package gocassa
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// SchemaSource describes the live schema of tables. A Migrator compares table definitions against it, and by default
+// reads it from Cassandra's system_schema tables.
+type SchemaSource interface {
+	// TableColumns returns the columns of the named table, or none if the table doesn't exist
+	TableColumns(keySpace, table string) ([]ColumnMetadata, error)
+}
+
+// Migration holds the statements needed to bring a table's schema in line with its definition.
+type Migration struct {
+	KeySpace   string
+	Table      string
+	Statements []Statement
+	qe         QueryExecutor
+}
+
+// SchemaConflictError is returned when a table's live schema differs from its definition in a way which can't be
+// migrated, such as a column changing type or a change to the primary key.
+type SchemaConflictError struct {
+	KeySpace  string
+	Table     string
+	Conflicts []string
+}
+
+func (e SchemaConflictError) Error() string {
+	return fmt.Sprintf("can't migrate %s.%s: %s", e.KeySpace, e.Table, strings.Join(e.Conflicts, "; "))
+}
+
+// Migrator brings the live schema of the tables defined on a keyspace in line with their definitions. Tables which
+// don't exist are created and columns missing from existing tables are added. Columns which are no longer defined are
+// left in place.
+type Migrator struct {
+	keySpace string
+	// tables returns the tables defined on the keyspace, or a TableConflictError if any of them conflict
+	tables func() ([]Table, error)
+	source SchemaSource
+	qe     QueryExecutor
+}
+
+// definedTable is a table whose definition a Migrator can compare with its live schema
+type definedTable interface {
+	Table
+	// tableKeys returns the keys the table was defined with
+	tableKeys() Keys
+	// tableFields returns the fields of the table's rows, and a value of the type of each
+	tableFields() ([]string, []interface{})
+}
+
+// WithSchemaSource returns a Migrator which reads the live schema from source, rather than from the keyspace.
+func (m *Migrator) WithSchemaSource(source SchemaSource) *Migrator {
+	return &Migrator{
+		keySpace: m.keySpace,
+		tables:   m.tables,
+		source:   source,
+		qe:       m.qe,
+	}
+}
+
+// Plan works out the migrations needed for each table, without running them. Tables which are already up to date
+// are omitted. If any table can't be migrated, a SchemaConflictError is returned.
+func (m *Migrator) Plan() ([]Migration, error) {
+	tables, err := m.tables()
+	if err != nil {
+		return nil, err
+	}
+	migrations := []Migration{}
+	for _, tbl := range tables {
+		def, ok := tbl.(definedTable)
+		if !ok {
+			return nil, fmt.Errorf("can't migrate table %s: unsupported table type %T", tbl.Name(), tbl)
+		}
+		live, err := m.source.TableColumns(m.keySpace, def.Name())
+		if err != nil {
+			return nil, err
+		}
+		stmts, err := migrationStatements(m.keySpace, def, live)
+		if err != nil {
+			return nil, err
+		}
+		if len(stmts) == 0 {
+			continue
+		}
+		migrations = append(migrations, Migration{
+			KeySpace:   m.keySpace,
+			Table:      def.Name(),
+			Statements: stmts,
+			qe:         m.qe,
+		})
+	}
+	return migrations, nil
+}
+
+// DryRun writes the CQL which Apply would run to w.
+func (m *Migrator) DryRun(w io.Writer) error {
+	migrations, err := m.Plan()
+	if err != nil {
+		return err
+	}
+	for _, migration := range migrations {
+		for _, stmt := range migration.Statements {
+			if _, err := fmt.Fprintf(w, "%s;\n", scriptStatement(stmt)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Apply plans and runs the migrations for each table. Nothing is run if any table can't be migrated.
+func (m *Migrator) Apply(ctx context.Context) error {
+	migrations, err := m.Plan()
+	if err != nil {
+		return err
+	}
+	for _, migration := range migrations {
+		if migration.qe == nil {
+			return fmt.Errorf("migrating %s.%s: no query executor configured", migration.KeySpace, migration.Table)
+		}
+		for _, stmt := range migration.Statements {
+			if err := migration.qe.ExecuteWithOptions(Options{Context: ctx}, stmt); err != nil {
+				return fmt.Errorf("migrating %s.%s: %w", migration.KeySpace, migration.Table, err)
+			}
+		}
+	}
+	return nil
+}
+
+// migrationStatements returns the statements needed to bring a table with the live columns given in line with the
+// table's definition.
+func migrationStatements(keySpace string, t definedTable, live []ColumnMetadata) ([]Statement, error) {
+	if len(live) == 0 {
+		stmt, err := t.CreateIfNotExistStatement()
+		if err != nil {
+			return nil, err
+		}
+		return []Statement{stmt}, nil
+	}
+
+	conflicts := []string{}
+	defined := func(names []string) []string {
+		lower := make([]string, len(names))
+		for i, name := range names {
+			lower[i] = strings.ToLower(name)
+		}
+		return lower
+	}
+	keys := t.tableKeys()
+	if want, got := defined(keys.PartitionKeys), keyColumns(live, PartitionKeyColumn); !equalStrings(want, got) {
+		conflicts = append(conflicts, fmt.Sprintf("partition key is (%s) but defined as (%s)",
+			strings.Join(got, ", "), strings.Join(want, ", ")))
+	}
+	if want, got := defined(keys.ClusteringColumns), keyColumns(live, ClusteringColumn); !equalStrings(want, got) {
+		conflicts = append(conflicts, fmt.Sprintf("clustering columns are (%s) but defined as (%s)",
+			strings.Join(got, ", "), strings.Join(want, ", ")))
+	}
+
+	liveTypes := make(map[string]string, len(live))
+	for _, c := range live {
+		liveTypes[strings.ToLower(c.Name)] = c.Type
+	}
+	stmts := []Statement{}
+	fields, values := t.tableFields()
+	for i, field := range fields {
+		name := strings.ToLower(field)
+		typ, err := stringTypeOf(values[i])
+		if err != nil {
+			return nil, err
+		}
+		liveType, exists := liveTypes[name]
+		switch {
+		case !exists:
+			stmts = append(stmts, cqlStatement{
+				query: fmt.Sprintf("ALTER TABLE %s.%s ADD %s %s", keySpace, t.Name(), name, typ),
+			})
+		case normalizeCQLType(liveType) != normalizeCQLType(typ):
+			conflicts = append(conflicts, fmt.Sprintf("column %s is %s but defined as %s", name, liveType, typ))
+		}
+	}
+
+	if len(conflicts) > 0 {
+		return nil, SchemaConflictError{
+			KeySpace:  keySpace,
+			Table:     t.Name(),
+			Conflicts: conflicts,
+		}
+	}
+	return stmts, nil
+}
+
+// keySpaceSchema is a SchemaSource which reads the live schema of tables from the metadata of their keyspace
+type keySpaceSchema struct {
+	ks KeySpace
+}
+
+func (s keySpaceSchema) TableColumns(keySpace, table string) ([]ColumnMetadata, error) {
+	md, err := s.ks.TableMetadata(table)
+	if _, ok := err.(TableNotFoundError); ok {
+		return nil, nil
+	}
+	return md.Columns, err
+}
+
+// scriptStatement returns the query of stmt without its terminating semicolon, if any, so statements can be written
+// out consistently as a CQL script
+func scriptStatement(stmt Statement) string {
+	return strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(stmt.Query()), ";"))
+}
+
+// normalizeCQLType returns a canonical form of a CQL type, so types Cassandra treats as the same compare equal
+func normalizeCQLType(typ string) string {
+	typ = strings.ToLower(strings.Replace(typ, " ", "", -1))
+	return strings.Replace(typ, "varchar", "text", -1)
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,195 @@
+package gocassa
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSchema is a SchemaSource holding the columns of each table, keyed by "keyspace.table"
+type fakeSchema map[string][]ColumnMetadata
+
+func (s fakeSchema) TableColumns(keySpace, table string) ([]ColumnMetadata, error) {
+	return s[keySpace+"."+strings.ToLower(table)], nil
+}
+
+type migrationCustomer struct {
+	Id      string
+	Name    string
+	Age     int
+	Tags    []string
+	Balance map[string]int64
+}
+
+func migrationTestKeySpace(executed *[]string) KeySpace {
+	qe := funcQE{fn: func(opts Options, stmt Statement) error {
+		*executed = append(*executed, stmt.Query())
+		return nil
+	}}
+	return NewConnection(qe).KeySpace("ks")
+}
+
+func TestMigratorPlan(t *testing.T) {
+	var executed []string
+	ks := migrationTestKeySpace(&executed)
+	ks.MapTable("customers", "Id", migrationCustomer{})
+	ks.MultimapTable("orders", "Name", "Id", Customer2{})
+
+	schema := fakeSchema{
+		"ks.customers_map_id": {
+			{Name: "id", Type: "text", Kind: PartitionKeyColumn, Position: 0},
+			{Name: "name", Type: "text", Kind: RegularColumn, Position: -1},
+			{Name: "legacy", Type: "int", Kind: RegularColumn, Position: -1},
+		},
+	}
+	migrations, err := ks.Migrator().WithSchemaSource(schema).Plan()
+	require.NoError(t, err)
+	require.Len(t, migrations, 2)
+
+	// Missing columns are added, and columns which are no longer defined are left alone
+	assert.Equal(t, "customers_map_Id", migrations[0].Table)
+	queries := []string{}
+	for _, stmt := range migrations[0].Statements {
+		queries = append(queries, stmt.Query())
+	}
+	assert.Equal(t, []string{
+		"ALTER TABLE ks.customers_map_Id ADD age int",
+		"ALTER TABLE ks.customers_map_Id ADD balance map<varchar, bigint>",
+		"ALTER TABLE ks.customers_map_Id ADD tags list<varchar>",
+	}, queries)
+
+	// Tables which don't exist are created
+	require.Len(t, migrations[1].Statements, 1)
+	assert.True(t, strings.HasPrefix(migrations[1].Statements[0].Query(), "CREATE TABLE IF NOT EXISTS ks.orders_multimap_Name_Id"))
+
+	// Nothing is run until the migrations are applied
+	assert.Empty(t, executed)
+}
+
+func TestMigratorUpToDate(t *testing.T) {
+	var executed []string
+	ks := migrationTestKeySpace(&executed)
+	ks.MapTable("customers", "Id", Customer{})
+
+	schema := fakeSchema{
+		"ks.customers_map_id": {
+			{Name: "id", Type: "text", Kind: PartitionKeyColumn, Position: 0},
+			{Name: "name", Type: "varchar", Kind: RegularColumn, Position: -1},
+		},
+	}
+	migrations, err := ks.Migrator().WithSchemaSource(schema).Plan()
+	require.NoError(t, err)
+	assert.Empty(t, migrations)
+}
+
+func TestMigratorConflicts(t *testing.T) {
+	var executed []string
+	ks := migrationTestKeySpace(&executed)
+	ks.MapTable("customers", "Id", migrationCustomer{})
+
+	schema := fakeSchema{
+		"ks.customers_map_id": {
+			{Name: "name", Type: "text", Kind: PartitionKeyColumn, Position: 0},
+			{Name: "id", Type: "text", Kind: ClusteringColumn, Position: 0},
+			{Name: "age", Type: "text", Kind: RegularColumn, Position: -1},
+		},
+	}
+	migrator := ks.Migrator().WithSchemaSource(schema)
+	_, err := migrator.Plan()
+	var conflictErr SchemaConflictError
+	require.True(t, errors.As(err, &conflictErr), "expected a SchemaConflictError, got %v", err)
+	assert.Equal(t, "customers_map_Id", conflictErr.Table)
+	assert.Equal(t, []string{
+		"partition key is (name) but defined as (id)",
+		"clustering columns are (id) but defined as ()",
+		"column age is text but defined as int",
+	}, conflictErr.Conflicts)
+
+	// Nothing is applied when any table conflicts
+	assert.Error(t, migrator.Apply(context.Background()))
+	assert.Empty(t, executed)
+}
+
+func TestMigratorApply(t *testing.T) {
+	var executed []string
+	ks := migrationTestKeySpace(&executed)
+	ks.MapTable("customers", "Id", migrationCustomer{})
+
+	schema := fakeSchema{
+		"ks.customers_map_id": {
+			{Name: "id", Type: "text", Kind: PartitionKeyColumn, Position: 0},
+			{Name: "name", Type: "text", Kind: RegularColumn, Position: -1},
+			{Name: "age", Type: "int", Kind: RegularColumn, Position: -1},
+			{Name: "balance", Type: "map<text, bigint>", Kind: RegularColumn, Position: -1},
+		},
+	}
+	migrator := ks.Migrator().WithSchemaSource(schema)
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, migrator.DryRun(buf))
+	assert.Equal(t, "ALTER TABLE ks.customers_map_Id ADD tags list<varchar>;\n", buf.String())
+	assert.Empty(t, executed)
+
+	require.NoError(t, migrator.Apply(context.Background()))
+	assert.Equal(t, []string{"ALTER TABLE ks.customers_map_Id ADD tags list<varchar>"}, executed)
+}
+
+func TestMigratorSystemSchema(t *testing.T) {
+	var stmts []Statement
+	qe := funcQE{fn: func(opts Options, stmt Statement) error {
+		stmts = append(stmts, stmt)
+		return nil
+	}}
+	ks := NewConnection(qe).KeySpace("ks")
+	ks.MapTable("customers", "Id", Customer{})
+
+	// The live schema is read from system_schema, and a table with no columns there is created
+	migrations, err := ks.Migrator().Plan()
+	require.NoError(t, err)
+	require.Len(t, stmts, 1)
+	assert.Equal(t, "SELECT column_name, type, kind, position, clustering_order FROM system_schema.columns WHERE keyspace_name = ? AND table_name = ?", stmts[0].Query())
+	assert.Equal(t, []interface{}{"ks", "customers_map_id"}, stmts[0].Values())
+	require.Len(t, migrations, 1)
+	assert.True(t, strings.HasPrefix(migrations[0].Statements[0].Query(), "CREATE TABLE IF NOT EXISTS"))
+
+}
+
+func TestMigratorKeySpaceTables(t *testing.T) {
+	var executed []string
+	ks := migrationTestKeySpace(&executed)
+	ks.MapTable("customers", "Id", Customer{})
+	ks.MultimapTable("orders", "Name", "Id", Customer2{})
+	ks.TimeSeriesTable("trips", "Time", "Id", time.Minute, Trip{})
+	// A copy of a table is only migrated once it's registered
+	ks.MapTable("customers", "Id", Customer{}).WithOptions(Options{TableName: "customers_copy"})
+
+	// Every table defined on the keyspace is migrated, including those of recipes
+	migrations, err := ks.Migrator().WithSchemaSource(fakeSchema{}).Plan()
+	require.NoError(t, err)
+	tables := []string{}
+	for _, migration := range migrations {
+		tables = append(tables, migration.Table)
+	}
+	assert.Equal(t, []string{"customers_map_Id", "orders_multimap_Name_Id", "trips_timeSeries_Time_Id_1m0s"}, tables)
+
+	// Tables which conflict can't be migrated
+	ks.RegisterTable(ks.MultimapTable("customers", "Name", "Id", Customer{}).WithOptions(Options{TableName: "customers_map_Id"}).Table())
+	_, err = ks.Migrator().WithSchemaSource(fakeSchema{}).Plan()
+	var conflictErr TableConflictError
+	assert.True(t, errors.As(err, &conflictErr), "expected a TableConflictError, got %v", err)
+
+	// The live schema of a mock keyspace's tables is their definition, so they're always up to date
+	mock := NewMockKeySpace()
+	mock.MapTable("customers", "Id", Customer{})
+	mock.MultimapTable("orders", "Name", "Id", Customer2{})
+	migrations, err = mock.Migrator().Plan()
+	require.NoError(t, err)
+	assert.Empty(t, migrations)
+	assert.NoError(t, mock.Migrator().Apply(context.Background()))
+}
@@ -7,6 +7,7 @@ import (
 	"reflect"
 	"strings"
 	"sync"
+	"time"
 
 	"context"
 
@@ -14,6 +15,64 @@ import (
 	"github.com/google/btree"
 )
 
+// MockClock is a Clock for tests, whose time only moves when it's advanced.
+type MockClock struct {
+	mtx     sync.Mutex
+	now     time.Time
+	waiters []mockClockWaiter
+}
+
+type mockClockWaiter struct {
+	at time.Time
+	c  chan time.Time
+}
+
+// NewMockClock returns a MockClock which tells the time now.
+func NewMockClock(now time.Time) *MockClock {
+	return &MockClock{now: now}
+}
+
+func (c *MockClock) Now() time.Time {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.now
+}
+
+func (c *MockClock) After(d time.Duration) <-chan time.Time {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	ch := make(chan time.Time, 1)
+	if d <= 0 {
+		ch <- c.now
+		return ch
+	}
+	c.waiters = append(c.waiters, mockClockWaiter{at: c.now.Add(d), c: ch})
+	return ch
+}
+
+// Advance moves the clock on by d, firing the channels returned by After which are due.
+func (c *MockClock) Advance(d time.Duration) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.now = c.now.Add(d)
+	waiters := c.waiters[:0]
+	for _, w := range c.waiters {
+		if w.at.After(c.now) {
+			waiters = append(waiters, w)
+			continue
+		}
+		w.c <- c.now
+	}
+	c.waiters = waiters
+}
+
+// waiting returns the number of channels returned by After which haven't fired yet
+func (c *MockClock) waiting() int {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return len(c.waiters)
+}
+
 // MockKeySpace implements the KeySpace interface and constructs in-memory tables.
 type mockKeySpace struct {
 	k
@@ -72,6 +131,10 @@ func (m mockOp) RunAtomicallyWithContext(ctx context.Context) error {
 	return m.RunLoggedBatchWithContext(ctx)
 }
 
+func (m mockOp) RunConcurrentlyWithContext(ctx context.Context, maxParallel int) error {
+	return mockMultiOp{m}.RunConcurrentlyWithContext(ctx, maxParallel)
+}
+
 func (m mockOp) GenerateStatement() Statement {
 	return noOpStatement{}
 }
@@ -84,6 +147,23 @@ func (m mockOp) Preflight() error {
 	return m.preflightErr
 }
 
+// onConditionFailed returns a copy of the op which fails with the error returned by conditionFailed in place of a
+// ConditionFailedError
+func (m mockOp) onConditionFailed(conditionFailed func(ConditionFailedError) error) Op {
+	funcs := make([]func(mockOp) error, len(m.funcs))
+	for i, f := range m.funcs {
+		f := f
+		funcs[i] = func(m mockOp) error {
+			err := f(m)
+			if cerr, ok := err.(ConditionFailedError); ok {
+				return conditionFailed(cerr)
+			}
+			return err
+		}
+	}
+	return mockOp{options: m.options, funcs: funcs, preflightErr: m.preflightErr}
+}
+
 type mockMultiOp []Op
 
 func (mo mockMultiOp) Run() error {
@@ -107,17 +187,40 @@ func (mo mockMultiOp) RunWithContext(ctx context.Context) error {
 }
 
 func (mo mockMultiOp) RunAtomically() error {
+	// Lazy ops are refused just as they are by a real batch, even though the mock runs the ops in turn
+	for _, op := range mo {
+		if _, ok := op.(lazyOp); ok {
+			return errLazyBatch
+		}
+	}
+	if err := checkCounterBatch(mo); err != nil {
+		return err
+	}
 	return mo.Run()
 }
 
 func (mo mockMultiOp) RunLoggedBatchWithContext(ctx context.Context) error {
-	return mo.WithOptions(Options{Context: ctx}).Run()
+	return mo.WithOptions(Options{Context: ctx}).RunAtomically()
 }
 
 func (mo mockMultiOp) RunAtomicallyWithContext(ctx context.Context) error {
 	return mo.RunLoggedBatchWithContext(ctx)
 }
 
+func (mo mockMultiOp) RunConcurrentlyWithContext(ctx context.Context, maxParallel int) error {
+	if err := mo.Preflight(); err != nil {
+		return err
+	}
+	ops := mo.WithOptions(Options{Context: ctx}).(mockMultiOp)
+	return runConcurrently(ctx, len(ops), maxParallel, func(i int) error {
+		// Error injectors are consulted in order as each op is about to start, just as they are by Run
+		errorInjector := getErrorInjector(ops[i].Options().Context)
+		return errorInjector.shouldReturnErr(ops[i], i, len(ops))
+	}, func(ctx context.Context, i int) error {
+		return ops[i].WithOptions(Options{Context: ctx}).Run()
+	})
+}
+
 func (mo mockMultiOp) GenerateStatement() Statement {
 	return noOpStatement{}
 }
@@ -135,6 +238,12 @@ func (mo mockMultiOp) Add(inOps ...Op) Op {
 			ops = append(ops, op...)
 		case mockOp:
 			ops = append(ops, op)
+		case lazyOp:
+			// Lazy ops built on mock tables don't have a QueryExecutor
+			if op.QueryExecutor() != nil {
+				panic("can't Add non-mock ops to mockMultiOp")
+			}
+			ops = append(ops, op)
 		case multiOp:
 			if len(op) == 0 {
 				continue
@@ -190,15 +299,209 @@ func (ks *mockKeySpace) NewTable(name string, entity interface{}, fieldSource ma
 	}
 	mt.fields = fields
 
+	ks.registry.register(mt, keys)
 	return mt
 }
 
+// TableMetadata describes the named table from its MockTable definition. As Cassandra would, it lower cases the
+// names of the table's columns.
+func (ks *mockKeySpace) TableMetadata(name string) (TableMetadata, error) {
+	var mt *MockTable
+	for _, tbl := range ks.RegisteredTables() {
+		if strings.EqualFold(tbl.Name(), name) {
+			mt = tbl.(*MockTable)
+			break
+		}
+	}
+	if mt == nil {
+		return TableMetadata{}, TableNotFoundError{KeySpace: ks.Name(), Table: name}
+	}
+
+	directions := map[string]ColumnDirection{}
+	for _, o := range mt.options.ClusteringOrder {
+		directions[strings.ToLower(o.Column)] = o.Direction
+	}
+	columns := []ColumnMetadata{}
+	for _, field := range mt.fields {
+		typ, err := stringTypeOf(mt.fieldSource[field])
+		if err != nil {
+			return TableMetadata{}, err
+		}
+		column := ColumnMetadata{
+			Name:     strings.ToLower(field),
+			Type:     typ,
+			Kind:     RegularColumn,
+			Position: -1,
+		}
+		for i, pk := range mt.keys.PartitionKeys {
+			if pk == field {
+				column.Kind, column.Position = PartitionKeyColumn, i
+			}
+		}
+		for i, ck := range mt.keys.ClusteringColumns {
+			if ck == field {
+				column.Kind, column.Position = ClusteringColumn, i
+				column.ClusteringOrder = directions[column.Name]
+			}
+		}
+		columns = append(columns, column)
+	}
+
+	md := newTableMetadata(ks.Name(), mt.Name(), columns)
+	if mt.options.TableOptions != nil {
+		md.Options = *mt.options.TableOptions
+	}
+	return md, nil
+}
+
 func NewMockKeySpace() KeySpace {
+	return newMockKeySpace("")
+}
+
+func newMockKeySpace(name string) *mockKeySpace {
 	ks := &mockKeySpace{}
+	ks.name = name
 	ks.tableFactory = ks
+	ks.registry = newTableRegistry()
 	return ks
 }
 
+// Migrator returns a Migrator for the tables defined on the keyspace. Their live schema is their in-memory definition,
+// so there's never anything to migrate.
+func (ks *mockKeySpace) Migrator() *Migrator {
+	return &Migrator{
+		keySpace: ks.Name(),
+		tables:   func() ([]Table, error) { return ks.registry.tables(ks.Name()) },
+		source:   keySpaceSchema{ks: ks},
+	}
+}
+
+// CreateAllIfNotExist only checks the tables defined on the keyspace don't conflict, as in-memory tables don't need
+// creating.
+func (ks *mockKeySpace) CreateAllIfNotExist(ctx context.Context) error {
+	_, err := ks.registry.tables(ks.Name())
+	return err
+}
+
+// DropAll removes all rows from the tables defined on the keyspace.
+func (ks *mockKeySpace) DropAll() error {
+	for _, tbl := range ks.RegisteredTables() {
+		mt := tbl.(*MockTable)
+		mt.Lock()
+		mt.mtx.Lock()
+		for key := range mt.rows {
+			delete(mt.rows, key)
+		}
+		mt.mtx.Unlock()
+		mt.Unlock()
+	}
+	return nil
+}
+
+// SchemaCQL returns the CQL script which would create the tables defined on the keyspace in Cassandra.
+func (ks *mockKeySpace) SchemaCQL() (string, error) {
+	tables, err := ks.registry.tables(ks.Name())
+	if err != nil {
+		return "", err
+	}
+	stmts := make([]Statement, len(tables))
+	for i, tbl := range tables {
+		if stmts[i], err = tbl.(*MockTable).createIfNotExistStatement(); err != nil {
+			return "", err
+		}
+	}
+	return schemaCQL(stmts), nil
+}
+
+// MockConnection implements the Connection interface, handing out in-memory keyspaces. It records the options each
+// keyspace was created with so they can be asserted on.
+type MockConnection struct {
+	mtx       *sync.Mutex
+	options   map[string]KeySpaceOptions
+	keySpaces map[string]KeySpace
+}
+
+// NewMockConnection returns a MockConnection with no keyspaces.
+func NewMockConnection() *MockConnection {
+	return &MockConnection{
+		mtx:       &sync.Mutex{},
+		options:   map[string]KeySpaceOptions{},
+		keySpaces: map[string]KeySpace{},
+	}
+}
+
+func (c *MockConnection) CreateKeySpace(name string) error {
+	return c.CreateKeySpaceWithOptions(name, KeySpaceOptions{})
+}
+
+func (c *MockConnection) CreateKeySpaceWithOptions(name string, opts KeySpaceOptions) error {
+	if _, err := KeySpaceStatement(name, opts); err != nil {
+		return err
+	}
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if _, exists := c.options[name]; exists {
+		if opts.IfNotExists {
+			return nil
+		}
+		return fmt.Errorf("keyspace %s already exists", name)
+	}
+	c.options[name] = opts
+	return nil
+}
+
+func (c *MockConnection) AlterKeySpace(name string, opts KeySpaceOptions) error {
+	if _, err := AlterKeySpaceStatement(name, opts); err != nil {
+		return err
+	}
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	current, exists := c.options[name]
+	if !exists {
+		return fmt.Errorf("keyspace %s does not exist", name)
+	}
+	if opts.setsReplication() {
+		current.Strategy = opts.Strategy
+		current.ReplicationFactor = opts.ReplicationFactor
+		current.DataCenters = opts.DataCenters
+	}
+	if opts.DurableWrites != nil {
+		current.DurableWrites = opts.DurableWrites
+	}
+	c.options[name] = current
+	return nil
+}
+
+func (c *MockConnection) DropKeySpace(name string) error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	delete(c.options, name)
+	delete(c.keySpaces, name)
+	return nil
+}
+
+// KeySpace returns the in-memory keyspace with the given name. The same keyspace is returned for a name until it is
+// dropped.
+func (c *MockConnection) KeySpace(name string) KeySpace {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if ks, ok := c.keySpaces[name]; ok {
+		return ks
+	}
+	ks := newMockKeySpace(name)
+	c.keySpaces[name] = ks
+	return ks
+}
+
+// KeySpaceOptions returns the options the named keyspace was created with, including any changes made by
+// AlterKeySpace, and whether the keyspace exists.
+func (c *MockConnection) KeySpaceOptions(name string) (KeySpaceOptions, bool) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	opts, ok := c.options[name]
+	return opts, ok
+}
+
 // MockTable implements the Table interface and stores rows in-memory.
 type MockTable struct {
 	*sync.RWMutex
@@ -219,6 +522,62 @@ type rowKey string
 type superColumn struct {
 	Key     key
 	Columns map[string]interface{}
+	// Expires holds when the columns written with a TTL expire, with the expiry of the row's primary key under
+	// rowMarker. Columns without an expiry never expire.
+	Expires map[string]time.Time
+}
+
+// rowMarker is the key of superColumn.Expires holding when the row written by an insert expires, like Cassandra's row
+// marker keeps a row alive while it has no other live columns
+const rowMarker = ""
+
+// setExpiry records when column expires, or that it doesn't if expires is the zero time
+func (c *superColumn) setExpiry(column string, expires time.Time) {
+	if expires.IsZero() {
+		delete(c.Expires, column)
+		return
+	}
+	if c.Expires == nil {
+		c.Expires = map[string]time.Time{}
+	}
+	c.Expires[column] = expires
+}
+
+// liveColumns returns the columns of the row which haven't expired by now, or nil if the whole row has expired. A row
+// is live while its row marker or any of its other columns is, and its keys are returned with it.
+func (c *superColumn) liveColumns(keys Keys, now time.Time) map[string]interface{} {
+	if len(c.Expires) == 0 {
+		return c.Columns
+	}
+	live := func(column string) bool {
+		expires, ok := c.Expires[column]
+		return !ok || expires.After(now)
+	}
+
+	alive := live(rowMarker)
+	result := make(map[string]interface{}, len(c.Columns))
+	for column, value := range c.Columns {
+		if isKeyField(column, keys) {
+			result[column] = value
+		} else if live(column) {
+			result[column] = value
+			alive = true
+		}
+	}
+	if !alive {
+		return nil
+	}
+	return result
+}
+
+// isKeyField returns whether field is part of the primary key
+func isKeyField(field string, keys Keys) bool {
+	for _, key := range keys.PartitionKeys {
+		if strings.EqualFold(key, field) {
+			return true
+		}
+	}
+	return isClusteringKeyField(field, keys)
 }
 
 func (c *superColumn) Less(item btree.Item) bool {
@@ -370,6 +729,12 @@ func (t *MockTable) getOrCreateRow(rowKey key) *btree.BTree {
 }
 
 func (t *MockTable) getOrCreateColumnGroup(rowKey, superColumnKey key) map[string]interface{} {
+	scol, _ := t.getOrCreateSuperColumn(rowKey, superColumnKey)
+	return scol.Columns
+}
+
+// getOrCreateSuperColumn returns the row with the given keys, and whether it had to be created
+func (t *MockTable) getOrCreateSuperColumn(rowKey, superColumnKey key) (*superColumn, bool) {
 	row := t.getOrCreateRow(rowKey)
 	scol := superColumnKey.ToSuperColumn()
 
@@ -384,12 +749,46 @@ func (t *MockTable) getOrCreateColumnGroup(rowKey, superColumnKey key) map[strin
 	}
 
 	if row.Has(scol) {
-		return row.Get(scol).(*superColumn).Columns
+		return row.Get(scol).(*superColumn), false
 	}
 	row.ReplaceOrInsert(scol)
 	scol.Columns = map[string]interface{}{}
 
-	return scol.Columns
+	return scol, true
+}
+
+// expiry returns when columns written with opts expire, or the zero time if they don't. The time is told by the
+// options' Clock, so tests can expire rows by advancing a MockClock.
+func (t *MockTable) expiry(opts Options) time.Time {
+	if opts.TTL <= 0 {
+		return time.Time{}
+	}
+	return t.now(opts).Add(opts.TTL)
+}
+
+// now returns the time told by the Clock of opts
+func (t *MockTable) now(opts Options) time.Time {
+	if opts.Clock == nil {
+		return time.Now()
+	}
+	return opts.Clock.Now()
+}
+
+// insertRecords assigns the columns of an insert to the row with the given keys, which expire with its row marker
+// according to opts
+func (t *MockTable) insertRecords(columns map[string]interface{}, rowKey, superColumnKey key, opts Options) error {
+	scol, _ := t.getOrCreateSuperColumn(rowKey, superColumnKey)
+	if err := assignRecords(columns, scol.Columns); err != nil {
+		return err
+	}
+	expires := t.expiry(opts)
+	scol.setExpiry(rowMarker, expires)
+	for column := range columns {
+		if !isKeyField(column, t.keys) {
+			scol.setExpiry(column, expires)
+		}
+	}
+	return nil
 }
 
 func (t *MockTable) SetWithOptions(i interface{}, options Options) Op {
@@ -412,17 +811,65 @@ func (t *MockTable) SetWithOptions(i interface{}, options Options) Op {
 			return err
 		}
 
-		superColumn := t.getOrCreateColumnGroup(rowKey, superColumnKey)
+		return t.insertRecords(columns, rowKey, superColumnKey, options.Merge(m.options))
+	})
+}
+
+func (t *MockTable) Set(i interface{}) Op {
+	return t.SetWithOptions(i, t.options)
+}
+
+func (t *MockTable) SetIfNotExists(i interface{}) Op {
+	return newOp(func(m mockOp) error {
+		t.Lock()
+		defer t.Unlock()
+
+		columns, ok := toMap(i)
+		if !ok {
+			return errors.New("Can't create: value not understood")
+		}
 
-		if err := assignRecords(columns, superColumn); err != nil {
+		rowKey, err := t.partitionKeyFromColumnValues(columns, t.keys.PartitionKeys)
+		if err != nil {
 			return err
 		}
-		return nil
+
+		superColumnKey, err := t.clusteringKeyFromColumnValues(columns, t.keys.ClusteringColumns)
+		if err != nil {
+			return err
+		}
+
+		opts := t.options.Merge(m.options)
+		if existing := t.existingColumns(rowKey, superColumnKey, t.now(opts)); existing != nil {
+			stmt := InsertStatement{keyspace: t.ksName, table: t.Name(), fieldMap: columns, keys: t.keys, ifNotExists: true}
+			return ConditionFailedError{Statement: stmt, Current: lowerCaseColumns(existing)}
+		}
+		return t.insertRecords(columns, rowKey, superColumnKey, opts)
 	})
 }
 
-func (t *MockTable) Set(i interface{}) Op {
-	return t.SetWithOptions(i, t.options)
+// existingColumns returns the live columns of the row with the given keys at now, or nil if there isn't one
+func (t *MockTable) existingColumns(rowKey, superColumnKey key, now time.Time) map[string]interface{} {
+	t.mtx.RLock()
+	defer t.mtx.RUnlock()
+	row := t.rows[rowKey.RowKey()]
+	if row == nil {
+		return nil
+	}
+	item := row.Get(superColumnKey.ToSuperColumn())
+	if item == nil {
+		return nil
+	}
+	return item.(*superColumn).liveColumns(t.keys, now)
+}
+
+// lowerCaseColumns returns a copy of columns keyed by their lower case names, as Cassandra returns them
+func lowerCaseColumns(columns map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(columns))
+	for name, value := range columns {
+		result[strings.ToLower(name)] = value
+	}
+	return result
 }
 
 func (t *MockTable) Where(relations ...Relation) Filter {
@@ -452,8 +899,16 @@ func (t *MockTable) Recreate() error {
 	return nil
 }
 
+func (t *MockTable) AlterOptions() error {
+	return nil
+}
+
+func (t *MockTable) AlterOptionsStatement() (Statement, error) {
+	return noOpStatement{}, nil
+}
+
 func (t *MockTable) WithOptions(o Options) Table {
-	return &MockTable{
+	mt := &MockTable{
 		RWMutex:     t.RWMutex,
 		ksName:      t.ksName,
 		tableName:   t.tableName,
@@ -465,6 +920,45 @@ func (t *MockTable) WithOptions(o Options) Table {
 		options:     t.options.Merge(o),
 		mtx:         t.mtx,
 	}
+	return mt
+}
+
+// tableKeys returns the keys the table was defined with
+func (t *MockTable) tableKeys() Keys {
+	return t.keys
+}
+
+// tableFields returns the fields of the table's rows, and a value of the type of each
+func (t *MockTable) tableFields() ([]string, []interface{}) {
+	values := make([]interface{}, len(t.fields))
+	for i, field := range t.fields {
+		values[i] = t.fieldSource[field]
+	}
+	return t.fields, values
+}
+
+// createIfNotExistStatement returns the statement which would create the table in Cassandra
+func (t *MockTable) createIfNotExistStatement() (Statement, error) {
+	values := make([]interface{}, len(t.fields))
+	for i, field := range t.fields {
+		values[i] = t.fieldSource[field]
+	}
+	return createTableIfNotExist(t.ksName, t.Name(), t.keys.PartitionKeys, t.keys.ClusteringColumns, t.fields, values,
+		t.options.ClusteringOrder, t.keys.Compound, t.options.CompactStorage, t.options.Compressor, t.options.TableOptions)
+}
+
+// reversedBy returns whether rows are read in the reverse of the table's clustering order when ordered by order
+func (t *MockTable) reversedBy(order []ClusteringOrderColumn) bool {
+	if len(order) == 0 {
+		return false
+	}
+	direction := ASC
+	for _, o := range t.options.ClusteringOrder {
+		if o.Column == order[0].Column {
+			direction = o.Direction
+		}
+	}
+	return order[0].Direction != direction
 }
 
 // MockFilter implements the Filter interface and works with MockTable.
@@ -484,6 +978,14 @@ func (f *MockFilter) Relations() []Relation {
 func (f *MockFilter) rowMatch(row map[string]interface{}) bool {
 	for _, relation := range f.relations {
 		value := row[relation.Field()]
+		if relation.isTuple() {
+			fields := tupleFields(relation.Field())
+			values := make([]interface{}, len(fields))
+			for i, field := range fields {
+				values[i] = row[field]
+			}
+			value = values
+		}
 		if !relation.accept(value) {
 			return false
 		}
@@ -544,6 +1046,7 @@ func (f *MockFilter) UpdateWithOptions(m map[string]interface{}, options Options
 			return err
 		}
 
+		expires := f.table.expiry(f.table.options.Merge(options).Merge(mock.options))
 		for _, rowKey := range rowKeys {
 			superColumnKeys, err := f.fieldsFromRelations(f.table.keys.ClusteringColumns)
 			if err != nil {
@@ -551,17 +1054,18 @@ func (f *MockFilter) UpdateWithOptions(m map[string]interface{}, options Options
 			}
 
 			for _, superColumnKey := range superColumnKeys {
-				superColumn := f.table.getOrCreateColumnGroup(rowKey, superColumnKey)
+				superColumn, created := f.table.getOrCreateSuperColumn(rowKey, superColumnKey)
 
 				for _, key := range []key{rowKey, superColumnKey} {
 					for _, keyPart := range key {
-						superColumn[keyPart.Key] = keyPart.Value
+						superColumn.Columns[keyPart.Key] = keyPart.Value
 					}
 				}
 
-				if err := assignRecords(m, superColumn); err != nil {
+				if err := assignRecords(m, superColumn.Columns); err != nil {
 					return err
 				}
+				f.table.updateExpiry(superColumn, created, m, expires)
 			}
 		}
 
@@ -569,10 +1073,100 @@ func (f *MockFilter) UpdateWithOptions(m map[string]interface{}, options Options
 	})
 }
 
+// updateExpiry records when the columns of m written by an update to scol expire. An update doesn't write a row
+// marker, so a row it creates lives only as long as the columns it wrote.
+func (t *MockTable) updateExpiry(scol *superColumn, created bool, m map[string]interface{}, expires time.Time) {
+	if created {
+		scol.setExpiry(rowMarker, expires)
+	}
+	for column := range m {
+		if !isKeyField(column, t.keys) {
+			scol.setExpiry(column, expires)
+		}
+	}
+}
+
 func (f *MockFilter) Update(m map[string]interface{}) Op {
 	return f.UpdateWithOptions(m, Options{})
 }
 
+// conditionalRow returns the keys of the single row a conditional write on the filter applies to, or a
+// ConditionFailedError for stmt if the row doesn't exist or doesn't meet the conditions
+func (f *MockFilter) conditionalRow(conditions []Relation, stmt Statement, now time.Time) (key, key, error) {
+	rowKeys, err := f.fieldsFromRelations(f.table.keys.PartitionKeys)
+	if err != nil {
+		return nil, nil, err
+	}
+	superColumnKeys, err := f.fieldsFromRelations(f.table.keys.ClusteringColumns)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(rowKeys) != 1 || len(superColumnKeys) != 1 {
+		return nil, nil, errors.New("a conditional write must select a single row")
+	}
+
+	// As in Cassandra, the columns of a row which doesn't exist are null, so conditions on null columns hold for it.
+	// Only IF EXISTS, which has no conditions, needs the row to exist.
+	existing := f.table.existingColumns(rowKeys[0], superColumnKeys[0], now)
+	if existing == nil && len(conditions) == 0 {
+		return nil, nil, ConditionFailedError{Statement: stmt, Current: map[string]interface{}{}}
+	}
+	if existing == nil {
+		existing = map[string]interface{}{}
+	}
+	if !(&MockFilter{table: f.table, relations: conditions}).rowMatch(existing) {
+		return nil, nil, ConditionFailedError{Statement: stmt, Current: lowerCaseColumns(existing)}
+	}
+	return rowKeys[0], superColumnKeys[0], nil
+}
+
+func (f *MockFilter) UpdateIf(m map[string]interface{}, conditions ...Relation) Op {
+	return newOp(func(mock mockOp) error {
+		f.table.Lock()
+		defer f.table.Unlock()
+
+		stmt := UpdateStatement{keyspace: f.table.ksName, table: f.table.Name(), fieldMap: m, where: f.relations,
+			keys: f.table.keys, ifExists: len(conditions) == 0, conditions: conditions}
+		opts := f.table.options.Merge(mock.options)
+		rowKey, superColumnKey, err := f.conditionalRow(conditions, stmt, f.table.now(opts))
+		if err != nil {
+			return err
+		}
+
+		// A row which doesn't exist is created, as by an unconditional update, when its conditions hold
+		superColumn, created := f.table.getOrCreateSuperColumn(rowKey, superColumnKey)
+		for _, key := range []key{rowKey, superColumnKey} {
+			for _, keyPart := range key {
+				superColumn.Columns[keyPart.Key] = keyPart.Value
+			}
+		}
+		if err := assignRecords(m, superColumn.Columns); err != nil {
+			return err
+		}
+		f.table.updateExpiry(superColumn, created, m, f.table.expiry(opts))
+		return nil
+	})
+}
+
+func (f *MockFilter) DeleteIf(conditions ...Relation) Op {
+	return newOp(func(mock mockOp) error {
+		f.table.Lock()
+		defer f.table.Unlock()
+
+		stmt := DeleteStatement{keyspace: f.table.ksName, table: f.table.Name(), where: f.relations,
+			keys: f.table.keys, ifExists: len(conditions) == 0, conditions: conditions}
+		rowKey, superColumnKey, err := f.conditionalRow(conditions, stmt, f.table.now(f.table.options.Merge(mock.options)))
+		if err != nil {
+			return err
+		}
+
+		f.table.mtx.Lock()
+		defer f.table.mtx.Unlock()
+		f.table.rows[rowKey.RowKey()].Delete(superColumnKey.ToSuperColumn())
+		return nil
+	})
+}
+
 func (f *MockFilter) Delete() Op {
 	return newOp(func(m mockOp) error {
 		f.table.Lock()
@@ -588,17 +1182,22 @@ func (f *MockFilter) Delete() Op {
 		for _, rowKey := range rowKeys {
 			row := f.table.rows[rowKey.RowKey()]
 			if row == nil {
-				return nil
+				continue
 			}
 
+			// The btree can't be changed while it's being iterated over, so the rows are deleted afterwards
+			var matched []btree.Item
 			row.Ascend(func(item btree.Item) bool {
 				columns := item.(*superColumn).Columns
 				if f.rowMatch(columns) {
-					row.Delete(item)
+					matched = append(matched, item)
 				}
 
 				return true
 			})
+			for _, item := range matched {
+				row.Delete(item)
+			}
 		}
 
 		return nil
@@ -615,16 +1214,23 @@ func (q *MockFilter) Read(out interface{}) Op {
 			err    error
 		)
 
+		now := q.table.now(q.table.options.Merge(m.options))
 		switch {
 		case len(q.Relations()) == 0:
-			result = q.readAllRows()
+			result = q.readAllRows(now)
 		default:
-			result, err = q.readSomeRows()
+			result, err = q.readSomeRows(now)
 		}
 		if err != nil {
 			return err
 		}
 
+		if q.table.reversedBy(m.options.ClusteringOrder) {
+			for i, j := 0, len(result)-1; i < j; i, j = i+1, j-1 {
+				result[i], result[j] = result[j], result[i]
+			}
+		}
+
 		opt := q.table.options.Merge(m.options)
 		if opt.Limit > 0 && opt.Limit < len(result) {
 			result = result[:opt.Limit]
@@ -642,7 +1248,7 @@ func (q *MockFilter) Read(out interface{}) Op {
 	})
 }
 
-func (q *MockFilter) readSomeRows() ([]map[string]interface{}, error) {
+func (q *MockFilter) readSomeRows(now time.Time) ([]map[string]interface{}, error) {
 	q.table.mtx.RLock()
 	defer q.table.mtx.RUnlock()
 
@@ -659,8 +1265,8 @@ func (q *MockFilter) readSomeRows() ([]map[string]interface{}, error) {
 		}
 
 		row.Ascend(func(item btree.Item) bool {
-			columns := item.(*superColumn).Columns
-			if q.rowMatch(columns) {
+			columns := item.(*superColumn).liveColumns(q.table.keys, now)
+			if columns != nil && q.rowMatch(columns) {
 				result = append(result, columns)
 			}
 
@@ -671,14 +1277,14 @@ func (q *MockFilter) readSomeRows() ([]map[string]interface{}, error) {
 	return result, nil
 }
 
-func (q *MockFilter) readAllRows() []map[string]interface{} {
+func (q *MockFilter) readAllRows(now time.Time) []map[string]interface{} {
 	q.table.mtx.RLock()
 	defer q.table.mtx.RUnlock()
 	var result []map[string]interface{}
 	for _, row := range q.table.rows {
 		row.Ascend(func(item btree.Item) bool {
-			columns := item.(*superColumn).Columns
-			if q.rowMatch(columns) {
+			columns := item.(*superColumn).liveColumns(q.table.keys, now)
+			if columns != nil && q.rowMatch(columns) {
 				result = append(result, columns)
 			}
 
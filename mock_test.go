@@ -240,6 +240,30 @@ func (s *MockSuite) TestMapTableMultiRead() {
 	s.Equal("Jill", users[1].Name)
 }
 
+func (s *MockSuite) TestMapTableMultiReadFanOut() {
+	s.insertUsers()
+	tbl := s.mapTbl.WithOptions(Options{MultiRead: MultiReadFanOut, Parallelism: 2})
+
+	var users []user
+	s.NoError(tbl.MultiRead([]interface{}{2, 1}, &users).Run())
+	s.Len(users, 2)
+	s.Equal("Jill", users[0].Name)
+	s.Equal("Jane", users[1].Name)
+
+	// Missing keys are reported, while the rows which were found are still returned in request order
+	err := tbl.MultiRead([]interface{}{1, 42, 2, 43}, &users).RunWithContext(context.Background())
+	s.Equal(PartialResultError{Missing: []interface{}{42, 43}}, err)
+	s.Len(users, 2)
+	s.Equal("Jane", users[0].Name)
+	s.Equal("Jill", users[1].Name)
+
+	// Fan out reads can be combined with other mock ops
+	var u user
+	s.NoError(tbl.MultiRead([]interface{}{1}, &users).Add(tbl.Read(2, &u)).Run())
+	s.Len(users, 1)
+	s.Equal("Jill", u.Name)
+}
+
 func (s *MockSuite) TestMapTableUpdate() {
 	s.insertUsers()
 	s.NoError(s.mapTbl.Update(1, map[string]interface{}{
@@ -349,6 +373,46 @@ func (s *MockSuite) TestMultiMapTableList() {
 	s.Equal("Joe", users[0].Name)
 }
 
+func (s *MockSuite) TestMultiMapTableListRange() {
+	for i := 1; i <= 5; i++ {
+		s.NoError(s.mmapTbl.Set(user{Pk1: 1, Pk2: i, Name: fmt.Sprintf("user %d", i)}).Run())
+	}
+	listed := func(opts ListOptions) ([]int, ListOptions, bool) {
+		var users []user
+		s.NoError(s.mmapTbl.ListRange(1, opts, &users).Run())
+		ids := []int{}
+		for _, u := range users {
+			ids = append(ids, u.Pk2)
+		}
+		next, more := s.mmapTbl.NextPage(opts, &users)
+		return ids, next, more
+	}
+
+	// Paging forwards doesn't re-read the last row of the previous page
+	ids, next, more := listed(ListOptions{Limit: 2})
+	s.Equal([]int{1, 2}, ids)
+	s.True(more)
+	ids, next, more = listed(next)
+	s.Equal([]int{3, 4}, ids)
+	s.True(more)
+	ids, _, more = listed(next)
+	s.Equal([]int{5}, ids)
+	s.False(more)
+
+	// Paging backwards
+	ids, next, more = listed(ListOptions{Order: DESC, Limit: 2})
+	s.Equal([]int{5, 4}, ids)
+	s.True(more)
+	ids, _, _ = listed(next)
+	s.Equal([]int{3, 2}, ids)
+
+	// Bounds apply in the direction of the listing
+	ids, _, _ = listed(ListOptions{Start: ExclusiveBound(1), End: InclusiveBound(3)})
+	s.Equal([]int{2, 3}, ids)
+	ids, _, _ = listed(ListOptions{Start: InclusiveBound(4), End: ExclusiveBound(2), Order: DESC})
+	s.Equal([]int{4, 3}, ids)
+}
+
 func (s *MockSuite) TestMultiMapTableUpdate() {
 	s.insertUsers()
 
@@ -400,6 +464,43 @@ func (s *MockSuite) TestTimeSeriesTableList() {
 	s.Equal(points[2], ps[1])
 }
 
+func (s *MockSuite) TestTimeSeriesTableListSince() {
+	// Points at the same time are ordered by their IDs, and the last is in the next bucket
+	at := s.parseTime("2015-04-01 15:41:00")
+	times := []time.Time{at.Add(-time.Second), at, at, at, at.Add(90 * time.Second)}
+	for i, t := range times {
+		p := point{Time: t, Id: i, User: "John", X: 1.1, Y: 1.2}
+		s.NoError(s.tsTbl.Set(p).Run())
+		s.NoError(s.mtsTbl.Set(p).Run())
+		s.NoError(s.mkTsTbl.Set(p).Run())
+	}
+	ids := func(ps []point) []int {
+		ids := []int{}
+		for _, p := range ps {
+			ids = append(ids, p.Id)
+		}
+		return ids
+	}
+
+	var ps []point
+	s.NoError(s.tsTbl.ListSince(at, 2, 5*time.Minute, &ps).Run())
+	s.Equal([]int{3, 4}, ids(ps))
+	s.NoError(s.tsTbl.ListBefore(at, 2, 5*time.Minute, &ps).Run())
+	s.Equal([]int{0, 1}, ids(ps))
+	s.Equal(errNoWindow, s.tsTbl.ListBefore(at, 2, 0, &ps).Run())
+
+	s.NoError(s.mtsTbl.ListSince("John", at, 1, time.Minute, &ps).Run())
+	s.Equal([]int{2, 3}, ids(ps))
+	s.NoError(s.mtsTbl.ListBefore("John", at.Add(90*time.Second), 4, 2*time.Minute, &ps).Run())
+	s.Equal([]int{0, 1, 2, 3}, ids(ps))
+
+	xy := map[string]interface{}{"X": 1.1, "Y": 1.2}
+	s.NoError(s.mkTsTbl.ListSince(xy, at.Add(-time.Second), map[string]interface{}{"Id": 0}, 5*time.Minute, &ps).Run())
+	s.Equal([]int{1, 2, 3, 4}, ids(ps))
+	s.NoError(s.mkTsTbl.ListBefore(xy, at, map[string]interface{}{"Id": 3}, time.Minute, &ps).Run())
+	s.Equal([]int{0, 1, 2}, ids(ps))
+}
+
 func (s *MockSuite) TestWithOptions() {
 	points := s.insertPoints()
 	var ps []point
@@ -877,6 +978,74 @@ func TestErrorInjectors(t *testing.T) {
 	})
 }
 
+func TestErrorInjectorsConcurrently(t *testing.T) {
+	type Thing struct {
+		ID    string
+		Field string
+	}
+	things := []Thing{
+		{ID: "1", Field: "one"},
+		{ID: "2", Field: "two"},
+		{ID: "3", Field: "three"},
+		{ID: "4", Field: "four"},
+	}
+	errToInject := fmt.Errorf("injected error")
+
+	t.Run("FailOnNthOperation", func(t *testing.T) {
+		ks := NewMockKeySpace()
+		table := ks.MapTable("table_name", "ID", Thing{})
+
+		op := Noop()
+		for _, thing := range things {
+			op = op.Add(table.Set(thing))
+		}
+		ctx := ErrorInjectorContext(context.Background(), FailOnNthOperation(2, errToInject))
+		err := op.RunConcurrentlyWithContext(ctx, 1)
+		assert.Equal(t, MultiError{2: errToInject}, err)
+
+		// The ops started before the failure ran, those after it never started
+		for i, thing := range things {
+			readThing := Thing{}
+			err := table.Read(thing.ID, &readThing).RunWithContext(context.Background())
+			if i < 2 {
+				assert.NoError(t, err)
+			} else {
+				assert.IsType(t, RowNotFoundError{}, err)
+			}
+		}
+	})
+
+	t.Run("FailOnEachOperation", func(t *testing.T) {
+		ks := NewMockKeySpace()
+		table := ks.MapTable("table_name", "ID", Thing{})
+
+		op := Noop()
+		for _, thing := range things {
+			op = op.Add(table.Set(thing))
+		}
+		errorInjector := FailOnEachOperation(errToInject)
+		ctx := ErrorInjectorContext(context.Background(), errorInjector)
+
+		for i := 0; i < len(things); i++ {
+			err := op.RunConcurrentlyWithContext(ctx, 2)
+			assert.Equal(t, MultiError{i: errToInject}, err)
+			assert.True(t, errorInjector.ShouldContinue())
+			assert.Equal(t, i, errorInjector.LastErrorInjectedAtIdx())
+		}
+
+		err := op.RunConcurrentlyWithContext(ctx, 2)
+		assert.NoError(t, err)
+		assert.False(t, errorInjector.ShouldContinue())
+
+		for _, thing := range things {
+			readThing := Thing{}
+			err := table.Read(thing.ID, &readThing).RunWithContext(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, thing, readThing)
+		}
+	})
+}
+
 func TestMockClusteringOrder(t *testing.T) {
 	type Thing struct {
 		ID      string
@@ -947,3 +1116,371 @@ func TestMockClusteringOrder(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, things, readThings)
 }
+
+func TestMockConnection(t *testing.T) {
+	conn := NewMockConnection()
+	var _ KeySpaceAdmin = conn
+	opts := KeySpaceOptions{DataCenters: map[string]int{"dc1": 3, "dc2": 3}}
+	require.NoError(t, conn.CreateKeySpaceWithOptions("ks", opts))
+
+	recorded, ok := conn.KeySpaceOptions("ks")
+	require.True(t, ok)
+	require.Equal(t, opts, recorded)
+
+	// Creating a keyspace which exists fails unless IfNotExists is set
+	require.Error(t, conn.CreateKeySpace("ks"))
+	require.NoError(t, conn.CreateKeySpaceWithOptions("ks", KeySpaceOptions{IfNotExists: true}))
+
+	durable := false
+	require.NoError(t, conn.AlterKeySpace("ks", KeySpaceOptions{DurableWrites: &durable}))
+	recorded, _ = conn.KeySpaceOptions("ks")
+	require.Equal(t, opts.DataCenters, recorded.DataCenters)
+	require.Equal(t, &durable, recorded.DurableWrites)
+
+	// Invalid options are rejected as they would be by Cassandra
+	require.Error(t, conn.CreateKeySpaceWithOptions("other", KeySpaceOptions{Strategy: NetworkTopologyStrategy}))
+	require.Error(t, conn.AlterKeySpace("other", KeySpaceOptions{ReplicationFactor: 3}))
+
+	require.Equal(t, "ks", conn.KeySpace("ks").Name())
+	require.NoError(t, conn.DropKeySpace("ks"))
+	_, ok = conn.KeySpaceOptions("ks")
+	require.False(t, ok)
+}
+
+func TestMockKeySpaceRegistry(t *testing.T) {
+	ks := NewMockKeySpace()
+	ks.MapTable("customers", "Id", Customer{})
+	ks.RegisterTable(ks.MultimapTable("customers", "Name", "Id", Customer{}).WithOptions(Options{TableName: "customers_map_Id"}).Table())
+
+	_, err := ks.SchemaCQL()
+	require.Error(t, err)
+	require.Error(t, ks.CreateAllIfNotExist(context.Background()))
+
+	ks = NewMockConnection().KeySpace("ks")
+	customers := ks.MapTable("customers", "Id", Customer{})
+	require.NoError(t, customers.Set(Customer{Id: "1", Name: "Joe"}).Run())
+	schema, err := ks.SchemaCQL()
+	require.NoError(t, err)
+	require.Contains(t, schema, "CREATE TABLE IF NOT EXISTS ks.customers_map_Id (")
+
+	// Dropping the tables removes their rows
+	require.NoError(t, ks.DropAll())
+	c := Customer{}
+	require.Error(t, customers.Read("1", &c).Run())
+}
+
+func TestMockMultimapMkListRange(t *testing.T) {
+	tbl := NewMockKeySpace().MultimapMultiKeyTable("users", []string{"Pk1"}, []string{"Ck1", "Ck2"}, user{})
+	for ck1 := 1; ck1 <= 2; ck1++ {
+		for ck2 := 1; ck2 <= 3; ck2++ {
+			require.NoError(t, tbl.Set(user{Pk1: 1, Ck1: ck1, Ck2: ck2}).Run())
+		}
+	}
+	field := map[string]interface{}{"Pk1": 1}
+	listed := func(opts ListOptions) ([][2]int, ListOptions, bool) {
+		var users []user
+		require.NoError(t, tbl.ListRange(field, opts, &users).Run())
+		keys := [][2]int{}
+		for _, u := range users {
+			keys = append(keys, [2]int{u.Ck1, u.Ck2})
+		}
+		next, more := tbl.NextPage(opts, &users)
+		return keys, next, more
+	}
+
+	keys, next, more := listed(ListOptions{Limit: 4})
+	require.Equal(t, [][2]int{{1, 1}, {1, 2}, {1, 3}, {2, 1}}, keys)
+	require.True(t, more)
+	require.Equal(t, ExclusiveBound(map[string]interface{}{"Ck1": 2, "Ck2": 1}), next.Start)
+	keys, _, more = listed(next)
+	require.Equal(t, [][2]int{{2, 2}, {2, 3}}, keys)
+	require.False(t, more)
+
+	keys, _, _ = listed(ListOptions{Order: DESC, Start: ExclusiveBound(map[string]interface{}{"Ck1": 2, "Ck2": 2}), Limit: 3})
+	require.Equal(t, [][2]int{{2, 1}, {1, 3}, {1, 2}}, keys)
+
+	// Bounds may be a prefix of the clustering key
+	keys, _, _ = listed(ListOptions{Start: ExclusiveBound(map[string]interface{}{"Ck1": 1})})
+	require.Equal(t, [][2]int{{2, 1}, {2, 2}, {2, 3}}, keys)
+	keys, _, _ = listed(ListOptions{
+		Start: ExclusiveBound(map[string]interface{}{"Ck1": 1, "Ck2": 2}),
+		End:   ExclusiveBound(map[string]interface{}{"Ck1": 2}),
+	})
+	require.Equal(t, [][2]int{{1, 3}}, keys)
+
+	var users []user
+	require.Error(t, tbl.ListRange(field, ListOptions{Start: InclusiveBound(map[string]interface{}{"Ck2": 1})}, &users).Run())
+	require.Error(t, tbl.ListRange(field, ListOptions{Start: InclusiveBound(1)}, &users).Run())
+}
+
+func TestMockTimeSeriesListWithLimit(t *testing.T) {
+	tbl := NewMockKeySpace().TimeSeriesTable("points", "Time", "Id", time.Minute, point{})
+	start := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	// Two points a minute, sharing a timestamp, in each of five buckets
+	for i := 0; i < 10; i++ {
+		require.NoError(t, tbl.Set(point{Time: start.Add(time.Duration(i/2) * time.Minute), Id: i}).Run())
+	}
+	end := start.Add(4 * time.Minute)
+
+	listed := func(opts SeriesListOptions) ([]int, SeriesListOptions, bool) {
+		var points []point
+		require.NoError(t, tbl.ListWithLimit(start, end, opts, &points).Run())
+		ids := []int{}
+		for _, p := range points {
+			ids = append(ids, p.Id)
+		}
+		next, more := tbl.NextPage(opts, &points)
+		return ids, next, more
+	}
+
+	ids, next, more := listed(SeriesListOptions{Limit: 3})
+	require.Equal(t, []int{0, 1, 2}, ids)
+	require.True(t, more)
+	require.Equal(t, &SeriesCursor{Time: start.Add(time.Minute), ID: 2}, next.After)
+	// The cursor resumes between the rows sharing its timestamp
+	ids, next, _ = listed(next)
+	require.Equal(t, []int{3, 4, 5}, ids)
+	ids, next, _ = listed(next)
+	require.Equal(t, []int{6, 7, 8}, ids)
+	ids, _, more = listed(next)
+	require.Equal(t, []int{9}, ids)
+	require.False(t, more)
+
+	// The latest rows are listed first in DESC order
+	ids, next, _ = listed(SeriesListOptions{Order: DESC, Limit: 3})
+	require.Equal(t, []int{9, 8, 7}, ids)
+	ids, _, _ = listed(next)
+	require.Equal(t, []int{6, 5, 4}, ids)
+
+	// Without a limit the whole range is listed
+	ids, _, more = listed(SeriesListOptions{})
+	require.Len(t, ids, 10)
+	require.False(t, more)
+}
+
+func TestMockFlakeSeriesListWithLimit(t *testing.T) {
+	tbl := NewMockKeySpace().FlakeSeriesTable("trips", "Id", time.Minute, Trip{})
+	ids := []string{
+		timeToFlake(t, "2006 Jan 2 15:03:59"),
+		timeToFlake(t, "2006 Jan 2 15:04:00"),
+		timeToFlake(t, "2006 Jan 2 15:04:01"),
+		timeToFlake(t, "2006 Jan 2 15:05:01"),
+	}
+	for _, id := range ids {
+		require.NoError(t, tbl.Set(Trip{Id: id}).Run())
+	}
+
+	opts := SeriesListOptions{Order: DESC, Limit: 2}
+	trips := []Trip{}
+	require.NoError(t, tbl.ListWithLimit(parse("2006 Jan 2 15:00:00"), parse("2006 Jan 2 15:06:00"), opts, &trips).Run())
+	require.Equal(t, []Trip{{Id: ids[3]}, {Id: ids[2]}}, trips)
+
+	opts, more := tbl.NextPage(opts, &trips)
+	require.True(t, more)
+	require.NoError(t, tbl.ListWithLimit(parse("2006 Jan 2 15:00:00"), parse("2006 Jan 2 15:06:00"), opts, &trips).Run())
+	require.Equal(t, []Trip{{Id: ids[1]}, {Id: ids[0]}}, trips)
+}
+
+func TestMockFlakeSeriesListBefore(t *testing.T) {
+	ks := NewMockKeySpace()
+	tbl := ks.FlakeSeriesTable("trips", "Id", time.Minute, Trip{})
+	multi := ks.MultiFlakeSeriesTable("trips", "Tag", "Id", time.Minute, TripB{})
+	ids := []string{
+		timeToFlake(t, "2006 Jan 2 15:03:59"),
+		timeToFlake(t, "2006 Jan 2 15:04:00"),
+		timeToFlake(t, "2006 Jan 2 15:04:01"),
+		timeToFlake(t, "2006 Jan 2 15:05:01"),
+	}
+	for _, id := range ids {
+		require.NoError(t, tbl.Set(Trip{Id: id}).Run())
+		require.NoError(t, multi.Set(TripB{Id: id, Tag: "A"}).Run())
+	}
+
+	trips := []Trip{}
+	require.NoError(t, tbl.ListBefore(ids[3], 61*time.Second, &trips).Run())
+	require.Equal(t, []Trip{{Id: ids[1]}, {Id: ids[2]}}, trips)
+	require.NoError(t, tbl.ListSince(ids[0], 2*time.Minute, &trips).Run())
+	require.Equal(t, []Trip{{Id: ids[1]}, {Id: ids[2]}, {Id: ids[3]}}, trips)
+	require.Equal(t, errNoWindow, tbl.ListBefore(ids[3], 0, &trips).Run())
+
+	tripsB := []TripB{}
+	require.NoError(t, multi.ListBefore("A", ids[2], time.Hour, &tripsB).Run())
+	require.Equal(t, []TripB{{Id: ids[0], Tag: "A"}, {Id: ids[1], Tag: "A"}}, tripsB)
+}
+
+func TestMockMultiKeyFlakeSeries(t *testing.T) {
+	type trip struct {
+		Id     string
+		Region string
+		Tag    string
+		Fare   int
+	}
+	tbl := NewMockKeySpace().MultiKeyFlakeSeriesTable("trips", []string{"Region", "Tag"}, "Id", time.Minute, trip{})
+	ids := []string{
+		timeToFlake(t, "2006 Jan 2 15:03:59"),
+		timeToFlake(t, "2006 Jan 2 15:04:00"),
+		timeToFlake(t, "2006 Jan 2 15:04:01"),
+		timeToFlake(t, "2006 Jan 2 15:05:01"),
+	}
+	london := map[string]interface{}{"Region": "London", "Tag": "A"}
+	paris := map[string]interface{}{"Region": "Paris", "Tag": "A"}
+	for i, id := range ids {
+		require.NoError(t, tbl.Set(trip{Id: id, Region: "London", Tag: "A", Fare: i}).Run())
+	}
+	require.NoError(t, tbl.Set(trip{Id: ids[1], Region: "Paris", Tag: "A"}).Run())
+
+	var tr trip
+	require.NoError(t, tbl.Read(london, ids[2], &tr).Run())
+	require.Equal(t, trip{Id: ids[2], Region: "London", Tag: "A", Fare: 2}, tr)
+
+	var trips []trip
+	require.NoError(t, tbl.List(london, parse("2006 Jan 2 15:03:58"), parse("2006 Jan 2 15:05:00"), &trips).Run())
+	require.Len(t, trips, 3)
+	require.Equal(t, ids[0], trips[0].Id)
+	require.Equal(t, ids[2], trips[2].Id)
+
+	require.NoError(t, tbl.List(paris, parse("2006 Jan 2 15:03:58"), parse("2006 Jan 2 15:06:00"), &trips).Run())
+	require.Len(t, trips, 1)
+	require.Equal(t, ids[1], trips[0].Id)
+
+	trips = nil
+	require.NoError(t, tbl.ListSince(london, ids[1], 2*time.Minute, &trips).Run())
+	require.Len(t, trips, 2)
+	require.Equal(t, ids[2], trips[0].Id)
+	require.Equal(t, ids[3], trips[1].Id)
+
+	opts := SeriesListOptions{Order: DESC, Limit: 3}
+	require.NoError(t, tbl.ListWithLimit(london, parse("2006 Jan 2 15:00:00"), parse("2006 Jan 2 15:06:00"), opts, &trips).Run())
+	require.Len(t, trips, 3)
+	require.Equal(t, ids[3], trips[0].Id)
+	opts, more := tbl.NextPage(opts, &trips)
+	require.True(t, more)
+	require.NoError(t, tbl.ListWithLimit(london, parse("2006 Jan 2 15:00:00"), parse("2006 Jan 2 15:06:00"), opts, &trips).Run())
+	require.Len(t, trips, 1)
+	require.Equal(t, ids[0], trips[0].Id)
+
+	trips = nil
+	require.NoError(t, tbl.ListBefore(london, ids[3], 2*time.Minute, &trips).Run())
+	require.Len(t, trips, 3)
+	require.Equal(t, ids[0], trips[0].Id)
+	require.Equal(t, ids[2], trips[2].Id)
+
+	require.NoError(t, tbl.Update(london, ids[2], map[string]interface{}{"Fare": 10}).Run())
+	require.NoError(t, tbl.Read(london, ids[2], &tr).Run())
+	require.Equal(t, 10, tr.Fare)
+
+	require.NoError(t, tbl.Delete(london, ids[2]).Run())
+	require.IsType(t, RowNotFoundError{}, tbl.Read(london, ids[2], &tr).Run())
+	require.NoError(t, tbl.Read(paris, ids[1], &tr).Run())
+
+	// A series key missing from v is an error rather than a query for a null partition key
+	noTag := map[string]interface{}{"Region": "London"}
+	errMissing := "missing value for series key field Tag"
+	require.EqualError(t, tbl.Set(map[string]interface{}{"Id": ids[0], "Region": "London"}).Run(), errMissing)
+	require.EqualError(t, tbl.Read(noTag, ids[0], &tr).Run(), errMissing)
+	require.EqualError(t, tbl.Update(noTag, ids[0], map[string]interface{}{"Fare": 1}).Run(), errMissing)
+	require.EqualError(t, tbl.Delete(noTag, ids[0]).Run(), errMissing)
+	require.EqualError(t, tbl.List(noTag, parse("2006 Jan 2 15:03:58"), parse("2006 Jan 2 15:05:00"), &trips).Run(), errMissing)
+	require.EqualError(t, tbl.ListSince(noTag, ids[0], time.Minute, &trips).Run(), errMissing)
+	require.EqualError(t, tbl.ListBefore(noTag, ids[3], time.Minute, &trips).Run(), errMissing)
+	require.EqualError(t, tbl.ListWithLimit(noTag, parse("2006 Jan 2 15:00:00"), parse("2006 Jan 2 15:06:00"), SeriesListOptions{Limit: 3}, &trips).Run(), errMissing)
+}
+
+func TestMockCounterTable(t *testing.T) {
+	tbl := NewMockKeySpace().CounterTable("views", []string{"Site"}, []string{"Page"}, []string{"Views", "Unique"}, pageViews{})
+	home := map[string]interface{}{"Site": "monzo.com", "Page": "/"}
+	about := map[string]interface{}{"Site": "monzo.com", "Page": "/about"}
+
+	require.NoError(t, tbl.Increment(home, "Views", 3).Run())
+	require.NoError(t, tbl.IncrementMany(home, map[string]int{"Views": -1, "Unique": 1}).
+		Add(tbl.Increment(about, "Views", 1)).
+		RunLoggedBatchWithContext(context.Background()))
+
+	var views pageViews
+	require.NoError(t, tbl.Read(home, &views).Run())
+	require.Equal(t, pageViews{Site: "monzo.com", Page: "/", Views: 2, Unique: 1}, views)
+
+	var all []pageViews
+	require.NoError(t, tbl.List(map[string]interface{}{"Site": "monzo.com"}, &all).Run())
+	require.Len(t, all, 2)
+	require.Equal(t, Counter(1), all[1].Views)
+
+	require.NoError(t, tbl.Reset(home).Run())
+	require.NoError(t, tbl.Increment(home, "Views", 1).Run())
+	require.NoError(t, tbl.Read(home, &views).Run())
+	require.Equal(t, Counter(1), views.Views)
+	require.Equal(t, Counter(0), views.Unique)
+}
+
+func TestMockConditionalWrites(t *testing.T) {
+	tbl := NewMockKeySpace().Table("customers", Customer{}, Keys{PartitionKeys: []string{"Id"}})
+
+	require.NoError(t, tbl.SetIfNotExists(Customer{Id: "1", Name: "Joe"}).Run())
+	err := tbl.SetIfNotExists(Customer{Id: "1", Name: "Jane"}).Run()
+	require.IsType(t, ConditionFailedError{}, err)
+	assert.Equal(t, map[string]interface{}{"id": "1", "name": "Joe"}, err.(ConditionFailedError).Current)
+
+	err = tbl.Where(Eq("Id", "1")).UpdateIf(map[string]interface{}{"Name": "Jane"}, Eq("Name", "Jim")).Run()
+	require.IsType(t, ConditionFailedError{}, err)
+	require.NoError(t, tbl.Where(Eq("Id", "1")).UpdateIf(map[string]interface{}{"Name": "Jane"}, Eq("Name", "Joe")).Run())
+	var c Customer
+	require.NoError(t, tbl.Where(Eq("Id", "1")).ReadOne(&c).Run())
+	assert.Equal(t, Customer{Id: "1", Name: "Jane"}, c)
+
+	// Rows which don't exist fail the IF EXISTS of writes without conditions
+	err = tbl.Where(Eq("Id", "2")).UpdateIf(map[string]interface{}{"Name": "Jim"}).Run()
+	require.IsType(t, ConditionFailedError{}, err)
+	assert.Empty(t, err.(ConditionFailedError).Current)
+	assert.IsType(t, ConditionFailedError{}, tbl.Where(Eq("Id", "2")).DeleteIf().Run())
+
+	assert.IsType(t, ConditionFailedError{}, tbl.Where(Eq("Id", "1")).DeleteIf(Eq("Name", "Joe")).Run())
+	require.NoError(t, tbl.Where(Eq("Id", "1")).DeleteIf(Eq("Name", "Jane")).Run())
+	assert.IsType(t, RowNotFoundError{}, tbl.Where(Eq("Id", "1")).ReadOne(&c).Run())
+	require.NoError(t, tbl.SetIfNotExists(Customer{Id: "1", Name: "Joe"}).Run())
+
+	assert.Error(t, tbl.Where(In("Id", "1", "2")).DeleteIf().Run())
+}
+
+func TestMockTTLExpiry(t *testing.T) {
+	clock := NewMockClock(time.Date(2020, 3, 4, 5, 6, 7, 0, time.UTC))
+	tbl := NewMockKeySpace().Table("customers", Customer{}, Keys{PartitionKeys: []string{"Id"}}).
+		WithOptions(Options{Clock: clock})
+	read := func(id string) (Customer, error) {
+		var c Customer
+		err := tbl.Where(Eq("Id", id)).ReadOne(&c).Run()
+		return c, err
+	}
+
+	require.NoError(t, tbl.Set(Customer{Id: "1", Name: "Joe"}).WithOptions(Options{TTL: time.Minute}).Run())
+	require.NoError(t, tbl.Set(Customer{Id: "2", Name: "Jane"}).Run())
+	clock.Advance(59 * time.Second)
+	c, err := read("1")
+	require.NoError(t, err)
+	assert.Equal(t, Customer{Id: "1", Name: "Joe"}, c)
+
+	clock.Advance(time.Second)
+	_, err = read("1")
+	assert.IsType(t, RowNotFoundError{}, err)
+	var all []Customer
+	require.NoError(t, tbl.Where().Read(&all).Run())
+	assert.Equal(t, []Customer{{Id: "2", Name: "Jane"}}, all)
+
+	// Expired rows don't exist for conditional writes
+	assert.IsType(t, ConditionFailedError{}, tbl.Where(Eq("Id", "1")).UpdateIf(map[string]interface{}{"Name": "Jim"}).Run())
+	require.NoError(t, tbl.SetIfNotExists(Customer{Id: "1", Name: "Jim"}).Run())
+
+	// A column updated with a TTL expires on its own, while the row is kept alive by its row marker
+	require.NoError(t, tbl.Where(Eq("Id", "2")).Update(map[string]interface{}{"Name": "Jo"}).WithOptions(Options{TTL: time.Minute}).Run())
+	clock.Advance(time.Minute)
+	c, err = read("2")
+	require.NoError(t, err)
+	assert.Equal(t, Customer{Id: "2"}, c)
+
+	// A row created by an update lives only as long as the columns it wrote
+	require.NoError(t, tbl.Where(Eq("Id", "3")).Update(map[string]interface{}{"Name": "Jay"}).WithOptions(Options{TTL: time.Minute}).Run())
+	_, err = read("3")
+	require.NoError(t, err)
+	clock.Advance(time.Minute)
+	_, err = read("3")
+	assert.IsType(t, RowNotFoundError{}, err)
+}
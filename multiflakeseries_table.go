@@ -9,7 +9,9 @@ type multiFlakeSeriesT struct {
 	t          Table
 	indexField string
 	idField    string
-	bucketSize time.Duration
+	buckets    BucketStrategy
+	decoder    IDTimeDecoder
+	options    Options
 }
 
 func (o *multiFlakeSeriesT) Table() Table                        { return o.t }
@@ -21,6 +23,10 @@ func (o *multiFlakeSeriesT) CreateStatement() (Statement, error) { return o.Tabl
 func (o *multiFlakeSeriesT) CreateIfNotExistStatement() (Statement, error) {
 	return o.Table().CreateIfNotExistStatement()
 }
+func (o *multiFlakeSeriesT) AlterOptions() error { return o.Table().AlterOptions() }
+func (o *multiFlakeSeriesT) AlterOptionsStatement() (Statement, error) {
+	return o.Table().AlterOptionsStatement()
+}
 
 func (o *multiFlakeSeriesT) Set(v interface{}) Op {
 	m, ok := toMap(v)
@@ -32,24 +38,24 @@ func (o *multiFlakeSeriesT) Set(v interface{}) Op {
 		panic(fmt.Sprintf("Id field (%s) is not present or is not a string", o.idField))
 	}
 
-	timestamp, err := flakeToTime(id)
+	timestamp, err := idTime(o.decoder, id)
 	if err != nil {
 		return errOp{err: err}
 	}
 
 	m[flakeTimestampFieldName] = timestamp
-	m[bucketFieldName] = bucket(timestamp, o.bucketSize)
+	m[bucketFieldName] = o.buckets.Bucket(timestamp)
 
 	return o.Table().
 		Set(m)
 }
 
 func (o *multiFlakeSeriesT) Update(v interface{}, id string, m map[string]interface{}) Op {
-	timestamp, err := flakeToTime(id)
+	timestamp, err := idTime(o.decoder, id)
 	if err != nil {
 		return errOp{err: err}
 	}
-	bucket := bucket(timestamp, o.bucketSize)
+	bucket := o.buckets.Bucket(timestamp)
 
 	return o.Table().
 		Where(Eq(o.indexField, v),
@@ -60,11 +66,11 @@ func (o *multiFlakeSeriesT) Update(v interface{}, id string, m map[string]interf
 }
 
 func (o *multiFlakeSeriesT) Delete(v interface{}, id string) Op {
-	timestamp, err := flakeToTime(id)
+	timestamp, err := idTime(o.decoder, id)
 	if err != nil {
 		return errOp{err: err}
 	}
-	bucket := bucket(timestamp, o.bucketSize)
+	bucket := o.buckets.Bucket(timestamp)
 
 	return o.Table().
 		Where(Eq(o.indexField, v),
@@ -75,11 +81,11 @@ func (o *multiFlakeSeriesT) Delete(v interface{}, id string) Op {
 }
 
 func (o *multiFlakeSeriesT) Read(v interface{}, id string, pointer interface{}) Op {
-	timestamp, err := flakeToTime(id)
+	timestamp, err := idTime(o.decoder, id)
 	if err != nil {
 		return errOp{err: err}
 	}
-	bucket := bucket(timestamp, o.bucketSize)
+	bucket := o.buckets.Bucket(timestamp)
 	return o.Table().
 		Where(Eq(o.indexField, v),
 			Eq(bucketFieldName, bucket),
@@ -93,48 +99,100 @@ func (o *multiFlakeSeriesT) List(v interface{}, startTime, endTime time.Time, po
 	for bucket := o.Buckets(v, startTime); bucket.Bucket().Before(endTime); bucket = bucket.Next() {
 		buckets = append(buckets, bucket.Bucket())
 	}
-	return o.Table().
+	read := o.Table().
 		Where(Eq(o.indexField, v),
 			In(bucketFieldName, buckets...),
 			GTE(flakeTimestampFieldName, startTime),
 			LT(flakeTimestampFieldName, endTime)).
 		Read(pointerToASlice)
+	return mergedSeriesRead(read, o.options, flakeTimestampFieldName, o.order(), pointerToASlice)
+}
+
+func (o *multiFlakeSeriesT) ListWithLimit(v interface{}, startTime, endTime time.Time, opts SeriesListOptions, pointerToASlice interface{}) Op {
+	order := o.order()
+	return seriesWalk{
+		buckets:      func(start time.Time) Buckets { return o.Buckets(v, start) },
+		timeField:    flakeTimestampFieldName,
+		idFields:     []string{o.idField},
+		start:        startTime,
+		end:          endTime,
+		endExclusive: true,
+		idOrder:      &order,
+	}.list(opts, pointerToASlice)
+}
+
+func (o *multiFlakeSeriesT) NextPage(opts SeriesListOptions, pointerToASlice interface{}) (SeriesListOptions, bool) {
+	return nextSeriesPage(opts, pointerToASlice, o.order())
 }
 
 func (o *multiFlakeSeriesT) Buckets(v interface{}, start time.Time) Buckets {
 	return bucketIter{
 		v:         start,
-		step:      o.bucketSize,
+		strategy:  o.buckets,
 		field:     bucketFieldName,
 		invariant: o.Table().Where(Eq(o.indexField, v))}
 }
 
 func (o *multiFlakeSeriesT) ListSince(v interface{}, id string, window time.Duration, pointerToASlice interface{}) Op {
-	startTime, err := flakeToTime(id)
+	startTime, err := idTime(o.decoder, id)
 	if err != nil {
 		return errOp{err: err}
 	}
 
-	var endTime time.Time
-	if window == 0 {
-		// no window set - so go up until 5 mins in the future
-		endTime = time.Now().Add(5 * time.Minute)
-	} else {
-		endTime = startTime.Add(window)
+	return seriesReadSince(o.Table(), o.options, window, func(now time.Time) Op {
+		var endTime time.Time
+		if window == 0 {
+			// no window set - so go up until 5 mins in the future
+			endTime = now.Add(5 * time.Minute)
+		} else {
+			endTime = startTime.Add(window)
+		}
+
+		buckets := []interface{}{}
+		for bucket := o.Buckets(v, startTime); bucket.Bucket().Before(endTime); bucket = bucket.Next() {
+			buckets = append(buckets, bucket.Bucket())
+		}
+
+		read := o.Table().
+			Where(Eq(o.indexField, v),
+				In(bucketFieldName, buckets...),
+				GTE(flakeTimestampFieldName, startTime),
+				LT(flakeTimestampFieldName, endTime)).
+			Read(pointerToASlice)
+		// The rows after id at the same time are selected by the order of the IDs' format, which Cassandra doesn't know
+		read = mergedSeriesRead(read, o.options, flakeTimestampFieldName, o.order(), pointerToASlice)
+		return seriesRowsPast(read, o.order(), SeriesCursor{Time: startTime, ID: id}, ASC, pointerToASlice)
+	})
+}
+
+func (o *multiFlakeSeriesT) ListBefore(v interface{}, id string, window time.Duration, pointerToASlice interface{}) Op {
+	endTime, err := idTime(o.decoder, id)
+	if err != nil {
+		return errOp{err: err}
 	}
+	if window <= 0 {
+		return errOp{err: errNoWindow}
+	}
+	startTime := endTime.Add(-window)
 
 	buckets := []interface{}{}
-	for bucket := o.Buckets(v, startTime); bucket.Bucket().Before(endTime); bucket = bucket.Next() {
+	for bucket := o.Buckets(v, startTime); !bucket.Bucket().After(endTime); bucket = bucket.Next() {
 		buckets = append(buckets, bucket.Bucket())
 	}
 
-	return o.Table().
+	read := o.Table().
 		Where(Eq(o.indexField, v),
-			In(bucketFieldName, buckets),
+			In(bucketFieldName, buckets...),
 			GTE(flakeTimestampFieldName, startTime),
-			LT(flakeTimestampFieldName, endTime),
-			GT(o.idField, id)).
+			LTE(flakeTimestampFieldName, endTime)).
 		Read(pointerToASlice)
+	// The rows before id at the same time are selected by the order of the IDs' format, which Cassandra doesn't know
+	read = mergedSeriesRead(read, o.options, flakeTimestampFieldName, o.order(), pointerToASlice)
+	return seriesRowsPast(read, o.order(), SeriesCursor{Time: endTime, ID: id}, DESC, pointerToASlice)
+}
+
+func (o *multiFlakeSeriesT) order() seriesOrder {
+	return flakeSeriesOrder(o.idField, o.decoder)
 }
 
 func (o *multiFlakeSeriesT) WithOptions(opt Options) MultiFlakeSeriesTable {
@@ -142,6 +200,8 @@ func (o *multiFlakeSeriesT) WithOptions(opt Options) MultiFlakeSeriesTable {
 		t:          o.Table().WithOptions(opt),
 		indexField: o.indexField,
 		idField:    o.idField,
-		bucketSize: o.bucketSize,
+		buckets:    o.buckets,
+		decoder:    o.decoder,
+		options:    o.options.Merge(opt),
 	}
 }
@@ -0,0 +1,253 @@
+package gocassa
+
+import (
+	"fmt"
+	"time"
+)
+
+type multiKeyFlakeSeriesT struct {
+	t           Table
+	indexFields []string
+	idField     string
+	buckets     BucketStrategy
+	decoder     IDTimeDecoder
+	options     Options
+}
+
+func (o *multiKeyFlakeSeriesT) Table() Table            { return o.t }
+func (o *multiKeyFlakeSeriesT) Create() error           { return o.Table().Create() }
+func (o *multiKeyFlakeSeriesT) CreateIfNotExist() error { return o.Table().CreateIfNotExist() }
+func (o *multiKeyFlakeSeriesT) Name() string            { return o.Table().Name() }
+func (o *multiKeyFlakeSeriesT) Recreate() error         { return o.Table().Recreate() }
+func (o *multiKeyFlakeSeriesT) CreateStatement() (Statement, error) {
+	return o.Table().CreateStatement()
+}
+func (o *multiKeyFlakeSeriesT) CreateIfNotExistStatement() (Statement, error) {
+	return o.Table().CreateIfNotExistStatement()
+}
+func (o *multiKeyFlakeSeriesT) AlterOptions() error { return o.Table().AlterOptions() }
+func (o *multiKeyFlakeSeriesT) AlterOptionsStatement() (Statement, error) {
+	return o.Table().AlterOptionsStatement()
+}
+
+func (o *multiKeyFlakeSeriesT) Set(v interface{}) Op {
+	m, ok := toMap(v)
+	if !ok {
+		panic("Can't set: not able to convert")
+	}
+	id, ok := m[o.idField].(string)
+	if !ok {
+		panic(fmt.Sprintf("Id field (%s) is not present or is not a string", o.idField))
+	}
+
+	timestamp, err := idTime(o.decoder, id)
+	if err != nil {
+		return errOp{err: err}
+	}
+
+	if _, err := o.partitionRelations(m); err != nil {
+		return errOp{err: err}
+	}
+
+	m[flakeTimestampFieldName] = timestamp
+	m[bucketFieldName] = o.buckets.Bucket(timestamp)
+
+	return o.Table().
+		Set(m)
+}
+
+func (o *multiKeyFlakeSeriesT) Update(v map[string]interface{}, id string, m map[string]interface{}) Op {
+	relations, err := o.rowRelations(v, id)
+	if err != nil {
+		return errOp{err: err}
+	}
+	return o.Table().
+		Where(relations...).
+		Update(m)
+}
+
+func (o *multiKeyFlakeSeriesT) Delete(v map[string]interface{}, id string) Op {
+	relations, err := o.rowRelations(v, id)
+	if err != nil {
+		return errOp{err: err}
+	}
+	return o.Table().
+		Where(relations...).
+		Delete()
+}
+
+func (o *multiKeyFlakeSeriesT) Read(v map[string]interface{}, id string, pointer interface{}) Op {
+	relations, err := o.rowRelations(v, id)
+	if err != nil {
+		return errOp{err: err}
+	}
+	return o.Table().
+		Where(relations...).
+		ReadOne(pointer)
+}
+
+func (o *multiKeyFlakeSeriesT) List(v map[string]interface{}, startTime, endTime time.Time, pointerToASlice interface{}) Op {
+	relations, err := o.partitionRelations(v)
+	if err != nil {
+		return errOp{err: err}
+	}
+
+	buckets := []interface{}{}
+	for bucket := o.Buckets(v, startTime); bucket.Bucket().Before(endTime); bucket = bucket.Next() {
+		buckets = append(buckets, bucket.Bucket())
+	}
+
+	relations = append(relations, In(bucketFieldName, buckets...))
+	relations = append(relations, GTE(flakeTimestampFieldName, startTime))
+	relations = append(relations, LT(flakeTimestampFieldName, endTime))
+
+	read := o.Table().
+		Where(relations...).
+		Read(pointerToASlice)
+	return mergedSeriesRead(read, o.options, flakeTimestampFieldName, o.order(), pointerToASlice)
+}
+
+func (o *multiKeyFlakeSeriesT) ListWithLimit(v map[string]interface{}, startTime, endTime time.Time, opts SeriesListOptions, pointerToASlice interface{}) Op {
+	if _, err := o.partitionRelations(v); err != nil {
+		return errOp{err: err}
+	}
+	order := o.order()
+	return seriesWalk{
+		buckets:      func(start time.Time) Buckets { return o.Buckets(v, start) },
+		timeField:    flakeTimestampFieldName,
+		idFields:     []string{o.idField},
+		start:        startTime,
+		end:          endTime,
+		endExclusive: true,
+		idOrder:      &order,
+	}.list(opts, pointerToASlice)
+}
+
+func (o *multiKeyFlakeSeriesT) NextPage(opts SeriesListOptions, pointerToASlice interface{}) (SeriesListOptions, bool) {
+	return nextSeriesPage(opts, pointerToASlice, o.order())
+}
+
+func (o *multiKeyFlakeSeriesT) Buckets(v map[string]interface{}, start time.Time) Buckets {
+	// Buckets can't return an error, so the ops listing the series check v has every index field before using it
+	relations, _ := o.partitionRelations(v)
+	return bucketIter{
+		v:         start,
+		strategy:  o.buckets,
+		field:     bucketFieldName,
+		invariant: o.Table().Where(relations...)}
+}
+
+func (o *multiKeyFlakeSeriesT) ListSince(v map[string]interface{}, id string, window time.Duration, pointerToASlice interface{}) Op {
+	relations, err := o.partitionRelations(v)
+	if err != nil {
+		return errOp{err: err}
+	}
+	startTime, err := idTime(o.decoder, id)
+	if err != nil {
+		return errOp{err: err}
+	}
+
+	return seriesReadSince(o.Table(), o.options, window, func(now time.Time) Op {
+		var endTime time.Time
+		if window == 0 {
+			// no window set - so go up until 5 mins in the future
+			endTime = now.Add(5 * time.Minute)
+		} else {
+			endTime = startTime.Add(window)
+		}
+
+		buckets := []interface{}{}
+		for bucket := o.Buckets(v, startTime); bucket.Bucket().Before(endTime); bucket = bucket.Next() {
+			buckets = append(buckets, bucket.Bucket())
+		}
+
+		where := append([]Relation{}, relations...)
+		where = append(where, In(bucketFieldName, buckets...))
+		where = append(where, GTE(flakeTimestampFieldName, startTime))
+		where = append(where, LT(flakeTimestampFieldName, endTime))
+
+		read := o.Table().
+			Where(where...).
+			Read(pointerToASlice)
+		// The rows after id at the same time are selected by the order of the IDs' format, which Cassandra doesn't know
+		read = mergedSeriesRead(read, o.options, flakeTimestampFieldName, o.order(), pointerToASlice)
+		return seriesRowsPast(read, o.order(), SeriesCursor{Time: startTime, ID: id}, ASC, pointerToASlice)
+	})
+}
+
+func (o *multiKeyFlakeSeriesT) ListBefore(v map[string]interface{}, id string, window time.Duration, pointerToASlice interface{}) Op {
+	relations, err := o.partitionRelations(v)
+	if err != nil {
+		return errOp{err: err}
+	}
+	endTime, err := idTime(o.decoder, id)
+	if err != nil {
+		return errOp{err: err}
+	}
+	if window <= 0 {
+		return errOp{err: errNoWindow}
+	}
+	startTime := endTime.Add(-window)
+
+	buckets := []interface{}{}
+	for bucket := o.Buckets(v, startTime); !bucket.Bucket().After(endTime); bucket = bucket.Next() {
+		buckets = append(buckets, bucket.Bucket())
+	}
+
+	relations = append(relations, In(bucketFieldName, buckets...))
+	relations = append(relations, GTE(flakeTimestampFieldName, startTime))
+	relations = append(relations, LTE(flakeTimestampFieldName, endTime))
+
+	read := o.Table().
+		Where(relations...).
+		Read(pointerToASlice)
+	// The rows before id at the same time are selected by the order of the IDs' format, which Cassandra doesn't know
+	read = mergedSeriesRead(read, o.options, flakeTimestampFieldName, o.order(), pointerToASlice)
+	return seriesRowsPast(read, o.order(), SeriesCursor{Time: endTime, ID: id}, DESC, pointerToASlice)
+}
+
+func (o *multiKeyFlakeSeriesT) order() seriesOrder {
+	return flakeSeriesOrder(o.idField, o.decoder)
+}
+
+// partitionRelations returns the relations selecting the series v, returning an error if v has no value for one of
+// the index fields
+func (o *multiKeyFlakeSeriesT) partitionRelations(v map[string]interface{}) ([]Relation, error) {
+	relations := make([]Relation, 0, len(o.indexFields)+1)
+	for _, field := range o.indexFields {
+		value, ok := v[field]
+		if !ok || value == nil {
+			return nil, fmt.Errorf("missing value for series key field %s", field)
+		}
+		relations = append(relations, Eq(field, value))
+	}
+	return relations, nil
+}
+
+// rowRelations returns the relations selecting the row of the series v with the given ID
+func (o *multiKeyFlakeSeriesT) rowRelations(v map[string]interface{}, id string) ([]Relation, error) {
+	timestamp, err := idTime(o.decoder, id)
+	if err != nil {
+		return nil, err
+	}
+
+	relations, err := o.partitionRelations(v)
+	if err != nil {
+		return nil, err
+	}
+	relations = append(relations, Eq(bucketFieldName, o.buckets.Bucket(timestamp)))
+	relations = append(relations, Eq(flakeTimestampFieldName, timestamp))
+	relations = append(relations, Eq(o.idField, id))
+	return relations, nil
+}
+
+func (o *multiKeyFlakeSeriesT) WithOptions(opt Options) MultiKeyFlakeSeriesTable {
+	return &multiKeyFlakeSeriesT{
+		t:           o.Table().WithOptions(opt),
+		indexFields: o.indexFields,
+		idField:     o.idField,
+		buckets:     o.buckets,
+		decoder:     o.decoder,
+		options:     o.options.Merge(opt),
+	}
+}
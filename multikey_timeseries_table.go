@@ -9,7 +9,8 @@ type multiKeyTimeSeriesT struct {
 	indexFields []string
 	timeField   string
 	idFields    []string
-	bucketSize  time.Duration
+	buckets     BucketStrategy
+	options     Options
 }
 
 func (o *multiKeyTimeSeriesT) Table() Table                        { return o.t }
@@ -21,6 +22,10 @@ func (o *multiKeyTimeSeriesT) CreateStatement() (Statement, error) { return o.Ta
 func (o *multiKeyTimeSeriesT) CreateIfNotExistStatement() (Statement, error) {
 	return o.Table().CreateIfNotExistStatement()
 }
+func (o *multiKeyTimeSeriesT) AlterOptions() error { return o.Table().AlterOptions() }
+func (o *multiKeyTimeSeriesT) AlterOptionsStatement() (Statement, error) {
+	return o.Table().AlterOptionsStatement()
+}
 
 func (o *multiKeyTimeSeriesT) Set(v interface{}) Op {
 	m, ok := toMap(v)
@@ -30,14 +35,14 @@ func (o *multiKeyTimeSeriesT) Set(v interface{}) Op {
 	if tim, ok := m[o.timeField].(time.Time); !ok {
 		panic("timeField is not actually a time.Time")
 	} else {
-		m[bucketFieldName] = bucket(tim, o.bucketSize)
+		m[bucketFieldName] = o.buckets.Bucket(tim)
 	}
 	return o.Table().
 		Set(m)
 }
 
 func (o *multiKeyTimeSeriesT) Update(v map[string]interface{}, timeStamp time.Time, id map[string]interface{}, m map[string]interface{}) Op {
-	bucket := bucket(timeStamp, o.bucketSize)
+	bucket := o.buckets.Bucket(timeStamp)
 	relations := make([]Relation, 0)
 	relations = append(relations, o.ListOfEqualRelations(v, id)...)
 	relations = append(relations, Eq(bucketFieldName, bucket))
@@ -49,7 +54,7 @@ func (o *multiKeyTimeSeriesT) Update(v map[string]interface{}, timeStamp time.Ti
 }
 
 func (o *multiKeyTimeSeriesT) Delete(v map[string]interface{}, timeStamp time.Time, id map[string]interface{}) Op {
-	bucket := bucket(timeStamp, o.bucketSize)
+	bucket := o.buckets.Bucket(timeStamp)
 	relations := make([]Relation, 0)
 	relations = append(relations, o.ListOfEqualRelations(v, id)...)
 	relations = append(relations, Eq(bucketFieldName, bucket))
@@ -61,7 +66,7 @@ func (o *multiKeyTimeSeriesT) Delete(v map[string]interface{}, timeStamp time.Ti
 }
 
 func (o *multiKeyTimeSeriesT) Read(v map[string]interface{}, timeStamp time.Time, id map[string]interface{}, pointer interface{}) Op {
-	bucket := bucket(timeStamp, o.bucketSize)
+	bucket := o.buckets.Bucket(timeStamp)
 	relations := make([]Relation, 0)
 	relations = append(relations, o.ListOfEqualRelations(v, id)...)
 	relations = append(relations, Eq(bucketFieldName, bucket))
@@ -85,15 +90,54 @@ func (o *multiKeyTimeSeriesT) List(v map[string]interface{}, startTime time.Time
 	relations = append(relations, GTE(o.timeField, startTime))
 	relations = append(relations, LTE(o.timeField, endTime))
 
-	return o.Table().
+	read := o.Table().
 		Where(relations...).
 		Read(pointerToASlice)
+	return mergedSeriesRead(read, o.options, o.timeField, timeSeriesOrder(o.timeField, o.idFields), pointerToASlice)
+}
+
+func (o *multiKeyTimeSeriesT) ListWithLimit(v map[string]interface{}, startTime, endTime time.Time, opts SeriesListOptions, pointerToASlice interface{}) Op {
+	return seriesWalk{
+		buckets:   func(start time.Time) Buckets { return o.Buckets(v, start) },
+		timeField: o.timeField,
+		idFields:  o.idFields,
+		start:     startTime,
+		end:       endTime,
+	}.list(opts, pointerToASlice)
+}
+
+func (o *multiKeyTimeSeriesT) NextPage(opts SeriesListOptions, pointerToASlice interface{}) (SeriesListOptions, bool) {
+	return nextSeriesPage(opts, pointerToASlice, timeSeriesOrder(o.timeField, o.idFields))
+}
+
+func (o *multiKeyTimeSeriesT) ListSince(v map[string]interface{}, timeStamp time.Time, id map[string]interface{}, window time.Duration, pointerToASlice interface{}) Op {
+	return seriesReadSince(o.Table(), o.options, window, func(now time.Time) Op {
+		relations, err := seriesWindow(func(start time.Time) Buckets { return o.Buckets(v, start) }, o.timeField, o.idFields, SeriesCursor{Time: timeStamp, ID: id}, window, now, ASC)
+		if err != nil {
+			return errOp{err: err}
+		}
+		read := o.Table().
+			Where(append(o.ListOfEqualRelations(v, nil), relations...)...).
+			Read(pointerToASlice)
+		return mergedSeriesRead(read, o.options, o.timeField, timeSeriesOrder(o.timeField, o.idFields), pointerToASlice)
+	})
+}
+
+func (o *multiKeyTimeSeriesT) ListBefore(v map[string]interface{}, timeStamp time.Time, id map[string]interface{}, window time.Duration, pointerToASlice interface{}) Op {
+	relations, err := seriesWindow(func(start time.Time) Buckets { return o.Buckets(v, start) }, o.timeField, o.idFields, SeriesCursor{Time: timeStamp, ID: id}, window, time.Time{}, DESC)
+	if err != nil {
+		return errOp{err: err}
+	}
+	read := o.Table().
+		Where(append(o.ListOfEqualRelations(v, nil), relations...)...).
+		Read(pointerToASlice)
+	return mergedSeriesRead(read, o.options, o.timeField, timeSeriesOrder(o.timeField, o.idFields), pointerToASlice)
 }
 
 func (o *multiKeyTimeSeriesT) Buckets(v map[string]interface{}, start time.Time) Buckets {
 	return bucketIter{
 		v:         start,
-		step:      o.bucketSize,
+		strategy:  o.buckets,
 		field:     bucketFieldName,
 		invariant: o.Table().Where(o.ListOfEqualRelations(v, nil)...)}
 }
@@ -104,7 +148,8 @@ func (o *multiKeyTimeSeriesT) WithOptions(opt Options) MultiKeyTimeSeriesTable {
 		indexFields: o.indexFields,
 		timeField:   o.timeField,
 		idFields:    o.idFields,
-		bucketSize:  o.bucketSize,
+		buckets:     o.buckets,
+		options:     o.options.Merge(opt),
 	}
 }
 
@@ -15,6 +15,10 @@ func (mm *multimapMkT) CreateStatement() (Statement, error) { return mm.Table().
 func (mm *multimapMkT) CreateIfNotExistStatement() (Statement, error) {
 	return mm.Table().CreateIfNotExistStatement()
 }
+func (mm *multimapMkT) AlterOptions() error { return mm.Table().AlterOptions() }
+func (mm *multimapMkT) AlterOptionsStatement() (Statement, error) {
+	return mm.Table().AlterOptionsStatement()
+}
 
 func (mm *multimapMkT) Update(field, id map[string]interface{}, m map[string]interface{}) Op {
 	return mm.Table().
@@ -69,6 +73,26 @@ func (mm *multimapMkT) List(field, startId map[string]interface{}, limit int, po
 		Read(pointerToASlice)
 }
 
+func (mm *multimapMkT) ListRange(field map[string]interface{}, opts ListOptions, pointerToASlice interface{}) Op {
+	bounds, err := opts.relations(mm.idField)
+	if err != nil {
+		return errOp{err: err}
+	}
+	return mm.Table().
+		WithOptions(Options{
+			Limit: opts.Limit,
+		}).
+		Where(append(mm.ListOfEqualRelations(field, nil), bounds...)...).
+		Read(pointerToASlice).
+		WithOptions(Options{
+			ClusteringOrder: opts.clusteringOrder(mm.idField),
+		})
+}
+
+func (mm *multimapMkT) NextPage(opts ListOptions, pointerToASlice interface{}) (ListOptions, bool) {
+	return opts.next(mm.idField, pointerToASlice)
+}
+
 func (mm *multimapMkT) WithOptions(o Options) MultimapMkTable {
 	return &multimapMkT{
 		t:               mm.Table().WithOptions(o),
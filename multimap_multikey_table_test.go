@@ -224,3 +224,30 @@ func TestMultimapMultiKeyTableListOrder(t *testing.T) {
 		t.Fatalf("Expected to find charing cross, got %v", list[2].Address)
 	}
 }
+
+func TestMultimapMultiKeyTableListRangeStatement(t *testing.T) {
+	var stmt Statement
+	qe := funcQE{fn: func(opts Options, s Statement) error {
+		stmt = s
+		return nil
+	}}
+	tbl := NewConnection(qe).KeySpace("ks").MultimapMultiKeyTable(tablename, StorePK, StoreIndex, Store{})
+
+	stores := []Store{}
+	err := tbl.ListRange(map[string]interface{}{CityKey: "London"}, ListOptions{
+		Start: ExclusiveBound(map[string]interface{}{ManagerKey: "Joe", IdKey: "3"}),
+		End:   InclusiveBound(map[string]interface{}{ManagerKey: "Adam"}),
+		Order: DESC,
+		Limit: 10,
+	}, &stores).Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "SELECT address, city, id, manager FROM ks.store_multimapMk WHERE city = ? AND (manager, id) < (?,?) AND (manager) >= (?) ORDER BY Manager DESC, Id DESC LIMIT ?"
+	if stmt.Query() != expected {
+		t.Fatal(stmt.Query())
+	}
+	if !reflect.DeepEqual(stmt.Values(), []interface{}{"London", "Joe", "3", "Adam", 10}) {
+		t.Fatal(stmt.Values())
+	}
+}
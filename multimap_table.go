@@ -4,6 +4,8 @@ type multimapT struct {
 	t              Table
 	fieldToIndexBy string
 	idField        string
+	// versionField is the field holding the version of the rows, if their type has one
+	versionField string
 }
 
 func (mm *multimapT) Table() Table                        { return mm.t }
@@ -15,8 +17,15 @@ func (mm *multimapT) CreateStatement() (Statement, error) { return mm.Table().Cr
 func (mm *multimapT) CreateIfNotExistStatement() (Statement, error) {
 	return mm.Table().CreateIfNotExistStatement()
 }
+func (mm *multimapT) AlterOptions() error { return mm.Table().AlterOptions() }
+func (mm *multimapT) AlterOptionsStatement() (Statement, error) {
+	return mm.Table().AlterOptionsStatement()
+}
 
 func (mm *multimapT) Update(field, id interface{}, m map[string]interface{}) Op {
+	if mm.versionField != "" {
+		return versionedUpdate(mm.Table(), []Relation{Eq(mm.fieldToIndexBy, field), Eq(mm.idField, id)}, mm.versionField, m)
+	}
 	return mm.Table().
 		Where(Eq(mm.fieldToIndexBy, field),
 			Eq(mm.idField, id)).
@@ -24,6 +33,13 @@ func (mm *multimapT) Update(field, id interface{}, m map[string]interface{}) Op
 }
 
 func (mm *multimapT) Set(v interface{}) Op {
+	if mm.versionField != "" {
+		row, ok := toMap(v)
+		if !ok {
+			panic("Can't set: not able to convert")
+		}
+		return versionedSet(mm.Table(), []string{mm.fieldToIndexBy, mm.idField}, mm.versionField, row)
+	}
 	return mm.Table().
 		Set(v)
 }
@@ -60,10 +76,39 @@ func (mm *multimapT) List(field, startId interface{}, limit int, pointerToASlice
 		Read(pointerToASlice)
 }
 
+func (mm *multimapT) ListRange(field interface{}, opts ListOptions, pointerToASlice interface{}) Op {
+	clusteringKeys := []string{mm.idField}
+	bounds, err := opts.relations(clusteringKeys)
+	if err != nil {
+		return errOp{err: err}
+	}
+	return mm.Table().
+		WithOptions(Options{
+			Limit: opts.Limit,
+		}).
+		Where(append([]Relation{Eq(mm.fieldToIndexBy, field)}, bounds...)...).
+		Read(pointerToASlice).
+		WithOptions(Options{
+			ClusteringOrder: opts.clusteringOrder(clusteringKeys),
+		})
+}
+
+func (mm *multimapT) NextPage(opts ListOptions, pointerToASlice interface{}) (ListOptions, bool) {
+	return opts.next([]string{mm.idField}, pointerToASlice)
+}
+
 func (mm *multimapT) WithOptions(o Options) MultimapTable {
 	return &multimapT{
 		t:              mm.Table().WithOptions(o),
 		fieldToIndexBy: mm.fieldToIndexBy,
 		idField:        mm.idField,
+		versionField:   mm.versionField,
 	}
 }
+
+// unversioned returns a copy of the table whose writes aren't compare-and-set on the rows' version field
+func (mm *multimapT) unversioned() MultimapTable {
+	c := *mm
+	c.versionField = ""
+	return &c
+}
@@ -1,9 +1,42 @@
 package gocassa
 
-import "context"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
 
 type multiOp []Op
 
+// errConditionalBatch is returned when a conditional write is run in a batch with writes to other partitions, as a
+// batch of lightweight transactions must be confined to a single partition
+var errConditionalBatch = errors.New("conditional writes can only be batched with writes to the same partition")
+
+// errLazyBatch is returned when an op which runs queries of its own, such as one which reads before it writes, is run
+// in a batch, as there are no statements it could add to the batch
+var errLazyBatch = errors.New("ops which run queries of their own can't be run in a batch")
+
+// errMixedCounterBatch is returned when a counter update is run in a batch with other writes, as counters can only be
+// updated in a counter batch, which can't hold any other writes
+var errMixedCounterBatch = errors.New("counter updates can only be batched with other counter updates")
+
+// checkCounterBatch returns an error if some but not all of the ops are counter updates, which run in a counter batch
+func checkCounterBatch(ops []Op) error {
+	counters := 0
+	for _, op := range ops {
+		if op.Options().BatchType == CounterBatch {
+			counters++
+		}
+	}
+	if counters > 0 && counters < len(ops) {
+		return errMixedCounterBatch
+	}
+	return nil
+}
+
 func Noop() Op {
 	return multiOp(nil)
 }
@@ -12,8 +45,29 @@ func (mo multiOp) Run() error {
 	if err := mo.Preflight(); err != nil {
 		return err
 	}
-	for _, op := range mo {
-		if err := op.Run(); err != nil {
+	opts := mo.Options()
+	opts.TimeoutBudget = timeoutOptions(mo).TimeoutBudget
+	if opts.TimeoutBudget <= 0 {
+		for _, op := range mo {
+			if err := op.Run(); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	// Split what remains of the budget evenly between the remaining ops, so any time an op doesn't use is carried
+	// over to those which follow it
+	deadline := time.Now().Add(opts.TimeoutBudget)
+	for i, op := range mo {
+		share := time.Until(deadline) / time.Duration(len(mo)-i)
+		if share <= 0 {
+			return TimeoutError{Statement: op.GenerateStatement(), Timeout: opts.TimeoutBudget}
+		}
+		err := runWithTimeout(opts, share, op.GenerateStatement(), func(opts Options) error {
+			return op.WithOptions(Options{Context: opts.Context}).Run()
+		})
+		if err != nil {
 			return err
 		}
 	}
@@ -32,14 +86,67 @@ func (mo multiOp) runLoggedBatch() error {
 	if err := mo.Preflight(); err != nil {
 		return err
 	}
+	if err := checkCounterBatch(mo); err != nil {
+		return err
+	}
 	stmts := make([]Statement, len(mo))
+	conditional := -1
 	for i, op := range mo {
-		s := op.GenerateStatement()
-		stmts[i] = s
+		if _, ok := op.(lazyOp); ok {
+			return errLazyBatch
+		}
+		stmts[i] = op.GenerateStatement()
+		if conditional < 0 && isConditional(stmts[i]) {
+			conditional = i
+		}
 	}
 
 	qe := mo.QueryExecutor()
-	return qe.ExecuteAtomicallyWithOptions(mo.Options(), stmts)
+	opts := mo.Options()
+	timeout := timeoutOptions(mo).statementTimeout()
+	batch := batchStatement(opts.BatchType, stmts)
+	if conditional < 0 {
+		return runWithTimeout(opts, timeout, batch, func(opts Options) error {
+			return qe.ExecuteAtomicallyWithOptions(opts, stmts)
+		})
+	}
+
+	// A batch with conditions is applied as a whole or not at all. If it isn't, it fails as its first conditional
+	// write would on its own
+	if !samePartition(stmts) {
+		return errConditionalBatch
+	}
+	cqe, ok := qe.(ConditionalQueryExecutor)
+	if !ok {
+		return fmt.Errorf("the query executor can't execute conditional statements: %s", batch.Query())
+	}
+	return runWithTimeout(opts, timeout, batch, func(opts Options) error {
+		applied, current, err := cqe.ExecuteBatchConditionallyWithOptions(opts, stmts)
+		if err != nil {
+			return err
+		}
+		if !applied {
+			return conditionError(mo[conditional], ConditionFailedError{Statement: batch, Current: current})
+		}
+		return nil
+	})
+}
+
+// batchStatement returns a statement describing stmts executed as a single batch of the given type
+func batchStatement(batchType BatchType, stmts []Statement) Statement {
+	queries := make([]string, 0, len(stmts)+2)
+	values := []interface{}{}
+	if batchType == LoggedBatch {
+		queries = append(queries, "BEGIN BATCH")
+	} else {
+		queries = append(queries, "BEGIN "+batchType.String()+" BATCH")
+	}
+	for _, stmt := range stmts {
+		queries = append(queries, stmt.Query()+";")
+		values = append(values, stmt.Values()...)
+	}
+	queries = append(queries, "APPLY BATCH")
+	return cqlStatement{query: strings.Join(queries, "\n"), values: values}
 }
 
 func (mo multiOp) RunLoggedBatchWithContext(ctx context.Context) error {
@@ -54,6 +161,97 @@ func (mo multiOp) RunAtomicallyWithContext(ctx context.Context) error {
 	return mo.RunLoggedBatchWithContext(ctx)
 }
 
+func (mo multiOp) RunConcurrentlyWithContext(ctx context.Context, maxParallel int) error {
+	if err := mo.Preflight(); err != nil {
+		return err
+	}
+
+	// Unlike Run, ops may all be running at once and so each is given the whole of the remaining budget
+	var deadline time.Time
+	budget := timeoutOptions(mo).TimeoutBudget
+	if budget > 0 {
+		deadline = time.Now().Add(budget)
+	}
+	return runConcurrently(ctx, len(mo), maxParallel, nil, func(ctx context.Context, i int) error {
+		op := mo[i]
+		if deadline.IsZero() {
+			return op.WithOptions(Options{Context: ctx}).Run()
+		}
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return TimeoutError{Statement: op.GenerateStatement(), Timeout: budget}
+		}
+		return runWithTimeout(Options{Context: ctx}, remaining, op.GenerateStatement(), func(opts Options) error {
+			return op.WithOptions(Options{Context: opts.Context}).Run()
+		})
+	})
+}
+
+// runConcurrently calls run for each of n operations, with at most maxParallel running at once. The first failure
+// cancels the context passed to the operations still running and stops any more from starting. If start is not nil,
+// it is called for each operation in turn as it is about to start and may fail it in place of run.
+func runConcurrently(ctx context.Context, n, maxParallel int, start func(i int) error, run func(ctx context.Context, i int) error) error {
+	if n == 0 {
+		return nil
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if maxParallel <= 0 || maxParallel > n {
+		maxParallel = n
+	}
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mtx  sync.Mutex
+		wg   sync.WaitGroup
+		sem  = make(chan struct{}, maxParallel)
+		errs = MultiError{}
+	)
+	fail := func(i int, err error) {
+		mtx.Lock()
+		// Operations interrupted because an earlier one failed haven't failed in their own right
+		if len(errs) == 0 || !errors.Is(err, context.Canceled) {
+			errs[i] = err
+		}
+		mtx.Unlock()
+		cancel()
+	}
+
+	for i := 0; i < n; i++ {
+		select {
+		case sem <- struct{}{}:
+		case <-runCtx.Done():
+		}
+		if runCtx.Err() != nil {
+			break
+		}
+		if start != nil {
+			if err := start(i); err != nil {
+				fail(i, err)
+				break
+			}
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			if err := run(runCtx, i); err != nil {
+				fail(i, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return ctx.Err()
+}
+
 func (mo multiOp) GenerateStatement() Statement {
 	return noOpStatement{}
 }
@@ -0,0 +1,116 @@
+package gocassa
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultiOpRunConcurrently(t *testing.T) {
+	var (
+		mtx              sync.Mutex
+		running, maxSeen int
+		executed         []interface{}
+	)
+	qe := funcQE{fn: func(opts Options, stmt Statement) error {
+		mtx.Lock()
+		running++
+		if running > maxSeen {
+			maxSeen = running
+		}
+		executed = append(executed, stmt.Values()[0])
+		mtx.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+
+		mtx.Lock()
+		running--
+		mtx.Unlock()
+		return nil
+	}}
+	ks := NewConnection(qe).KeySpace("ks")
+	tbl := ks.MapTable("customers", "Id", Customer{})
+
+	op := Noop()
+	for _, id := range []string{"1", "2", "3", "4", "5", "6"} {
+		op = op.Add(tbl.Delete(id))
+	}
+	require.NoError(t, op.RunConcurrentlyWithContext(context.Background(), 2))
+	assert.Equal(t, 2, maxSeen)
+	assert.ElementsMatch(t, []interface{}{"1", "2", "3", "4", "5", "6"}, executed)
+}
+
+func TestMultiOpRunConcurrentlyCancelsOnError(t *testing.T) {
+	errBoom := errors.New("boom")
+	var (
+		mtx     sync.Mutex
+		started []interface{}
+	)
+	qe := funcQE{fn: func(opts Options, stmt Statement) error {
+		id := stmt.Values()[0]
+		mtx.Lock()
+		started = append(started, id)
+		mtx.Unlock()
+
+		if id == "2" {
+			time.Sleep(10 * time.Millisecond)
+			return errBoom
+		}
+		<-opts.Context.Done()
+		return opts.Context.Err()
+	}}
+	ks := NewConnection(qe).KeySpace("ks")
+	tbl := ks.MapTable("customers", "Id", Customer{})
+
+	op := tbl.Delete("1").Add(tbl.Delete("2"), tbl.Delete("3"), tbl.Delete("4"))
+	err := op.RunConcurrentlyWithContext(context.Background(), 2)
+
+	// The op blocked on the cancelled context isn't reported, and those queued behind it never start
+	var multiErr MultiError
+	require.True(t, errors.As(err, &multiErr), "expected a MultiError, got %v", err)
+	assert.Equal(t, []int{1}, multiErr.Indexes())
+	assert.True(t, errors.Is(err, errBoom))
+	assert.ElementsMatch(t, []interface{}{"1", "2"}, started)
+}
+
+func TestMultiOpRunConcurrentlyPreflight(t *testing.T) {
+	errInvalid := errors.New("invalid")
+	executed := 0
+	qe := funcQE{fn: func(Options, Statement) error {
+		executed++
+		return nil
+	}}
+	ks := NewConnection(qe).KeySpace("ks")
+	tbl := ks.MapTable("customers", "Id", Customer{})
+
+	err := tbl.Delete("1").Add(errOp{err: errInvalid}).RunConcurrentlyWithContext(context.Background(), 0)
+	assert.Equal(t, errInvalid, err)
+	assert.Equal(t, 0, executed)
+}
+
+func TestMultiOpBatchRejectsLazyOps(t *testing.T) {
+	var executed []Statement
+	qe := funcQE{fn: func(opts Options, stmt Statement) error {
+		executed = append(executed, stmt)
+		return nil
+	}}
+	tbl := NewConnection(qe).KeySpace("ks").MapTable("customers", "Id", Customer{}).
+		WithOptions(Options{MultiRead: MultiReadFanOut})
+
+	// A fan-out read runs its own queries, so it has no statement to add to the batch
+	res := []Customer{}
+	op := tbl.Set(Customer{Id: "1"}).Add(tbl.MultiRead([]interface{}{"1", "2"}, &res))
+	assert.Equal(t, errLazyBatch, op.RunAtomically())
+	assert.Equal(t, errLazyBatch, op.WithOptions(Options{BatchType: UnloggedBatch}).RunLoggedBatchWithContext(context.Background()))
+	assert.Empty(t, executed)
+
+	mockTbl := NewMockKeySpace().MapTable("customers", "Id", Customer{}).WithOptions(Options{MultiRead: MultiReadFanOut})
+	op = mockTbl.Set(Customer{Id: "1"}).Add(mockTbl.MultiRead([]interface{}{"1"}, &res))
+	assert.Equal(t, errLazyBatch, op.RunLoggedBatchWithContext(context.Background()))
+	assert.IsType(t, RowNotFoundError{}, mockTbl.Read("1", &Customer{}).Run())
+}
@@ -9,7 +9,8 @@ type multiTimeSeriesT struct {
 	indexField string
 	timeField  string
 	idField    string
-	bucketSize time.Duration
+	buckets    BucketStrategy
+	options    Options
 }
 
 func (o *multiTimeSeriesT) Table() Table                        { return o.t }
@@ -21,6 +22,10 @@ func (o *multiTimeSeriesT) CreateStatement() (Statement, error) { return o.Table
 func (o *multiTimeSeriesT) CreateIfNotExistStatement() (Statement, error) {
 	return o.Table().CreateIfNotExistStatement()
 }
+func (o *multiTimeSeriesT) AlterOptions() error { return o.Table().AlterOptions() }
+func (o *multiTimeSeriesT) AlterOptionsStatement() (Statement, error) {
+	return o.Table().AlterOptionsStatement()
+}
 
 func (o *multiTimeSeriesT) Set(v interface{}) Op {
 	m, ok := toMap(v)
@@ -30,14 +35,14 @@ func (o *multiTimeSeriesT) Set(v interface{}) Op {
 	if tim, ok := m[o.timeField].(time.Time); !ok {
 		panic("timeField is not actually a time.Time")
 	} else {
-		m[bucketFieldName] = bucket(tim, o.bucketSize)
+		m[bucketFieldName] = o.buckets.Bucket(tim)
 	}
 	return o.Table().
 		Set(m)
 }
 
 func (o *multiTimeSeriesT) Update(v interface{}, timeStamp time.Time, id interface{}, m map[string]interface{}) Op {
-	bucket := bucket(timeStamp, o.bucketSize)
+	bucket := o.buckets.Bucket(timeStamp)
 	return o.Table().
 		Where(Eq(o.indexField, v),
 			Eq(bucketFieldName, bucket),
@@ -47,7 +52,7 @@ func (o *multiTimeSeriesT) Update(v interface{}, timeStamp time.Time, id interfa
 }
 
 func (o *multiTimeSeriesT) Delete(v interface{}, timeStamp time.Time, id interface{}) Op {
-	bucket := bucket(timeStamp, o.bucketSize)
+	bucket := o.buckets.Bucket(timeStamp)
 	return o.Table().
 		Where(Eq(o.indexField, v),
 			Eq(bucketFieldName, bucket),
@@ -57,7 +62,7 @@ func (o *multiTimeSeriesT) Delete(v interface{}, timeStamp time.Time, id interfa
 }
 
 func (o *multiTimeSeriesT) Read(v interface{}, timeStamp time.Time, id, pointer interface{}) Op {
-	bucket := bucket(timeStamp, o.bucketSize)
+	bucket := o.buckets.Bucket(timeStamp)
 	return o.Table().
 		Where(Eq(o.indexField, v),
 			Eq(bucketFieldName, bucket),
@@ -72,18 +77,57 @@ func (o *multiTimeSeriesT) List(v interface{}, startTime time.Time, endTime time
 	for bucket := o.Buckets(v, startTime); bucket.Bucket().Before(endTime); bucket = bucket.Next() {
 		buckets = append(buckets, bucket.Bucket())
 	}
-	return o.Table().
+	read := o.Table().
 		Where(Eq(o.indexField, v),
 			In(bucketFieldName, buckets...),
 			GTE(o.timeField, startTime),
 			LTE(o.timeField, endTime)).
 		Read(pointerToASlice)
+	return mergedSeriesRead(read, o.options, o.timeField, timeSeriesOrder(o.timeField, []string{o.idField}), pointerToASlice)
+}
+
+func (o *multiTimeSeriesT) ListWithLimit(v interface{}, startTime, endTime time.Time, opts SeriesListOptions, pointerToASlice interface{}) Op {
+	return seriesWalk{
+		buckets:   func(start time.Time) Buckets { return o.Buckets(v, start) },
+		timeField: o.timeField,
+		idFields:  []string{o.idField},
+		start:     startTime,
+		end:       endTime,
+	}.list(opts, pointerToASlice)
+}
+
+func (o *multiTimeSeriesT) NextPage(opts SeriesListOptions, pointerToASlice interface{}) (SeriesListOptions, bool) {
+	return nextSeriesPage(opts, pointerToASlice, timeSeriesOrder(o.timeField, []string{o.idField}))
+}
+
+func (o *multiTimeSeriesT) ListSince(v interface{}, timeStamp time.Time, id interface{}, window time.Duration, pointerToASlice interface{}) Op {
+	return seriesReadSince(o.Table(), o.options, window, func(now time.Time) Op {
+		relations, err := seriesWindow(func(start time.Time) Buckets { return o.Buckets(v, start) }, o.timeField, []string{o.idField}, SeriesCursor{Time: timeStamp, ID: id}, window, now, ASC)
+		if err != nil {
+			return errOp{err: err}
+		}
+		read := o.Table().
+			Where(append([]Relation{Eq(o.indexField, v)}, relations...)...).
+			Read(pointerToASlice)
+		return mergedSeriesRead(read, o.options, o.timeField, timeSeriesOrder(o.timeField, []string{o.idField}), pointerToASlice)
+	})
+}
+
+func (o *multiTimeSeriesT) ListBefore(v interface{}, timeStamp time.Time, id interface{}, window time.Duration, pointerToASlice interface{}) Op {
+	relations, err := seriesWindow(func(start time.Time) Buckets { return o.Buckets(v, start) }, o.timeField, []string{o.idField}, SeriesCursor{Time: timeStamp, ID: id}, window, time.Time{}, DESC)
+	if err != nil {
+		return errOp{err: err}
+	}
+	read := o.Table().
+		Where(append([]Relation{Eq(o.indexField, v)}, relations...)...).
+		Read(pointerToASlice)
+	return mergedSeriesRead(read, o.options, o.timeField, timeSeriesOrder(o.timeField, []string{o.idField}), pointerToASlice)
 }
 
 func (o *multiTimeSeriesT) Buckets(v interface{}, start time.Time) Buckets {
 	return bucketIter{
 		v:         start,
-		step:      o.bucketSize,
+		strategy:  o.buckets,
 		field:     bucketFieldName,
 		invariant: o.Table().Where(Eq(o.indexField, v))}
 }
@@ -94,6 +138,7 @@ func (o *multiTimeSeriesT) WithOptions(opt Options) MultiTimeSeriesTable {
 		indexField: o.indexField,
 		timeField:  o.timeField,
 		idField:    o.idField,
-		bucketSize: o.bucketSize,
+		buckets:    o.buckets,
+		options:    o.options.Merge(opt),
 	}
 }
@@ -1,7 +1,9 @@
 package gocassa
 
 import (
+	"fmt"
 	"sort"
+	"time"
 
 	"context"
 )
@@ -21,6 +23,12 @@ type singleOp struct {
 	result  interface{}
 	m       map[string]interface{} // map for updates, sets etc
 	qe      QueryExecutor
+	// conditional writes are run as lightweight transactions: inserts IF NOT EXISTS, and updates or deletes IF the
+	// conditions hold, or IF EXISTS when there are none
+	conditional bool
+	conditions  []Relation
+	// conditionFailed, if set, returns the error the op fails with in place of a ConditionFailedError
+	conditionFailed func(ConditionFailedError) error
 }
 
 func (o *singleOp) Options() Options {
@@ -34,7 +42,11 @@ func (o *singleOp) WithOptions(opts Options) Op {
 		opType:  o.opType,
 		result:  o.result,
 		m:       o.m,
-		qe:      o.qe}
+		qe:      o.qe,
+
+		conditional:     o.conditional,
+		conditions:      o.conditions,
+		conditionFailed: o.conditionFailed}
 }
 
 func (o *singleOp) Add(additions ...Op) Op {
@@ -53,25 +65,73 @@ func newWriteOp(qe QueryExecutor, f filter, opType uint8, m map[string]interface
 		m:      m}
 }
 
+func newConditionalWriteOp(qe QueryExecutor, f filter, opType uint8, m map[string]interface{}, conditions []Relation) *singleOp {
+	op := newWriteOp(qe, f, opType, m)
+	op.conditional = true
+	op.conditions = conditions
+	return op
+}
+
 func (o *singleOp) Run() error {
+	timeout := timeoutOptions(o).statementTimeout()
 	switch o.opType {
 	case readOpType, singleReadOpType:
 		stmt := o.generateSelect(o.options)
 		scanner := NewScanner(stmt, o.result)
-		return o.qe.QueryWithOptions(o.options, stmt, scanner)
-	case insertOpType:
-		stmt := o.generateInsert(o.options)
-		return o.qe.ExecuteWithOptions(o.options, stmt)
-	case updateOpType:
-		stmt := o.generateUpdate(o.options)
-		return o.qe.ExecuteWithOptions(o.options, stmt)
-	case deleteOpType:
-		stmt := o.generateDelete(o.options)
-		return o.qe.ExecuteWithOptions(o.options, stmt)
+		return runWithTimeout(o.options, timeout, stmt, func(opts Options) error {
+			return o.qe.QueryWithOptions(opts, stmt, scanner)
+		})
+	case insertOpType, updateOpType, deleteOpType:
+		stmt := o.GenerateStatement()
+		if o.conditional {
+			return runWithTimeout(o.options, timeout, stmt, func(opts Options) error {
+				return conditionError(o, executeConditionally(o.qe, opts, stmt))
+			})
+		}
+		return runWithTimeout(o.options, timeout, stmt, func(opts Options) error {
+			return o.qe.ExecuteWithOptions(opts, stmt)
+		})
+	}
+	return nil
+}
+
+// executeConditionally executes the conditional statement stmt, returning a ConditionFailedError if it wasn't applied
+func executeConditionally(qe QueryExecutor, opts Options, stmt Statement) error {
+	cqe, ok := qe.(ConditionalQueryExecutor)
+	if !ok {
+		return fmt.Errorf("the query executor can't execute conditional statements: %s", stmt.Query())
+	}
+	applied, current, err := cqe.ExecuteConditionallyWithOptions(opts, stmt)
+	if err != nil {
+		return err
+	}
+	if !applied {
+		return ConditionFailedError{Statement: stmt, Current: current}
 	}
 	return nil
 }
 
+// onConditionFailed returns a copy of the op which fails with the error returned by conditionFailed in place of a
+// ConditionFailedError
+func (o *singleOp) onConditionFailed(conditionFailed func(ConditionFailedError) error) Op {
+	op := *o
+	op.conditionFailed = conditionFailed
+	return &op
+}
+
+// conditionError returns err, or the error op fails with in its place if err is a ConditionFailedError and op
+// replaces it
+func conditionError(op Op, err error) error {
+	cerr, ok := err.(ConditionFailedError)
+	if !ok {
+		return err
+	}
+	if so, ok := op.(*singleOp); ok && so.conditionFailed != nil {
+		return so.conditionFailed(cerr)
+	}
+	return err
+}
+
 func (o *singleOp) RunWithContext(ctx context.Context) error {
 	return o.WithOptions(Options{Context: ctx}).Run()
 }
@@ -88,6 +148,10 @@ func (o *singleOp) RunAtomicallyWithContext(ctx context.Context) error {
 	return o.RunLoggedBatchWithContext(ctx)
 }
 
+func (o *singleOp) RunConcurrentlyWithContext(ctx context.Context, maxParallel int) error {
+	return multiOp{o}.RunConcurrentlyWithContext(ctx, maxParallel)
+}
+
 func (o *singleOp) GenerateStatement() Statement {
 	switch o.opType {
 	case readOpType, singleReadOpType:
@@ -128,6 +192,8 @@ func (o *singleOp) generateInsert(opt Options) InsertStatement {
 		fieldMap: o.m,
 		ttl:      mopt.TTL,
 		keys:     o.f.t.info.keys,
+
+		ifNotExists: o.conditional,
 	}
 }
 
@@ -140,6 +206,9 @@ func (o *singleOp) generateUpdate(opt Options) UpdateStatement {
 		where:    o.f.rs,
 		ttl:      mopt.TTL,
 		keys:     o.f.t.info.keys,
+
+		ifExists:   o.conditional && len(o.conditions) == 0,
+		conditions: o.conditions,
 	}
 }
 
@@ -149,7 +218,48 @@ func (o *singleOp) generateDelete(opt Options) DeleteStatement {
 		table:    o.f.t.Name(),
 		where:    o.f.rs,
 		keys:     o.f.t.info.keys,
+
+		ifExists:   o.conditional && len(o.conditions) == 0,
+		conditions: o.conditions,
+	}
+}
+
+// timeoutOptions returns the Timeout and TimeoutBudget op runs with, which are those of its own options layered over
+// those of the tables its statements are on
+func timeoutOptions(op Op) Options {
+	var opts Options
+	switch o := op.(type) {
+	case *singleOp:
+		opts = o.f.t.options.Merge(o.options)
+	case multiOp:
+		for _, op := range o {
+			opts = opts.Merge(timeoutOptions(op))
+		}
+	default:
+		opts = op.Options()
+	}
+	return Options{Timeout: opts.Timeout, TimeoutBudget: opts.TimeoutBudget}
+}
+
+// runWithTimeout calls exec with opts, bounding its context by timeout if it is positive. If exec fails because the
+// timeout expired, a TimeoutError for stmt is returned.
+func runWithTimeout(opts Options, timeout time.Duration, stmt Statement, exec func(Options) error) error {
+	if timeout <= 0 {
+		return exec(opts)
+	}
+	parent := opts.Context
+	if parent == nil {
+		parent = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(parent, timeout)
+	defer cancel()
+
+	opts.Context = ctx
+	err := exec(opts)
+	if err != nil && ctx.Err() == context.DeadlineExceeded && parent.Err() == nil {
+		return TimeoutError{Statement: stmt, Timeout: timeout, err: err}
 	}
+	return err
 }
 
 func sortedKeys(m map[string]interface{}) []string {
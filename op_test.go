@@ -1,10 +1,16 @@
 package gocassa
 
 import (
+	"context"
+	"errors"
 	"strconv"
 	"strings"
+	"testing"
+	"time"
 
 	"github.com/gocql/gocql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 type OpTestStruct struct {
@@ -101,3 +107,215 @@ func (t *OpTestEnumType) UnmarshalCQL(_ gocql.TypeInfo, data []byte) error {
 	*t = values[string(data)]
 	return nil
 }
+
+// funcQE is a QueryExecutor which passes every statement it is given to fn, or to query (if set) for reads
+type funcQE struct {
+	fn    func(opts Options, stmt Statement) error
+	query func(stmt Statement, scanner Scanner) error
+}
+
+func (qe funcQE) Query(stmt Statement, scanner Scanner) error {
+	return qe.QueryWithOptions(Options{}, stmt, scanner)
+}
+
+func (qe funcQE) QueryWithOptions(opts Options, stmt Statement, scanner Scanner) error {
+	if qe.query != nil {
+		return qe.query(stmt, scanner)
+	}
+	return qe.fn(opts, stmt)
+}
+
+func (qe funcQE) Execute(stmt Statement) error {
+	return qe.ExecuteWithOptions(Options{}, stmt)
+}
+
+func (qe funcQE) ExecuteWithOptions(opts Options, stmt Statement) error {
+	return qe.fn(opts, stmt)
+}
+
+func (qe funcQE) ExecuteAtomically(stmts []Statement) error {
+	return qe.ExecuteAtomicallyWithOptions(Options{}, stmts)
+}
+
+func (qe funcQE) ExecuteAtomicallyWithOptions(opts Options, stmts []Statement) error {
+	return qe.fn(opts, batchStatement(opts.BatchType, stmts))
+}
+
+// blockingQE returns a QueryExecutor which blocks statements matching block until their context is done
+func blockingQE(block func(stmt Statement) bool) QueryExecutor {
+	return funcQE{fn: func(opts Options, stmt Statement) error {
+		if !block(stmt) {
+			return nil
+		}
+		if opts.Context == nil {
+			return errors.New("statement has no context")
+		}
+		<-opts.Context.Done()
+		return opts.Context.Err()
+	}}
+}
+
+func TestOpTimeout(t *testing.T) {
+	qe := blockingQE(func(Statement) bool { return true })
+	ks := NewConnection(qe).KeySpace("ks")
+	tbl := ks.MapTable("customers", "Id", Customer{})
+
+	err := tbl.Set(Customer{Id: "1", Name: "Joe"}).WithOptions(Options{Timeout: 10 * time.Millisecond}).Run()
+	var timeoutErr TimeoutError
+	require.True(t, errors.As(err, &timeoutErr), "expected a TimeoutError, got %v", err)
+	assert.Equal(t, 10*time.Millisecond, timeoutErr.Timeout)
+	assert.Contains(t, timeoutErr.Statement.Query(), "customers_map")
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+
+	// Timeouts set on the table apply to each of its statements
+	res := Customer{}
+	err = tbl.WithOptions(Options{Timeout: 10 * time.Millisecond}).Read("1", &res).Run()
+	require.True(t, errors.As(err, &timeoutErr), "expected a TimeoutError, got %v", err)
+	assert.Contains(t, timeoutErr.Statement.Query(), "SELECT")
+
+	// A deadline belonging to the caller isn't reported as a TimeoutError
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err = tbl.Delete("1").WithOptions(Options{Timeout: time.Minute}).RunWithContext(ctx)
+	assert.Equal(t, context.DeadlineExceeded, err)
+}
+
+func TestOpTimeoutBudget(t *testing.T) {
+	var remaining []time.Duration
+	qe := funcQE{fn: func(opts Options, stmt Statement) error {
+		deadline, ok := opts.Context.Deadline()
+		require.True(t, ok)
+		remaining = append(remaining, time.Until(deadline))
+		return nil
+	}}
+	ks := NewConnection(qe).KeySpace("ks")
+	tbl := ks.MapTable("customers", "Id", Customer{})
+
+	budget := 300 * time.Millisecond
+	op := tbl.Set(Customer{Id: "1"}).Add(tbl.Set(Customer{Id: "2"}), tbl.Set(Customer{Id: "3"}))
+	require.NoError(t, op.WithOptions(Options{TimeoutBudget: budget}).Run())
+
+	// Unused time is carried over, so each op is given all that remains divided by the ops left to run
+	require.Len(t, remaining, 3)
+	assert.InDelta(t, budget/3, remaining[0], float64(50*time.Millisecond))
+	assert.InDelta(t, budget/2, remaining[1], float64(50*time.Millisecond))
+	assert.InDelta(t, budget, remaining[2], float64(50*time.Millisecond))
+}
+
+func TestOpTimeoutBudgetExceeded(t *testing.T) {
+	qe := blockingQE(func(stmt Statement) bool {
+		return len(stmt.Values()) > 0 && stmt.Values()[len(stmt.Values())-1] == "2"
+	})
+	ks := NewConnection(qe).KeySpace("ks")
+	tbl := ks.MapTable("customers", "Id", Customer{})
+
+	op := tbl.Delete("1").Add(tbl.Delete("2"), tbl.Delete("3"))
+	err := op.WithOptions(Options{TimeoutBudget: 30 * time.Millisecond}).Run()
+	var timeoutErr TimeoutError
+	require.True(t, errors.As(err, &timeoutErr), "expected a TimeoutError, got %v", err)
+	assert.Equal(t, []interface{}{"2"}, timeoutErr.Statement.Values())
+	assert.True(t, timeoutErr.Timeout > 0 && timeoutErr.Timeout <= 15*time.Millisecond)
+
+	// The budget applies to logged batches as a whole
+	qe = blockingQE(func(Statement) bool { return true })
+	ks = NewConnection(qe).KeySpace("ks")
+	tbl = ks.MapTable("customers", "Id", Customer{})
+	op = tbl.Delete("1").Add(tbl.Delete("2"))
+	err = op.WithOptions(Options{TimeoutBudget: 10 * time.Millisecond}).RunLoggedBatchWithContext(context.Background())
+	require.True(t, errors.As(err, &timeoutErr), "expected a TimeoutError, got %v", err)
+	assert.True(t, strings.HasPrefix(timeoutErr.Statement.Query(), "BEGIN BATCH"))
+	assert.Equal(t, 10*time.Millisecond, timeoutErr.Timeout)
+
+	// Statements which don't start until the budget has been used up fail with the whole budget
+	qe = funcQE{fn: func(opts Options, stmt Statement) error {
+		time.Sleep(20 * time.Millisecond)
+		return nil
+	}}
+	tbl = NewConnection(qe).KeySpace("ks").MapTable("customers", "Id", Customer{})
+	op = tbl.Delete("1").Add(tbl.Delete("2"))
+	for _, run := range []func(Op) error{
+		func(op Op) error { return op.Run() },
+		func(op Op) error { return op.RunConcurrentlyWithContext(context.Background(), 1) },
+	} {
+		err = run(op.WithOptions(Options{TimeoutBudget: 10 * time.Millisecond}))
+		require.True(t, errors.As(err, &timeoutErr), "expected a TimeoutError, got %v", err)
+		assert.Equal(t, []interface{}{"2"}, timeoutErr.Statement.Values())
+		assert.Equal(t, 10*time.Millisecond, timeoutErr.Timeout)
+	}
+}
+
+func TestOpTableTimeouts(t *testing.T) {
+	qe := blockingQE(func(Statement) bool { return true })
+	tbl := NewConnection(qe).KeySpace("ks").MapTable("customers", "Id", Customer{})
+
+	// A Timeout set on the table bounds batches of its statements, as it does each statement on its own
+	var timeoutErr TimeoutError
+	timed := tbl.WithOptions(Options{Timeout: 10 * time.Millisecond})
+	err := timed.Delete("1").Add(timed.Delete("2")).RunLoggedBatchWithContext(context.Background())
+	require.True(t, errors.As(err, &timeoutErr), "expected a TimeoutError, got %v", err)
+	assert.True(t, strings.HasPrefix(timeoutErr.Statement.Query(), "BEGIN BATCH"))
+	assert.Equal(t, 10*time.Millisecond, timeoutErr.Timeout)
+
+	// As does a TimeoutBudget, for the ops run in turn or concurrently
+	budgeted := tbl.WithOptions(Options{TimeoutBudget: 10 * time.Millisecond})
+	op := budgeted.Delete("1").Add(budgeted.Delete("2"))
+	for _, run := range []func(Op) error{
+		func(op Op) error { return op.Run() },
+		func(op Op) error { return op.RunConcurrentlyWithContext(context.Background(), 1) },
+		func(op Op) error { return op.RunAtomically() },
+	} {
+		err = run(op)
+		require.True(t, errors.As(err, &timeoutErr), "expected a TimeoutError, got %v", err)
+		assert.True(t, timeoutErr.Timeout > 0 && timeoutErr.Timeout <= 10*time.Millisecond)
+	}
+}
+
+// casQE is a funcQE which executes conditional statements with cas
+type casQE struct {
+	funcQE
+	cas func(stmt Statement) (bool, map[string]interface{}, error)
+}
+
+func (qe casQE) ExecuteConditionallyWithOptions(opts Options, stmt Statement) (bool, map[string]interface{}, error) {
+	return qe.cas(stmt)
+}
+
+func (qe casQE) ExecuteBatchConditionallyWithOptions(opts Options, stmts []Statement) (bool, map[string]interface{}, error) {
+	return qe.cas(batchStatement(opts.BatchType, stmts))
+}
+
+func TestConditionalOps(t *testing.T) {
+	var stmts []Statement
+	current := map[string]interface{}{"id": "1", "name": "Joe"}
+	qe := casQE{
+		funcQE: funcQE{fn: func(opts Options, stmt Statement) error { return nil }},
+		cas: func(stmt Statement) (bool, map[string]interface{}, error) {
+			stmts = append(stmts, stmt)
+			return len(stmts) == 1, current, nil
+		},
+	}
+	tbl := NewConnection(qe).KeySpace("ks").Table("customers", Customer{}, Keys{PartitionKeys: []string{"Id"}})
+
+	require.NoError(t, tbl.SetIfNotExists(Customer{Id: "1", Name: "Joe"}).WithOptions(Options{TTL: time.Hour}).Run())
+	assert.Equal(t, "INSERT INTO ks.customers__Id__ (id, name) VALUES (?, ?) IF NOT EXISTS USING TTL ?", stmts[0].Query())
+	assert.Equal(t, []interface{}{"1", "Joe", 3600}, stmts[0].Values())
+
+	// Statements which aren't applied fail with the row their condition was checked against
+	err := tbl.Where(Eq("Id", "1")).UpdateIf(map[string]interface{}{"Name": "Jane"}, Eq("Name", "Jim")).Run()
+	var cerr ConditionFailedError
+	require.True(t, errors.As(err, &cerr), "expected a ConditionFailedError, got %v", err)
+	assert.Equal(t, current, cerr.Current)
+	assert.Equal(t, "UPDATE ks.customers__Id__ SET Name = ? WHERE id = ? IF name = ?", cerr.Statement.Query())
+
+	require.Error(t, tbl.Where(Eq("Id", "1")).DeleteIf().Run())
+	assert.Equal(t, "DELETE FROM ks.customers__Id__ WHERE id = ? IF EXISTS", stmts[2].Query())
+
+	// Conditional writes can only be batched with writes to the same partition
+	op := tbl.Set(Customer{Id: "2", Name: "Jane"}).Add(tbl.SetIfNotExists(Customer{Id: "1", Name: "Joe"}))
+	assert.Equal(t, errConditionalBatch, op.RunLoggedBatchWithContext(context.Background()))
+	assert.Len(t, stmts, 3)
+
+	// A QueryExecutor which can't execute conditional statements fails them rather than applying them unconditionally
+	tbl = NewConnection(qe.funcQE).KeySpace("ks").Table("customers", Customer{}, Keys{PartitionKeys: []string{"Id"}})
+	assert.Error(t, tbl.SetIfNotExists(Customer{Id: "1", Name: "Joe"}).Run())
+}
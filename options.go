@@ -25,6 +25,41 @@ func (d ColumnDirection) String() string {
 	}
 }
 
+// MultiReadStrategy determines how MapTable.MultiRead fetches the rows for its keys.
+type MultiReadStrategy uint8
+
+const (
+	// MultiReadIn fetches every key with a single query using an IN relation. This is the default.
+	MultiReadIn MultiReadStrategy = iota
+	// MultiReadFanOut fetches each key with its own single-partition query, running them concurrently. Each query
+	// can be routed directly to a replica owning its key by a token aware host selection policy, rather than
+	// leaving one coordinator to gather every partition. Keys with no row are reported in a PartialResultError.
+	MultiReadFanOut
+)
+
+// BatchType is the type of batch the statements of an Op run as a batch are executed in.
+type BatchType uint8
+
+const (
+	// LoggedBatch applies every statement of the batch, or none, across partitions. This is the default.
+	LoggedBatch BatchType = iota
+	// UnloggedBatch skips the batch log, so a batch spanning partitions may only be partially applied
+	UnloggedBatch
+	// CounterBatch is the only type of batch which may, and must, be used to update counters
+	CounterBatch
+)
+
+func (t BatchType) String() string {
+	switch t {
+	case UnloggedBatch:
+		return "UNLOGGED"
+	case CounterBatch:
+		return "COUNTER"
+	default:
+		return "LOGGED"
+	}
+}
+
 // ClusteringOrderColumn specifies a clustering column and whether its
 // clustering order is ASC or DESC.
 type ClusteringOrderColumn struct {
@@ -58,9 +93,34 @@ type Options struct {
 	// Setting CompactStorage to true enables table creation with compact storage
 	CompactStorage bool
 	// Compressor specifies the compressor (if any) to use on a newly created table
+	//
+	// Deprecated: Compressor is set with the legacy sstable_compression key, which Cassandra 3.0 and later reject. Use
+	// TableOptions.Compression instead.
 	Compressor string
+	// TableOptions specifies the properties, such as compaction and the default TTL, of a newly created table. They
+	// can be applied to an existing table with Table.AlterOptions
+	TableOptions *TableOptions
 	// Context allows a request context to passed, which is propagated to the QueryExecutor
 	Context context.Context
+	// Timeout bounds the time each statement may take to execute. If it is exceeded the statement's context is
+	// cancelled and a TimeoutError is returned
+	Timeout time.Duration
+	// TimeoutBudget bounds the total time taken by an Op made up of several statements. Each statement is given an
+	// even share of what remains of the budget, so time left unused by one statement is available to the rest
+	TimeoutBudget time.Duration
+	// MultiRead selects how MapTable.MultiRead fetches its rows. It is read from the table's options.
+	MultiRead MultiReadStrategy
+	// Parallelism bounds the number of queries an Op issuing several at once may have in flight. If zero, there is
+	// no limit
+	Parallelism int
+	// BatchType selects the type of batch RunLoggedBatchWithContext executes an Op's statements in. A CounterTable's
+	// ops use CounterBatch
+	BatchType BatchType
+	// Clock tells a LeaseTable or QueueTable the time, which it compares with when leases and claims expire, and tells
+	// the mock keyspace's tables when rows written with a TTL expire. It is read from the table's options, and if nil
+	// the system clock is used. A UniqueIndex, which has no options of its own, reads it from the options of its ops
+	// to date its claims and find the orphaned ones
+	Clock Clock
 }
 
 // Merge returns a new Options which is a right biased merge of the two initial Options.
@@ -73,7 +133,14 @@ func (o Options) Merge(neu Options) Options {
 		Select:          o.Select,
 		CompactStorage:  o.CompactStorage,
 		Compressor:      o.Compressor,
+		TableOptions:    o.TableOptions,
 		Context:         o.Context,
+		Timeout:         o.Timeout,
+		TimeoutBudget:   o.TimeoutBudget,
+		MultiRead:       o.MultiRead,
+		Parallelism:     o.Parallelism,
+		BatchType:       o.BatchType,
+		Clock:           o.Clock,
 	}
 	if neu.TTL != time.Duration(0) {
 		ret.TTL = neu.TTL
@@ -102,14 +169,45 @@ func (o Options) Merge(neu Options) Options {
 	if len(neu.Compressor) > 0 {
 		ret.Compressor = neu.Compressor
 	}
+	if neu.TableOptions != nil {
+		ret.TableOptions = neu.TableOptions
+	}
 	// Take the latest context added, so it can be overridden
 	if neu.Context != nil {
 		ret.Context = neu.Context
 	}
+	if neu.Timeout != time.Duration(0) {
+		ret.Timeout = neu.Timeout
+	}
+	if neu.TimeoutBudget != time.Duration(0) {
+		ret.TimeoutBudget = neu.TimeoutBudget
+	}
+	if neu.MultiRead != MultiReadIn {
+		ret.MultiRead = neu.MultiRead
+	}
+	if neu.Parallelism != 0 {
+		ret.Parallelism = neu.Parallelism
+	}
+	if neu.Clock != nil {
+		ret.Clock = neu.Clock
+	}
+	if neu.BatchType != LoggedBatch {
+		ret.BatchType = neu.BatchType
+	}
 
 	return ret
 }
 
+// statementTimeout returns the time a single statement may run for: the tighter of Timeout and TimeoutBudget, or
+// zero if neither is set.
+func (o Options) statementTimeout() time.Duration {
+	timeout := o.Timeout
+	if o.TimeoutBudget > 0 && (timeout <= 0 || o.TimeoutBudget < timeout) {
+		timeout = o.TimeoutBudget
+	}
+	return timeout
+}
+
 // AppendClusteringOrder adds a clustering order.  If there already clustering orders, the new one is added to the end.
 func (o Options) AppendClusteringOrder(column string, direction ColumnDirection) Options {
 	col := ClusteringOrderColumn{
@@ -0,0 +1,188 @@
+package gocassa
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gocql/gocql"
+)
+
+// unclaimed is the ClaimedUntil of messages which have never been claimed. Cassandra doesn't apply conditions
+// comparing null values, so it can't be left empty
+var unclaimed = time.Unix(0, 0).UTC()
+
+// QueueMessage is a message of a QueueTable.
+type QueueMessage struct {
+	Shard string
+	// Id is a TimeUUID holding the time the message was enqueued at
+	Id       string
+	Enqueued time.Time
+	Payload  []byte
+	// ClaimedBy is the consumer which last claimed the message, and ClaimedUntil when its claim expires
+	ClaimedBy    string
+	ClaimedUntil time.Time
+}
+
+// Cursor returns the position of the message in its shard, from which polling can be resumed.
+func (m QueueMessage) Cursor() SeriesCursor {
+	return SeriesCursor{Time: m.Enqueued, ID: m.Id}
+}
+
+// QueueCheckpoint records the last message of a shard of a QueueTable a consumer has handled.
+type QueueCheckpoint struct {
+	// Id identifies the consumer and shard
+	Id        string
+	Consumer  string
+	Shard     string
+	Enqueued  time.Time
+	MessageId string
+}
+
+// Cursor returns the position of the checkpointed message in its shard, from which polling can be resumed.
+func (c QueueCheckpoint) Cursor() SeriesCursor {
+	return SeriesCursor{Time: c.Enqueued, ID: c.MessageId}
+}
+
+func checkpointID(consumer, shard string) string {
+	return consumer + "/" + shard
+}
+
+type queueT struct {
+	messages    MultiTimeSeriesTable
+	checkpoints MapTable
+	buckets     BucketStrategy
+	options     Options
+}
+
+var errNoPollStart = errors.New("polling a queue needs the time to start from")
+
+func (q *queueT) Messages() MultiTimeSeriesTable {
+	return q.messages
+}
+
+func (q *queueT) Checkpoints() MapTable {
+	return q.checkpoints
+}
+
+func (q *queueT) clock() Clock {
+	if q.options.Clock == nil {
+		return SystemClock()
+	}
+	return q.options.Clock
+}
+
+// message returns the filter selecting the message of the shard with the given ID
+func (q *queueT) message(shard, id string) (Filter, error) {
+	enqueued, err := idTime(TimeUUIDs(), id)
+	if err != nil {
+		return nil, err
+	}
+	return q.messages.Table().Where(
+		Eq("Shard", shard),
+		Eq(bucketFieldName, q.buckets.Bucket(enqueued)),
+		Eq("Enqueued", enqueued),
+		Eq("Id", id)), nil
+}
+
+func (q *queueT) Enqueue(shard string, payload []byte) Op {
+	// Cassandra stores times to the millisecond, which the time of the ID is read back to
+	now := q.clock().Now().Truncate(time.Millisecond)
+	return q.messages.Set(QueueMessage{
+		Shard:        shard,
+		Id:           gocql.UUIDFromTime(now).String(),
+		Enqueued:     now,
+		Payload:      payload,
+		ClaimedUntil: unclaimed,
+	})
+}
+
+func (q *queueT) Poll(shard string, since SeriesCursor, limit int, messages *[]QueueMessage) Op {
+	if since.Time.IsZero() {
+		return errOp{err: errNoPollStart}
+	}
+	return newLazyOp(q.messages.Table().Set(QueueMessage{}).QueryExecutor(), func(opts Options) error {
+		now := q.clock().Now()
+		listOpts := SeriesListOptions{Limit: limit}
+		if since.ID != nil {
+			listOpts.After = &since
+		}
+		// Messages enqueued by writers whose clocks are a little ahead are listed too
+		end := now.Add(time.Minute)
+
+		visible := []QueueMessage{}
+		for {
+			var page []QueueMessage
+			if err := q.messages.ListWithLimit(shard, since.Time, end, listOpts, &page).WithOptions(opts).Run(); err != nil {
+				return err
+			}
+			for _, msg := range page {
+				if msg.ClaimedUntil.After(now) {
+					continue
+				}
+				visible = append(visible, msg)
+				if limit > 0 && len(visible) == limit {
+					*messages = visible
+					return nil
+				}
+			}
+
+			next, more := q.messages.NextPage(listOpts, &page)
+			if !more {
+				break
+			}
+			listOpts = next
+		}
+		*messages = visible
+		return nil
+	})
+}
+
+func (q *queueT) Claim(shard, id, consumer string, timeout time.Duration) Op {
+	f, err := q.message(shard, id)
+	if err != nil {
+		return errOp{err: err}
+	}
+	return newLazyOp(q.messages.Table().Set(QueueMessage{}).QueryExecutor(), func(opts Options) error {
+		now := q.clock().Now()
+		m := map[string]interface{}{"ClaimedBy": consumer, "ClaimedUntil": now.Add(timeout)}
+		err := f.UpdateIf(m, LTE("ClaimedUntil", now)).WithOptions(opts).Run()
+		if cerr, ok := err.(ConditionFailedError); ok {
+			if len(cerr.Current) == 0 {
+				return ErrMessageNotFound
+			}
+			return ErrMessageClaimed
+		}
+		return err
+	})
+}
+
+func (q *queueT) Ack(shard, id string) Op {
+	f, err := q.message(shard, id)
+	if err != nil {
+		return errOp{err: err}
+	}
+	return f.Delete()
+}
+
+func (q *queueT) Checkpoint(consumer string, msg QueueMessage) Op {
+	return q.checkpoints.Set(QueueCheckpoint{
+		Id:        checkpointID(consumer, msg.Shard),
+		Consumer:  consumer,
+		Shard:     msg.Shard,
+		Enqueued:  msg.Enqueued,
+		MessageId: msg.Id,
+	})
+}
+
+func (q *queueT) ReadCheckpoint(consumer, shard string, checkpoint *QueueCheckpoint) Op {
+	return q.checkpoints.Read(checkpointID(consumer, shard), checkpoint)
+}
+
+func (q *queueT) WithOptions(opt Options) QueueTable {
+	return &queueT{
+		messages:    q.messages.WithOptions(opt),
+		checkpoints: q.checkpoints.WithOptions(opt),
+		buckets:     q.buckets,
+		options:     q.options.Merge(opt),
+	}
+}
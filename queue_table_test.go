@@ -0,0 +1,98 @@
+package gocassa
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func payloads(messages []QueueMessage) []string {
+	result := make([]string, len(messages))
+	for i, msg := range messages {
+		result[i] = string(msg.Payload)
+	}
+	return result
+}
+
+func TestMockQueueTable(t *testing.T) {
+	start := time.Date(2020, 3, 4, 5, 6, 7, 0, time.UTC)
+	clock := NewMockClock(start)
+	queue := NewMockKeySpace().QueueTable("events", FixedBuckets(time.Hour)).WithOptions(Options{Clock: clock})
+
+	for _, msg := range []string{"a1", "a2", "a3"} {
+		require.NoError(t, queue.Enqueue("a", []byte(msg)).Run())
+		require.NoError(t, queue.Enqueue("b", []byte(strings.Replace(msg, "a", "b", 1))).Run())
+		clock.Advance(40 * time.Minute)
+	}
+
+	var messages []QueueMessage
+	require.NoError(t, queue.Poll("a", SeriesCursor{Time: start}, 0, &messages).Run())
+	assert.Equal(t, []string{"a1", "a2", "a3"}, payloads(messages))
+	require.NoError(t, queue.Poll("b", SeriesCursor{Time: start}, 2, &messages).Run())
+	assert.Equal(t, []string{"b1", "b2"}, payloads(messages))
+
+	// Claimed messages are hidden until the claim expires
+	first := messages[0]
+	require.NoError(t, queue.Claim("b", first.Id, "consumer-1", time.Minute).Run())
+	assert.Equal(t, ErrMessageClaimed, queue.Claim("b", first.Id, "consumer-2", time.Minute).Run())
+	require.NoError(t, queue.Poll("b", SeriesCursor{Time: start}, 2, &messages).Run())
+	assert.Equal(t, []string{"b2", "b3"}, payloads(messages))
+	clock.Advance(time.Minute)
+	require.NoError(t, queue.Poll("b", SeriesCursor{Time: start}, 2, &messages).Run())
+	assert.Equal(t, []string{"b1", "b2"}, payloads(messages))
+	assert.Equal(t, "consumer-1", messages[0].ClaimedBy)
+
+	// Acked messages are gone
+	require.NoError(t, queue.Claim("b", first.Id, "consumer-2", time.Minute).Run())
+	require.NoError(t, queue.Ack("b", first.Id).Run())
+	clock.Advance(time.Minute)
+	assert.Equal(t, ErrMessageNotFound, queue.Claim("b", first.Id, "consumer-1", time.Minute).Run())
+	require.NoError(t, queue.Poll("b", SeriesCursor{Time: start}, 0, &messages).Run())
+	assert.Equal(t, []string{"b2", "b3"}, payloads(messages))
+
+	// Consumers resume polling from their checkpoints
+	require.NoError(t, queue.Checkpoint("consumer-1", messages[0]).Run())
+	var checkpoint QueueCheckpoint
+	require.NoError(t, queue.ReadCheckpoint("consumer-1", "b", &checkpoint).Run())
+	assert.Equal(t, messages[0].Cursor(), checkpoint.Cursor())
+	require.NoError(t, queue.Poll("b", checkpoint.Cursor(), 0, &messages).Run())
+	assert.Equal(t, []string{"b3"}, payloads(messages))
+	assert.IsType(t, RowNotFoundError{}, queue.ReadCheckpoint("consumer-1", "a", &checkpoint).Run())
+
+	assert.Equal(t, errNoPollStart, queue.Poll("a", SeriesCursor{}, 0, &messages).Run())
+	assert.Error(t, queue.Ack("a", "not-a-uuid").Run())
+}
+
+func TestQueueTableEnqueueInBatch(t *testing.T) {
+	var stmts []Statement
+	qe := funcQE{fn: func(opts Options, stmt Statement) error {
+		stmts = append(stmts, stmt)
+		return nil
+	}}
+	now := time.Date(2020, 3, 4, 5, 6, 7, 8e6, time.UTC)
+	ks := NewConnection(qe).KeySpace("ks")
+	queue := ks.QueueTable("events", FixedBuckets(time.Hour)).WithOptions(Options{Clock: NewMockClock(now)})
+	customers := ks.MapTable("customers", "Id", Customer{})
+
+	// The message is written in the same batch as the write it announces
+	op := customers.Set(Customer{Id: "1", Name: "Joe"}).Add(queue.Enqueue("customers", []byte("created 1")))
+	require.NoError(t, op.RunLoggedBatchWithContext(context.Background()))
+	require.Len(t, stmts, 1)
+	assert.Equal(t, "BEGIN BATCH\n"+
+		"UPDATE ks.customers_map_Id SET name = ? WHERE id = ?;\n"+
+		"UPDATE ks.events_queue_multiTimeSeries_Shard_Enqueued_Id_1h0m0s SET claimedby = ?, claimeduntil = ?, payload = ? WHERE shard = ? AND bucket = ? AND enqueued = ? AND id = ?;\n"+
+		"APPLY BATCH", stmts[0].Query())
+	values := stmts[0].Values()
+	assert.Equal(t, []interface{}{"", unclaimed, []byte("created 1"), "customers"}, values[2:6])
+	assert.True(t, now.Truncate(time.Hour).Equal(values[6].(time.Time)))
+	assert.Equal(t, now, values[7])
+
+	// The message can be found from its ID
+	enqueued, err := TimeUUIDs().IDTime(values[8].(string))
+	require.NoError(t, err)
+	assert.True(t, now.Equal(enqueued.Truncate(time.Millisecond)))
+}
@@ -16,6 +16,7 @@ type Field struct {
 	index     []int
 	typ       reflect.Type
 	omitEmpty bool
+	version   bool
 }
 
 func (f Field) Name() string {
@@ -30,6 +31,11 @@ func (f Field) Index() []int {
 	return f.index
 }
 
+// IsVersion returns whether the field's tag has the "version" option, marking it as the version of the row.
+func (f Field) IsVersion() bool {
+	return f.version
+}
+
 func fillField(f Field) Field {
 	f.nameBytes = []byte(f.name)
 
@@ -141,6 +147,7 @@ func typeFields(t reflect.Type) []Field {
 						index:     index,
 						typ:       ft,
 						omitEmpty: opts.Contains("omitempty"),
+						version:   opts.Contains("version"),
 					}))
 					if count[f.typ] > 1 {
 						// If there were multiple instances, add a second,
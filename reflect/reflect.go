@@ -56,6 +56,23 @@ func StructFieldMap(structType r.Type, lowercaseFields bool) (map[string]Field,
 	return cachedTypeFieldMap(structType, lowercaseFields), nil
 }
 
+// VersionField returns the name of the field of a struct type whose tag has the
+// "version" option, if there is one. Example:
+//
+//   // Field appears as key "version" and holds the version of the row.
+//   Field int `cql:"version,version"`
+func VersionField(structType r.Type) (string, bool) {
+	if structType.Kind() != r.Struct {
+		return "", false
+	}
+	for _, field := range cachedTypeFields(structType) {
+		if field.IsVersion() {
+			return field.Name(), true
+		}
+	}
+	return "", false
+}
+
 // MapToStruct converts a map to a struct. It is the inverse of the StructToMap
 // function. For details see StructToMap.
 func MapToStruct(m map[string]interface{}, struc interface{}) error {
@@ -293,3 +293,25 @@ func assertValuesEqual(t *testing.T, a, b []interface{}) {
 		}
 	}
 }
+
+func TestVersionField(t *testing.T) {
+	type VersionedTweet struct {
+		Tweet
+		Version int64 `cql:"version,version"`
+	}
+
+	if name, ok := VersionField(reflect.TypeOf(VersionedTweet{})); !ok || name != "version" {
+		t.Errorf("expected the version field to be 'version', got %q (%v)", name, ok)
+	}
+	m, _ := StructFieldMap(reflect.TypeOf(VersionedTweet{}), false)
+	if !m["version"].IsVersion() || m["Text"].IsVersion() {
+		t.Errorf("expected only the version field to be the version: %+v", m)
+	}
+
+	if name, ok := VersionField(reflect.TypeOf(Tweet{})); ok {
+		t.Errorf("expected no version field, got %q", name)
+	}
+	if _, ok := VersionField(reflect.TypeOf(42)); ok {
+		t.Errorf("expected no version field for a non-struct type")
+	}
+}
@@ -0,0 +1,132 @@
+package gocassa
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// TableConflictError is returned when two tables defined on a keyspace resolve to the same name but have different
+// keys.
+type TableConflictError struct {
+	KeySpace string
+	Table    string
+	// Keys holds the keys of each conflicting definition, in the order they were defined
+	Keys []Keys
+}
+
+func (e TableConflictError) Error() string {
+	keys := make([]string, len(e.Keys))
+	for i, k := range e.Keys {
+		keys[i] = fmt.Sprintf("(partition keys: %s; clustering columns: %s)",
+			strings.Join(k.PartitionKeys, ", "), strings.Join(k.ClusteringColumns, ", "))
+	}
+	return fmt.Sprintf("table %s.%s is defined with conflicting keys: %s", e.KeySpace, e.Table, strings.Join(keys, " and "))
+}
+
+// tableRegistry keeps track of the tables defined on a keyspace, so they can be created, dropped and dumped together.
+// Definitions are kept by table name and keys, so defining a table again replaces its earlier definition rather than
+// adding another. Copies made with WithOptions, which may be made per request, are only registered when asked to.
+type tableRegistry struct {
+	mtx     sync.Mutex
+	seq     int
+	entries map[string][]*registeredTable
+}
+
+type registeredTable struct {
+	table Table
+	keys  Keys
+	// seq is the order the definition was registered in, so definitions of the same table are listed stably
+	seq int
+}
+
+func newTableRegistry() *tableRegistry {
+	return &tableRegistry{
+		entries: map[string][]*registeredTable{},
+	}
+}
+
+// register records the definition of table, replacing any earlier definition of it with the same name and keys
+func (r *tableRegistry) register(table Table, keys Keys) {
+	if r == nil {
+		return
+	}
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	name := strings.ToLower(table.Name())
+	for _, entry := range r.entries[name] {
+		if equalKeys(entry.keys, keys) {
+			entry.table = table
+			return
+		}
+	}
+	r.entries[name] = append(r.entries[name], &registeredTable{table: table, keys: keys, seq: r.seq})
+	r.seq++
+}
+
+// definitions returns the registered definitions, ordered by table name and then the order they were registered in
+func (r *tableRegistry) definitions() []*registeredTable {
+	if r == nil {
+		return nil
+	}
+	r.mtx.Lock()
+	defs := []*registeredTable{}
+	for _, entries := range r.entries {
+		for _, entry := range entries {
+			defs = append(defs, &registeredTable{table: entry.table, keys: entry.keys, seq: entry.seq})
+		}
+	}
+	r.mtx.Unlock()
+
+	sort.Slice(defs, func(i, j int) bool {
+		ni, nj := strings.ToLower(defs[i].table.Name()), strings.ToLower(defs[j].table.Name())
+		if ni != nj {
+			return ni < nj
+		}
+		return defs[i].seq < defs[j].seq
+	})
+	return defs
+}
+
+// tables returns one definition of each registered table, ordered by name. If any table is defined with conflicting
+// keys, a TableConflictError is returned.
+func (r *tableRegistry) tables(keySpace string) ([]Table, error) {
+	defs := r.definitions()
+	tables := []Table{}
+	for i := 0; i < len(defs); {
+		j := i + 1
+		for j < len(defs) && strings.EqualFold(defs[i].table.Name(), defs[j].table.Name()) {
+			j++
+		}
+		if j-i > 1 {
+			keys := []Keys{}
+			for _, def := range defs[i:j] {
+				keys = append(keys, def.keys)
+			}
+			return nil, TableConflictError{KeySpace: keySpace, Table: defs[i].table.Name(), Keys: keys}
+		}
+		tables = append(tables, defs[i].table)
+		i = j
+	}
+	return tables, nil
+}
+
+func equalKeys(a, b Keys) bool {
+	return equalStrings(a.PartitionKeys, b.PartitionKeys) &&
+		equalStrings(a.ClusteringColumns, b.ClusteringColumns) &&
+		a.Compound == b.Compound
+}
+
+// schemaCQL joins the statements creating each of the tables into a single CQL script
+func schemaCQL(stmts []Statement) string {
+	buf := &strings.Builder{}
+	for i, stmt := range stmts {
+		if i > 0 {
+			buf.WriteString("\n")
+		}
+		fmt.Fprintf(buf, "%s;\n", scriptStatement(stmt))
+	}
+	return buf.String()
+}
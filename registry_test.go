@@ -0,0 +1,155 @@
+package gocassa
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func registryTestKeySpace(executed *[]string) KeySpace {
+	return NewConnection(funcQE{fn: func(opts Options, stmt Statement) error {
+		*executed = append(*executed, stmt.Query())
+		return nil
+	}}).KeySpace("ks")
+}
+
+func TestRegistrySchemaCQL(t *testing.T) {
+	var executed []string
+	ks := registryTestKeySpace(&executed)
+	orders := ks.MultimapTable("orders", "Name", "Id", Customer2{})
+	ks.RegisterTable(orders.WithOptions(Options{TableName: "orders_by_name"}).Table())
+	ks.MapTable("customers", "Id", Customer{})
+	// Defining the same table again doesn't register it twice
+	ks.MapTable("customers", "Id", Customer{})
+
+	// The renamed copy of the multimap table is registered alongside it
+	tables := ks.RegisteredTables()
+	require.Len(t, tables, 3)
+	assert.Equal(t, "customers_map_Id", tables[0].Name())
+	assert.Equal(t, "orders_by_name", tables[1].Name())
+	assert.Equal(t, "orders_multimap_Name_Id", tables[2].Name())
+
+	schema, err := ks.SchemaCQL()
+	require.NoError(t, err)
+	assert.Equal(t, strings.Join([]string{
+		"CREATE TABLE IF NOT EXISTS ks.customers_map_Id (",
+		"    id varchar,",
+		"    name varchar,",
+		"    PRIMARY KEY ((id ))",
+		");",
+		"",
+		"CREATE TABLE IF NOT EXISTS ks.orders_by_name (",
+		"    id varchar,",
+		"    name varchar,",
+		"    tag varchar,",
+		"    PRIMARY KEY ((name), id)",
+		");",
+		"",
+		"CREATE TABLE IF NOT EXISTS ks.orders_multimap_Name_Id (",
+		"    id varchar,",
+		"    name varchar,",
+		"    tag varchar,",
+		"    PRIMARY KEY ((name), id)",
+		");",
+		"",
+	}, "\n"), schema)
+	assert.Empty(t, executed)
+
+	require.NoError(t, ks.CreateAllIfNotExist(context.Background()))
+	require.Len(t, executed, 3)
+	assert.True(t, strings.HasPrefix(executed[0], "CREATE TABLE IF NOT EXISTS ks.customers_map_Id"))
+	assert.True(t, strings.HasPrefix(executed[1], "CREATE TABLE IF NOT EXISTS ks.orders_by_name"))
+	assert.True(t, strings.HasPrefix(executed[2], "CREATE TABLE IF NOT EXISTS ks.orders_multimap_Name_Id"))
+
+	executed = nil
+	require.NoError(t, ks.DropAll())
+	assert.Equal(t, []string{
+		"DROP TABLE IF EXISTS ks.customers_map_Id",
+		"DROP TABLE IF EXISTS ks.orders_by_name",
+		"DROP TABLE IF EXISTS ks.orders_multimap_Name_Id",
+	}, executed)
+}
+
+func TestRegistryConflict(t *testing.T) {
+	var executed []string
+	ks := registryTestKeySpace(&executed)
+	ks.RegisterTable(ks.MapTable("customers", "Id", Customer{}).WithOptions(Options{TableName: "customers"}).Table())
+	ks.RegisterTable(ks.MultimapTable("customers", "Name", "Id", Customer{}).WithOptions(Options{TableName: "customers"}).Table())
+
+	_, err := ks.SchemaCQL()
+	var conflictErr TableConflictError
+	require.True(t, errors.As(err, &conflictErr), "expected a TableConflictError, got %v", err)
+	assert.Equal(t, "customers", conflictErr.Table)
+	assert.Equal(t, []Keys{
+		{PartitionKeys: []string{"Id"}},
+		{PartitionKeys: []string{"Name"}, ClusteringColumns: []string{"Id"}},
+	}, conflictErr.Keys)
+
+	// Nothing is created when any table conflicts
+	assert.Error(t, ks.CreateAllIfNotExist(context.Background()))
+	assert.Empty(t, executed)
+}
+
+func TestRegistryReplacesDefinitions(t *testing.T) {
+	var executed []string
+	for _, ks := range []KeySpace{registryTestKeySpace(&executed), NewMockKeySpace()} {
+		// Tables built over and over, such as once per request, replace their earlier definitions, and copies of them
+		// made with WithOptions aren't registered unless asked to be
+		for i := 0; i < 100; i++ {
+			ks.MapTable("customers", "Id", Customer{}).Table().WithOptions(Options{TableOptions: &TableOptions{Comment: "customers"}})
+			orders := ks.MultimapTable("orders", "Name", "Id", Customer2{})
+			orders.WithOptions(Options{TableName: fmt.Sprintf("orders_%d", i)})
+			ks.RegisterTable(orders.WithOptions(Options{TableName: "orders_by_name"}).Table())
+		}
+
+		var registry *tableRegistry
+		switch ks := ks.(type) {
+		case *k:
+			registry = ks.registry
+		case *mockKeySpace:
+			registry = ks.registry
+		}
+		assert.Len(t, registry.entries, 3)
+		assert.Len(t, registry.definitions(), 3)
+		tables := ks.RegisteredTables()
+		require.Len(t, tables, 3)
+		assert.Equal(t, "customers_map_Id", tables[0].Name())
+		assert.Equal(t, "orders_by_name", tables[1].Name())
+		assert.Equal(t, "orders_multimap_Name_Id", tables[2].Name())
+	}
+}
+
+func TestRegistryKeepsRenamedTables(t *testing.T) {
+	var executed []string
+	for _, ks := range []KeySpace{registryTestKeySpace(&executed), NewMockKeySpace()} {
+		users := ks.MapTable("users", "Id", Customer{})
+		archive := users.WithOptions(Options{TableName: "users_archive"})
+		ks.RegisterTable(archive.Table())
+		// Registering the archive again, such as to change its table options, replaces its definition
+		ks.RegisterTable(archive.WithOptions(Options{TableOptions: &TableOptions{Comment: "archive"}}).Table())
+
+		// The archive is registered as well as, rather than in place of, the table still in use
+		tables := ks.RegisteredTables()
+		require.Len(t, tables, 2)
+		assert.Equal(t, "users_archive", tables[0].Name())
+		assert.Equal(t, "users_map_Id", tables[1].Name())
+
+		schema, err := ks.SchemaCQL()
+		require.NoError(t, err)
+		assert.Contains(t, schema, "users_map_Id (\n")
+		assert.Contains(t, schema, "users_archive (\n")
+		assert.Contains(t, schema, "comment = 'archive'")
+	}
+
+	// Only tables defined on a keyspace can be registered
+	ks := NewMockKeySpace()
+	wrapped := struct{ Table }{ks.MapTable("users", "Id", Customer{}).Table()}
+	assert.PanicsWithValue(t, "Can't register table users_map_Id: it wasn't defined on a keyspace", func() {
+		ks.RegisterTable(wrapped)
+	})
+}
@@ -89,15 +89,27 @@ func convertToPrimitive(i interface{}) interface{} {
 	}
 }
 
+func (r Relation) isTuple() bool {
+	return r.Comparator() >= CmpTupleEquality
+}
+
 func (r Relation) accept(i interface{}) bool {
 	var result bool
 	var err error
 
+	if r.isTuple() {
+		return r.acceptTuple(i)
+	}
+
 	if r.Comparator() == CmpEquality || r.Comparator() == CmpIn {
 		return anyEquals(i, r.Terms())
 	}
 
 	a, b := convertToPrimitive(i), convertToPrimitive(r.Terms()[0])
+	// Nothing is greater or less than null, as in Cassandra
+	if a == nil || b == nil {
+		return false
+	}
 
 	switch r.Comparator() {
 	case CmpGreaterThan:
@@ -115,6 +127,53 @@ func (r Relation) accept(i interface{}) bool {
 	return err == nil && result
 }
 
+// acceptTuple compares the values of the tuple's fields with the terms in order, as Cassandra does
+func (r Relation) acceptTuple(i interface{}) bool {
+	values, ok := i.([]interface{})
+	if !ok || len(values) != len(r.Terms()) {
+		return false
+	}
+
+	// Values are compared by ordering them both ways rather than with ==, which panics for uncomparable types such as
+	// slices. Values which can't be ordered, including nulls, fail the comparison.
+	cmp := 0
+	for n, value := range values {
+		a, b := convertToPrimitive(value), convertToPrimitive(r.Terms()[n])
+		if a == nil || b == nil {
+			return false
+		}
+		less, err := builtinLessThan(a, b)
+		if err != nil {
+			return false
+		}
+		greater, err := builtinLessThan(b, a)
+		if err != nil {
+			return false
+		}
+		if less {
+			cmp = -1
+			break
+		} else if greater {
+			cmp = 1
+			break
+		}
+	}
+
+	switch r.Comparator() {
+	case CmpTupleEquality:
+		return cmp == 0
+	case CmpTupleGreaterThan:
+		return cmp > 0
+	case CmpTupleGreaterThanOrEquals:
+		return cmp >= 0
+	case CmpTupleLesserThan:
+		return cmp < 0
+	case CmpTupleLesserThanOrEquals:
+		return cmp <= 0
+	}
+	return false
+}
+
 func toI(i interface{}) []interface{} {
 	return []interface{}{i}
 }
@@ -32,6 +32,30 @@ func TestAnyEquals(t *testing.T) {
 	}
 }
 
+func TestAcceptTuple(t *testing.T) {
+	testCases := []struct {
+		relation Relation
+		values   []interface{}
+		accepted bool
+	}{
+		{TupleGT("k", "a", 1), makeInterfaceArray("a", 2), true},
+		{TupleGT("k", "a", 1), makeInterfaceArray("a", 1), false},
+		{TupleGTE("k", "a", 1), makeInterfaceArray("a", 1), true},
+		{TupleLT("k", "a", 1), makeInterfaceArray("0", 9), true},
+		{TupleEq("k", []byte("a"), 1), makeInterfaceArray([]byte("a"), 1), true},
+		// Values which can't be ordered fail the comparison rather than panicking
+		{TupleEq("k", []string{"a"}, 1), makeInterfaceArray([]string{"a"}, 1), false},
+		{TupleGT("k", map[string]int{}, 1), makeInterfaceArray(map[string]int{}, 2), false},
+		{TupleGT("k", "a", 1), makeInterfaceArray(nil, 2), false},
+	}
+
+	for _, tc := range testCases {
+		if accepted := tc.relation.accept(tc.values); accepted != tc.accepted {
+			t.Fatalf("expected %v, got %v (testcase: %v)", tc.accepted, accepted, tc)
+		}
+	}
+}
+
 func makeInterfaceArray(terms ...interface{}) []interface{} {
 	interfaceSlice := make([]interface{}, len(terms))
 	for i, d := range terms {
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"reflect"
 	"strings"
+	"sync"
 
 	"github.com/gocql/gocql"
 
@@ -67,16 +68,16 @@ func (s *scanner) iterSlice(iter Scannable) (int, error) {
 		sliceElem.Set(reflect.Zero(sliceType))
 	}
 
-	// Extract the type of the underlying struct
-	fieldMap, err := r.StructFieldMap(sliceElemValType, true)
+	// Work out which fields of the underlying struct the columns are decoded into
+	columns, err := decodePlan(sliceElemValType, s.stmt.Fields())
 	if err != nil {
-		return 0, fmt.Errorf("could not decode struct of type %v: %v", sliceElemValType, err)
+		return 0, err
 	}
 
 	rowsScanned := 0
 	for iter.Next() {
 		outVal := reflect.New(sliceElemValType).Elem()
-		ptrs := generatePtrs(s.stmt.Fields(), fieldMap, outVal)
+		ptrs := generatePtrs(columns, outVal)
 		err := iter.Scan(ptrs...)
 		if err != nil {
 			return rowsScanned, err
@@ -110,14 +111,14 @@ func (s *scanner) iterSingle(iter Scannable) (int, error) {
 		outVal = outVal.Elem() // we will eventually get to the underlying value
 	}
 
-	// Extract the type of the underlying struct and get it's field map
+	// Work out which fields of the underlying struct the columns are decoded into
 	resultBaseType := getNonPtrType(reflect.TypeOf(s.result))
-	fieldMap, err := r.StructFieldMap(resultBaseType, true)
+	columns, err := decodePlan(resultBaseType, s.stmt.Fields())
 	if err != nil {
-		return 0, fmt.Errorf("could not decode struct of type %v: %v", resultBaseType, err)
+		return 0, err
 	}
 
-	ptrs := generatePtrs(s.stmt.Fields(), fieldMap, outVal)
+	ptrs := generatePtrs(columns, outVal)
 	if !iter.Next() {
 		err := iter.Err()
 		if err == nil || err == gocql.ErrNotFound {
@@ -136,18 +137,58 @@ func (s *scanner) iterSingle(iter Scannable) (int, error) {
 	return 1, nil
 }
 
-// generatePtrs takes in a list of fields, the field map giving the type info
-// per field and the target struct value and generates a list of interface
-// pointers
-//
-// If a field is nil, it means it couldn't be matched and we insert an
-// IgnoreFieldType pointer instead. This means you will always get back
-// len(fields) pointers initialized
-func generatePtrs(fields []string, fieldMap map[string]r.Field, structVal reflect.Value) []interface{} {
-	ptrs := make([]interface{}, len(fields))
+// decodePlans caches the decodedColumns of each struct type and list of
+// columns read into it, so the struct's fields are only looked up by name
+// the first time
+var decodePlans sync.Map
+
+type decodePlanKey struct {
+	structType reflect.Type
+	fields     string
+}
+
+// decodedColumn is the field of a struct a column is decoded into
+type decodedColumn struct {
+	// index is the index of the field, as taken by reflect.Value.FieldByIndex
+	index []int
+	// ignored is set if the struct has no field for the column
+	ignored bool
+}
+
+// decodePlan returns the fields of structType each of the columns in fields
+// is decoded into
+func decodePlan(structType reflect.Type, fields []string) ([]decodedColumn, error) {
+	key := decodePlanKey{structType: structType, fields: strings.Join(fields, ",")}
+	if plan, ok := decodePlans.Load(key); ok {
+		return plan.([]decodedColumn), nil
+	}
+
+	fieldMap, err := r.StructFieldMap(structType, true)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode struct of type %v: %v", structType, err)
+	}
+	plan := make([]decodedColumn, len(fields))
 	for i, fieldName := range fields {
 		field, ok := fieldMap[strings.ToLower(fieldName)]
 		if !ok {
+			plan[i] = decodedColumn{ignored: true}
+			continue
+		}
+		plan[i] = decodedColumn{index: field.Index()}
+	}
+	decodePlans.Store(key, plan)
+	return plan, nil
+}
+
+// generatePtrs takes in the columns being decoded and the target struct
+// value and generates a list of interface pointers
+//
+// If a column has no field, we insert an IgnoreFieldType pointer instead.
+// This means you will always get back len(columns) pointers initialized
+func generatePtrs(columns []decodedColumn, structVal reflect.Value) []interface{} {
+	ptrs := make([]interface{}, len(columns))
+	for i, field := range columns {
+		if field.ignored {
 			ptrs[i] = &IgnoreFieldType{}
 			continue
 		}
@@ -157,15 +198,15 @@ func generatePtrs(fields []string, fieldMap map[string]r.Field, structVal reflec
 		// can't access them! We could be smarter here by allocating embedded
 		// pointers (if they aren't allocated already) and traversing the
 		// struct allocating all the way down as necessary
-		if len(field.Index()) > 1 {
-			elem := structVal.FieldByIndex([]int{field.Index()[0]})
+		if len(field.index) > 1 {
+			elem := structVal.FieldByIndex([]int{field.index[0]})
 			if elem.Kind() == reflect.Ptr && elem.IsNil() {
 				ptrs[i] = &IgnoreFieldType{}
 				continue
 			}
 		}
 
-		elem := structVal.FieldByIndex(field.Index())
+		elem := structVal.FieldByIndex(field.index)
 		if !elem.CanSet() {
 			ptrs[i] = &IgnoreFieldType{}
 			continue
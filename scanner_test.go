@@ -265,6 +265,39 @@ func TestScanIterEmbedded(t *testing.T) {
 	iter.Reset()
 }
 
+func TestScanDecodePlan(t *testing.T) {
+	results := []map[string]interface{}{
+		{"id": "acc_abcd1", "name": "John", "created": "2018-05-01 19:00:00+0000"},
+	}
+	fieldNames := []string{"id", "name", "created"}
+	stmt := SelectStatement{keyspace: "test", table: "bench", fields: fieldNames}
+	iter := newMockIterator(results, stmt.fields)
+
+	// The fields the columns are decoded into are worked out once for each struct type and list of columns
+	accounts := []Account{}
+	_, err := NewScanner(stmt, &accounts).ScanIter(iter)
+	require.NoError(t, err)
+	plan, ok := decodePlans.Load(decodePlanKey{structType: reflect.TypeOf(Account{}), fields: "id,name,created"})
+	require.True(t, ok)
+	assert.Equal(t, []decodedColumn{{index: []int{0}}, {index: []int{1}}, {ignored: true}}, plan)
+
+	iter.Reset()
+	account := Account{}
+	_, err = NewScanner(stmt, &account).ScanIter(iter)
+	require.NoError(t, err)
+	assert.Equal(t, Account{ID: "acc_abcd1", Name: "John"}, account)
+
+	// Nil pointers can't be decoded into
+	iter.Reset()
+	var nilAccounts *[]Account
+	_, err = NewScanner(stmt, nilAccounts).ScanIter(iter)
+	assert.Error(t, err)
+	iter.Reset()
+	var nilAccount *Account
+	_, err = NewScanner(stmt, nilAccount).ScanIter(iter)
+	assert.Error(t, err)
+}
+
 func TestScanWithSentinelValues(t *testing.T) {
 	type accountStruct struct {
 		ID       string
@@ -0,0 +1,575 @@
+package gocassa
+
+import (
+	"container/heap"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// SeriesCursor is the position of a row in a time or flake series, from which a listing can be resumed.
+type SeriesCursor struct {
+	// Time is the row's timestamp, which for flake series is the timestamp of its ID
+	Time time.Time
+	// ID is the row's ID, or for a MultiKeyTimeSeriesTable a map of its ID column values
+	ID interface{}
+}
+
+// SeriesListOptions specifies the rows listed by a ListWithLimit, which walks the buckets of a series one at a time.
+type SeriesListOptions struct {
+	// Order is the direction the series is walked in. ASC lists the earliest rows from the start of the range, and
+	// DESC the latest rows from its end.
+	Order ColumnDirection
+	// Limit is the maximum number of rows listed, or 0 to list the whole range
+	Limit int
+	// After resumes the listing after the row at this position, as returned by NextPage
+	After *SeriesCursor
+}
+
+// seriesWalk lists the rows of a series between start and end by querying its buckets one at a time
+type seriesWalk struct {
+	buckets func(start time.Time) Buckets
+	// timeField and idFields are the series' clustering columns
+	timeField    string
+	idFields     []string
+	start, end   time.Time
+	endExclusive bool
+	// idOrder, if set, is the order of the rows at the same time when it isn't the order of their IDs' strings, which
+	// is the order Cassandra knows
+	idOrder *seriesOrder
+}
+
+func (w seriesWalk) list(opts SeriesListOptions, pointerToASlice interface{}) Op {
+	if err := allocateNilReference(pointerToASlice); err != nil {
+		return errOp{err: err}
+	}
+	sliceType := getNonPtrType(reflect.TypeOf(pointerToASlice))
+	if sliceType.Kind() != reflect.Slice {
+		return errOp{err: fmt.Errorf("can't read into %T: expected a pointer to a slice", pointerToASlice)}
+	}
+	if opts.After != nil {
+		if _, ok := opts.After.ID.(map[string]interface{}); ok != (len(w.idFields) > 1) {
+			return errOp{err: fmt.Errorf("cursor ID %v doesn't match the ID columns %v", opts.After.ID, w.idFields)}
+		}
+	}
+
+	first := w.buckets(w.start)
+	qe := first.Filter().Read(pointerToASlice).QueryExecutor()
+	return newLazyOp(qe, func(runOpts Options) error {
+		rows := reflect.MakeSlice(sliceType, 0, opts.Limit)
+
+		for b := w.firstBucket(opts); w.inRange(b, opts.Order); b = w.nextBucket(b, opts.Order) {
+			limit := 0
+			if opts.Limit > 0 {
+				limit = opts.Limit - rows.Len()
+			}
+			var result reflect.Value
+			var err error
+			if w.idOrder != nil {
+				result, err = w.readBucketInIDOrder(b, opts, limit, runOpts, sliceType)
+			} else {
+				relations, ok := w.relations(b.Bucket(), opts)
+				if !ok {
+					continue
+				}
+				result, err = w.readBucket(b, relations, opts.Order, limit, runOpts, sliceType)
+			}
+			if err != nil {
+				return err
+			}
+
+			rows = reflect.AppendSlice(rows, result)
+			if opts.Limit > 0 && rows.Len() >= opts.Limit {
+				break
+			}
+		}
+
+		out := reflect.ValueOf(pointerToASlice)
+		for out.Kind() == reflect.Ptr {
+			out = out.Elem()
+		}
+		out.Set(rows)
+		return nil
+	})
+}
+
+// readBucket reads up to limit rows of a bucket selected by relations, or all of them if limit is 0
+func (w seriesWalk) readBucket(b Buckets, relations []Relation, order ColumnDirection, limit int, runOpts Options, sliceType reflect.Type) (reflect.Value, error) {
+	result := reflect.New(sliceType)
+	filter := b.Filter()
+	err := filter.Table().
+		Where(append(filter.Relations(), relations...)...).
+		Read(result.Interface()).
+		WithOptions(runOpts.Merge(Options{ClusteringOrder: w.clusteringOrder(order), Limit: limit})).
+		Run()
+	return result.Elem(), err
+}
+
+// readBucketInIDOrder reads up to limit rows of a bucket within the range and past the cursor, in the order of
+// w.idOrder. Cassandra orders the rows at the same time by their IDs' strings instead, so the rows at each time are
+// read whole and put in order before the cursor and limit are applied to them.
+func (w seriesWalk) readBucketInIDOrder(b Buckets, opts SeriesListOptions, limit int, runOpts Options, sliceType reflect.Type) (reflect.Value, error) {
+	// Only single column relations are used, so that the bounds can be moved on past the rows already read
+	lower, upper := GTE(w.timeField, w.start), LTE(w.timeField, w.end)
+	if w.endExclusive {
+		upper = LT(w.timeField, w.end)
+	}
+	var cursor *SeriesCursor
+	if opts.After != nil && b.Bucket().Equal(w.buckets(opts.After.Time).Bucket()) {
+		cursor = opts.After
+		if opts.Order == DESC && cursor.Time.Before(w.end) {
+			upper = LTE(w.timeField, cursor.Time)
+		} else if opts.Order != DESC && cursor.Time.After(w.start) {
+			lower = GTE(w.timeField, cursor.Time)
+		}
+	}
+
+	rows := reflect.MakeSlice(sliceType, 0, limit)
+	for {
+		queryLimit := 0
+		if limit > 0 {
+			queryLimit = limit - rows.Len()
+		}
+		result, err := w.readBucket(b, []Relation{lower, upper}, opts.Order, queryLimit, runOpts, sliceType)
+		if err != nil {
+			return rows, err
+		}
+
+		// The rows at the last time read may have been cut short by the limit, so they're read again in full and
+		// the rest of the bucket is read from after them
+		truncated := queryLimit > 0 && result.Len() >= queryLimit
+		if truncated {
+			last, ok := w.rowTime(result.Index(result.Len() - 1))
+			if !ok {
+				return reflect.AppendSlice(rows, result), nil
+			}
+			group, err := w.readBucket(b, []Relation{Eq(w.timeField, last)}, opts.Order, 0, runOpts, sliceType)
+			if err != nil {
+				return rows, err
+			}
+			n := result.Len()
+			for n > 0 {
+				if t, ok := w.rowTime(result.Index(n - 1)); !ok || !t.Equal(last) {
+					break
+				}
+				n--
+			}
+			result = reflect.AppendSlice(result.Slice(0, n), group)
+			if opts.Order == DESC {
+				upper = LT(w.timeField, last)
+			} else {
+				lower = GT(w.timeField, last)
+			}
+		}
+
+		if cursor != nil {
+			result = rowsPast(result, *w.idOrder, *cursor, opts.Order)
+		}
+		mergeSeriesRows(result, opts.Order, *w.idOrder)
+		rows = reflect.AppendSlice(rows, result)
+		if !truncated || (limit > 0 && rows.Len() >= limit) {
+			break
+		}
+	}
+	if limit > 0 && rows.Len() > limit {
+		rows = rows.Slice(0, limit)
+	}
+	return rows, nil
+}
+
+// rowTime returns the time of a row of the series ordered by w.idOrder
+func (w seriesWalk) rowTime(row reflect.Value) (time.Time, bool) {
+	m, ok := rowToMap(row.Interface())
+	if !ok {
+		return time.Time{}, false
+	}
+	position, ok := w.idOrder.position(m)
+	return position.Time, ok
+}
+
+// nextSeriesPage returns the options listing the rows following the rows in pointerToASlice, and whether there may be
+// any
+func nextSeriesPage(opts SeriesListOptions, pointerToASlice interface{}, order seriesOrder) (SeriesListOptions, bool) {
+	rows := reflect.Indirect(reflect.ValueOf(pointerToASlice))
+	if rows.Kind() != reflect.Slice || opts.Limit <= 0 || rows.Len() < opts.Limit {
+		return opts, false
+	}
+	last, ok := rowToMap(rows.Index(rows.Len() - 1).Interface())
+	if !ok {
+		return opts, false
+	}
+	cursor, ok := order.position(last)
+	if !ok {
+		return opts, false
+	}
+
+	opts.After = &cursor
+	return opts, true
+}
+
+// firstBucket returns the bucket the walk starts from
+func (w seriesWalk) firstBucket(opts SeriesListOptions) Buckets {
+	switch {
+	case opts.After != nil:
+		return w.buckets(opts.After.Time)
+	case opts.Order == DESC:
+		return w.buckets(w.end)
+	default:
+		return w.buckets(w.start)
+	}
+}
+
+func (w seriesWalk) nextBucket(b Buckets, order ColumnDirection) Buckets {
+	if order == DESC {
+		return b.Prev()
+	}
+	return b.Next()
+}
+
+// inRange returns whether the bucket may hold rows within the range
+func (w seriesWalk) inRange(b Buckets, order ColumnDirection) bool {
+	if order == DESC {
+		return !b.Bucket().Before(w.buckets(w.start).Bucket())
+	}
+	if w.endExclusive {
+		return b.Bucket().Before(w.end)
+	}
+	return !b.Bucket().After(w.end)
+}
+
+// relations returns the relations selecting the rows of a bucket within the range and after the cursor, and whether
+// the cursor leaves any rows of the bucket to select
+func (w seriesWalk) relations(bucket time.Time, opts SeriesListOptions) ([]Relation, bool) {
+	endRelation := LTE(w.timeField, w.end)
+	if w.endExclusive {
+		endRelation = LT(w.timeField, w.end)
+	}
+	if opts.After == nil || !bucket.Equal(w.buckets(opts.After.Time).Bucket()) {
+		return []Relation{GTE(w.timeField, w.start), endRelation}, true
+	}
+
+	// Within the cursor's bucket the rows are bounded by tuples of the clustering columns, as Cassandra doesn't allow
+	// a column to be restricted with both tuple and single column relations
+	columns, values := seriesTuple(w.timeField, w.idFields, *opts.After)
+	timeColumn := "(" + w.timeField + ")"
+
+	if opts.Order == DESC {
+		if opts.After.Time.Before(w.start) {
+			return nil, false
+		}
+		return []Relation{TupleLT(columns, values...), TupleGTE(timeColumn, w.start)}, true
+	}
+	end := TupleLTE(timeColumn, w.end)
+	if w.endExclusive {
+		end = TupleLT(timeColumn, w.end)
+	}
+	return []Relation{TupleGT(columns, values...), end}, true
+}
+
+// seriesTuple returns the tuple of a series' clustering columns, and their values at the cursor
+func seriesTuple(timeField string, idFields []string, cursor SeriesCursor) (string, []interface{}) {
+	values := []interface{}{cursor.Time}
+	if id, ok := cursor.ID.(map[string]interface{}); ok {
+		for _, field := range idFields {
+			values = append(values, id[field])
+		}
+	} else {
+		values = append(values, cursor.ID)
+	}
+	return "(" + strings.Join(append([]string{timeField}, idFields...), ", ") + ")", values
+}
+
+var errNoWindow = errors.New("listing the rows before a cursor needs a window")
+
+// seriesClock returns the clock of an op's options, or the system clock if they have none
+func seriesClock(opts Options) Clock {
+	if opts.Clock == nil {
+		return SystemClock()
+	}
+	return opts.Clock
+}
+
+// seriesReadSince returns the read of the rows of the series table tbl after a cursor built by read, which is passed
+// the time now. If the window of the rows is zero they're read up until a time after now, so the read is built when
+// it runs, with the time told by the Clock of its options layered over the table's.
+func seriesReadSince(tbl Table, options Options, window time.Duration, read func(now time.Time) Op) Op {
+	now := func(opts Options) time.Time {
+		return seriesClock(options.Merge(opts)).Now()
+	}
+	if window != 0 {
+		return read(now(Options{}))
+	}
+	return buildLazyOp(tbl.Where().Read(nil).QueryExecutor(), func(opts Options) Op {
+		return read(now(opts))
+	})
+}
+
+// seriesWindow returns the relations selecting the rows of a series after the cursor and within window of it, or
+// before it if direction is DESC. The rows are bounded by tuples of the clustering columns, so the rows at the same
+// time as the cursor are selected by their IDs. If the window of the rows after the cursor is zero they're selected
+// up until five minutes after now, but the rows before it always need a window.
+func seriesWindow(buckets func(start time.Time) Buckets, timeField string, idFields []string, cursor SeriesCursor, window time.Duration, now time.Time, direction ColumnDirection) ([]Relation, error) {
+	columns, values := seriesTuple(timeField, idFields, cursor)
+	timeColumn := "(" + timeField + ")"
+
+	if direction == DESC {
+		if window <= 0 {
+			return nil, errNoWindow
+		}
+		startTime := cursor.Time.Add(-window)
+		inBuckets := []interface{}{}
+		for bucket := buckets(startTime); !bucket.Bucket().After(cursor.Time); bucket = bucket.Next() {
+			inBuckets = append(inBuckets, bucket.Bucket())
+		}
+		return []Relation{
+			In(bucketFieldName, inBuckets...),
+			TupleGTE(timeColumn, startTime),
+			TupleLT(columns, values...),
+		}, nil
+	}
+
+	endTime := cursor.Time.Add(window)
+	if window == 0 {
+		endTime = now.Add(5 * time.Minute)
+	}
+	inBuckets := []interface{}{}
+	for bucket := buckets(cursor.Time); bucket.Bucket().Before(endTime); bucket = bucket.Next() {
+		inBuckets = append(inBuckets, bucket.Bucket())
+	}
+	return []Relation{
+		In(bucketFieldName, inBuckets...),
+		TupleGT(columns, values...),
+		TupleLT(timeColumn, endTime),
+	}, nil
+}
+
+func (w seriesWalk) clusteringOrder(order ColumnDirection) []ClusteringOrderColumn {
+	columns := append([]string{w.timeField}, w.idFields...)
+	return ListOptions{Order: order}.clusteringOrder(columns)
+}
+
+// seriesOrder is the order of the rows of a series, by their time and then their ID
+type seriesOrder struct {
+	// position reads the position of a row
+	position func(row map[string]interface{}) (SeriesCursor, bool)
+	// compareIDs compares the IDs of rows at the same time, returning -1, 0 or 1 as a sorts before, with or after b
+	compareIDs func(a, b interface{}) int
+}
+
+// timeSeriesOrder returns the order of a time series, whose rows are ordered by the values of their ID columns
+func timeSeriesOrder(timeField string, idFields []string) seriesOrder {
+	return seriesOrder{
+		position: func(row map[string]interface{}) (SeriesCursor, bool) {
+			value, _ := columnValue(row, timeField)
+			timestamp, ok := value.(time.Time)
+			if !ok {
+				return SeriesCursor{}, false
+			}
+			id, ok := seriesID(row, idFields)
+			return SeriesCursor{Time: timestamp, ID: id}, ok
+		},
+		compareIDs: func(a, b interface{}) int {
+			aIDs, bIDs := []interface{}{a}, []interface{}{b}
+			if aID, ok := a.(map[string]interface{}); ok {
+				bID, _ := b.(map[string]interface{})
+				aIDs, bIDs = nil, nil
+				for _, field := range idFields {
+					aIDs, bIDs = append(aIDs, aID[field]), append(bIDs, bID[field])
+				}
+			}
+			for i := range aIDs {
+				x, y := convertToPrimitive(aIDs[i]), convertToPrimitive(bIDs[i])
+				if x == y {
+					continue
+				}
+				if less, err := builtinLessThan(x, y); err == nil && less {
+					return -1
+				}
+				return 1
+			}
+			return 0
+		},
+	}
+}
+
+// flakeSeriesOrder returns the order of a flake series, whose rows are ordered by the times of their IDs and then
+// the order of the IDs' format
+func flakeSeriesOrder(idField string, decoder IDTimeDecoder) seriesOrder {
+	return seriesOrder{
+		position: func(row map[string]interface{}) (SeriesCursor, bool) {
+			value, _ := columnValue(row, idField)
+			id, ok := value.(string)
+			if !ok {
+				return SeriesCursor{}, false
+			}
+			timestamp, err := idTime(decoder, id)
+			return SeriesCursor{Time: timestamp, ID: id}, err == nil
+		},
+		compareIDs: func(a, b interface{}) int {
+			return decoder.CompareIDs(fmt.Sprint(a), fmt.Sprint(b))
+		},
+	}
+}
+
+// compare compares the positions of two rows, returning -1, 0 or 1 as a is before, at or after b
+func (s seriesOrder) compare(a, b SeriesCursor) int {
+	switch {
+	case a.Time.Before(b.Time):
+		return -1
+	case a.Time.After(b.Time):
+		return 1
+	}
+	return s.compareIDs(a.ID, b.ID)
+}
+
+// seriesID returns the ID of a row, which is a map of the ID column values if there are several
+func seriesID(row map[string]interface{}, idFields []string) (interface{}, bool) {
+	if len(idFields) == 1 {
+		return columnValue(row, idFields[0])
+	}
+	id := make(map[string]interface{}, len(idFields))
+	for _, field := range idFields {
+		value, ok := columnValue(row, field)
+		if !ok {
+			return nil, false
+		}
+		id[field] = value
+	}
+	return id, true
+}
+
+// mergedSeriesRead wraps a read of several buckets of a series, which Cassandra returns grouped by partition, merging
+// the rows into the order of the series' clustering columns. The rows of each partition are already in order, so
+// they're merged as sorted runs. The order is ascending unless the options order the time column descending.
+func mergedSeriesRead(read Op, options Options, timeField string, order seriesOrder, pointerToASlice interface{}) Op {
+	return wrapLazyOp(read, func(opts Options) error {
+		if err := read.WithOptions(opts).Run(); err != nil {
+			return err
+		}
+
+		direction := ASC
+		for _, o := range options.Merge(opts).ClusteringOrder {
+			if o.Column == timeField {
+				direction = o.Direction
+			}
+		}
+		rows := reflect.Indirect(reflect.ValueOf(pointerToASlice))
+		for rows.Kind() == reflect.Ptr {
+			rows = rows.Elem()
+		}
+		mergeSeriesRows(rows, direction, order)
+		return nil
+	})
+}
+
+// seriesRowsPast wraps a read of a series, leaving out the rows at or before the cursor, or at or after it if
+// direction is DESC. It bounds reads by the order of an ID format, which Cassandra only knows as the order of the
+// IDs' strings. Rows whose position can't be read are kept.
+func seriesRowsPast(read Op, order seriesOrder, cursor SeriesCursor, direction ColumnDirection, pointerToASlice interface{}) Op {
+	return wrapLazyOp(read, func(opts Options) error {
+		if err := read.WithOptions(opts).Run(); err != nil {
+			return err
+		}
+
+		rows := reflect.Indirect(reflect.ValueOf(pointerToASlice))
+		for rows.Kind() == reflect.Ptr {
+			rows = rows.Elem()
+		}
+		if rows.Kind() != reflect.Slice {
+			return nil
+		}
+		rows.Set(rowsPast(rows, order, cursor, direction))
+		return nil
+	})
+}
+
+// rowsPast returns the rows after the cursor, or before it if direction is DESC. Rows whose position can't be read
+// are kept.
+func rowsPast(rows reflect.Value, order seriesOrder, cursor SeriesCursor, direction ColumnDirection) reflect.Value {
+	past := func(cmp int) bool {
+		if direction == DESC {
+			return cmp < 0
+		}
+		return cmp > 0
+	}
+	kept := reflect.MakeSlice(rows.Type(), 0, rows.Len())
+	for i := 0; i < rows.Len(); i++ {
+		if row, ok := rowToMap(rows.Index(i).Interface()); ok {
+			if position, ok := order.position(row); ok && !past(order.compare(position, cursor)) {
+				continue
+			}
+		}
+		kept = reflect.Append(kept, rows.Index(i))
+	}
+	return kept
+}
+
+// mergeSeriesRows orders a slice of rows made of sorted runs with a k-way merge of the runs. If the position of any
+// row can't be read, the rows are left as they are.
+func mergeSeriesRows(rows reflect.Value, direction ColumnDirection, order seriesOrder) {
+	if rows.Kind() != reflect.Slice || rows.Len() < 2 {
+		return
+	}
+
+	positions := make([]SeriesCursor, rows.Len())
+	for i := range positions {
+		row, ok := rowToMap(rows.Index(i).Interface())
+		if !ok {
+			return
+		}
+		if positions[i], ok = order.position(row); !ok {
+			return
+		}
+	}
+	less := func(i, j int) bool {
+		cmp := order.compare(positions[i], positions[j])
+		if direction == DESC {
+			return cmp > 0
+		}
+		return cmp < 0
+	}
+
+	// Each run is the range [start, end) of rows in order
+	runs := &seriesRuns{less: less}
+	start := 0
+	for i := 1; i <= rows.Len(); i++ {
+		if i == rows.Len() || less(i, i-1) {
+			runs.runs = append(runs.runs, [2]int{start, i})
+			start = i
+		}
+	}
+	if len(runs.runs) == 1 {
+		return
+	}
+
+	heap.Init(runs)
+	merged := reflect.MakeSlice(rows.Type(), 0, rows.Len())
+	for runs.Len() > 0 {
+		run := &runs.runs[0]
+		merged = reflect.Append(merged, rows.Index(run[0]))
+		if run[0]++; run[0] == run[1] {
+			heap.Pop(runs)
+		} else {
+			heap.Fix(runs, 0)
+		}
+	}
+	reflect.Copy(rows, merged)
+}
+
+// seriesRuns is a heap of sorted runs of rows, ordered by the first row of each run
+type seriesRuns struct {
+	runs [][2]int
+	less func(i, j int) bool
+}
+
+func (h seriesRuns) Len() int            { return len(h.runs) }
+func (h seriesRuns) Less(i, j int) bool  { return h.less(h.runs[i][0], h.runs[j][0]) }
+func (h seriesRuns) Swap(i, j int)       { h.runs[i], h.runs[j] = h.runs[j], h.runs[i] }
+func (h *seriesRuns) Push(x interface{}) { h.runs = append(h.runs, x.([2]int)) }
+func (h *seriesRuns) Pop() interface{} {
+	last := h.runs[len(h.runs)-1]
+	h.runs = h.runs[:len(h.runs)-1]
+	return last
+}
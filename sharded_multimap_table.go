@@ -0,0 +1,264 @@
+package gocassa
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"reflect"
+
+	"github.com/gocql/gocql"
+)
+
+// shardFieldName is the column of a sharded table holding the shard of each row, which is part of its partition key
+const shardFieldName = "shard"
+
+type shardedMultimapT struct {
+	t              Table
+	fieldToIndexBy string
+	idField        string
+	shards         int
+	// versionField is the field holding the version of the rows, if their type has one
+	versionField string
+	options      Options
+}
+
+func (mm *shardedMultimapT) Table() Table                        { return mm.t }
+func (mm *shardedMultimapT) Create() error                       { return mm.Table().Create() }
+func (mm *shardedMultimapT) CreateIfNotExist() error             { return mm.Table().CreateIfNotExist() }
+func (mm *shardedMultimapT) Name() string                        { return mm.Table().Name() }
+func (mm *shardedMultimapT) Recreate() error                     { return mm.Table().Recreate() }
+func (mm *shardedMultimapT) CreateStatement() (Statement, error) { return mm.Table().CreateStatement() }
+func (mm *shardedMultimapT) CreateIfNotExistStatement() (Statement, error) {
+	return mm.Table().CreateIfNotExistStatement()
+}
+func (mm *shardedMultimapT) AlterOptions() error { return mm.Table().AlterOptions() }
+func (mm *shardedMultimapT) AlterOptionsStatement() (Statement, error) {
+	return mm.Table().AlterOptionsStatement()
+}
+
+// shardOf returns the shard of the row with the given clustering key, from a hash of the key as Cassandra encodes it.
+// Integers are hashed as bigints whatever their Go type, so that a key finds the same shard whether it's an int32 or
+// an int64.
+func shardOf(id interface{}, shards int) (int, error) {
+	switch v := reflect.ValueOf(id); v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		id = v.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		id = int64(v.Uint())
+	}
+	data, err := gocql.Marshal(gocqlTypeInfo{proto: 0x03, typ: cassaType(id)}, id)
+	if err != nil {
+		return 0, fmt.Errorf("can't work out the shard of %v: %w", id, err)
+	}
+	h := fnv.New32a()
+	h.Write(data)
+	return int(h.Sum32() % uint32(shards)), nil
+}
+
+// allShards returns the values of the shard column of every shard
+func (mm *shardedMultimapT) allShards() []interface{} {
+	shards := make([]interface{}, mm.shards)
+	for i := range shards {
+		shards[i] = i
+	}
+	return shards
+}
+
+// rowRelations returns the relations selecting the row with the given keys
+func (mm *shardedMultimapT) rowRelations(field, id interface{}) ([]Relation, error) {
+	shard, err := shardOf(id, mm.shards)
+	if err != nil {
+		return nil, err
+	}
+	return []Relation{Eq(mm.fieldToIndexBy, field), Eq(shardFieldName, shard), Eq(mm.idField, id)}, nil
+}
+
+func (mm *shardedMultimapT) Update(field, id interface{}, m map[string]interface{}) Op {
+	relations, err := mm.rowRelations(field, id)
+	if err != nil {
+		return errOp{err: err}
+	}
+	if mm.versionField != "" {
+		return versionedUpdate(mm.Table(), relations, mm.versionField, m)
+	}
+	return mm.Table().
+		Where(relations...).
+		Update(m)
+}
+
+func (mm *shardedMultimapT) Set(v interface{}) Op {
+	m, ok := toMap(v)
+	if !ok {
+		panic("Can't set: not able to convert")
+	}
+	shard, err := shardOf(m[mm.idField], mm.shards)
+	if err != nil {
+		return errOp{err: err}
+	}
+	m[shardFieldName] = shard
+
+	if mm.versionField != "" {
+		return versionedSet(mm.Table(), []string{mm.fieldToIndexBy, shardFieldName, mm.idField}, mm.versionField, m)
+	}
+	return mm.Table().
+		Set(m)
+}
+
+func (mm *shardedMultimapT) Delete(field, id interface{}) Op {
+	relations, err := mm.rowRelations(field, id)
+	if err != nil {
+		return errOp{err: err}
+	}
+	return mm.Table().
+		Where(relations...).
+		Delete()
+}
+
+func (mm *shardedMultimapT) DeleteAll(field interface{}) Op {
+	return mm.Table().
+		Where(Eq(mm.fieldToIndexBy, field), In(shardFieldName, mm.allShards()...)).
+		Delete()
+}
+
+func (mm *shardedMultimapT) Read(field, id, pointer interface{}) Op {
+	relations, err := mm.rowRelations(field, id)
+	if err != nil {
+		return errOp{err: err}
+	}
+	return mm.Table().
+		Where(relations...).
+		ReadOne(pointer)
+}
+
+func (mm *shardedMultimapT) List(field, startId interface{}, limit int, pointerToASlice interface{}) Op {
+	var bounds []Relation
+	if startId != nil {
+		bounds = append(bounds, GTE(mm.idField, startId))
+	}
+	return mm.fanOutList(field, bounds, limit, ASC, pointerToASlice)
+}
+
+func (mm *shardedMultimapT) ListRange(field interface{}, opts ListOptions, pointerToASlice interface{}) Op {
+	bounds, err := opts.relations([]string{mm.idField})
+	if err != nil {
+		return errOp{err: err}
+	}
+	return mm.fanOutList(field, bounds, opts.Limit, opts.Order, pointerToASlice)
+}
+
+func (mm *shardedMultimapT) NextPage(opts ListOptions, pointerToASlice interface{}) (ListOptions, bool) {
+	return opts.next([]string{mm.idField}, pointerToASlice)
+}
+
+// fanOutList reads up to limit rows within the bounds from each shard of the partition, running the reads
+// concurrently, and merges them into pointerToASlice in the order of their clustering key, keeping the first limit
+// rows.
+func (mm *shardedMultimapT) fanOutList(field interface{}, bounds []Relation, limit int, order ColumnDirection, pointerToASlice interface{}) Op {
+	if err := allocateNilReference(pointerToASlice); err != nil {
+		return errOp{err: err}
+	}
+	sliceType := getNonPtrType(reflect.TypeOf(pointerToASlice))
+	if sliceType.Kind() != reflect.Slice {
+		return errOp{err: fmt.Errorf("can't read into %T: expected a pointer to a slice", pointerToASlice)}
+	}
+
+	clusteringOrder := ListOptions{Order: order}.clusteringOrder([]string{mm.idField})
+	qe := mm.Table().Where(Eq(mm.fieldToIndexBy, field)).Read(pointerToASlice).QueryExecutor()
+	return newLazyOp(qe, func(opts Options) error {
+		// The reads are built afresh each time the op runs, so that runs of it don't share results
+		results := make([]reflect.Value, mm.shards)
+		reads := Noop()
+		for shard := range results {
+			results[shard] = reflect.New(sliceType)
+			relations := append([]Relation{Eq(mm.fieldToIndexBy, field), Eq(shardFieldName, shard)}, bounds...)
+			reads = reads.Add(mm.Table().
+				WithOptions(Options{
+					Limit: limit,
+				}).
+				Where(relations...).
+				Read(results[shard].Interface()).
+				WithOptions(Options{
+					ClusteringOrder: clusteringOrder,
+				}))
+		}
+		parallelism := mm.options.Merge(opts).Parallelism
+		if err := reads.WithOptions(opts).RunConcurrentlyWithContext(opts.Context, parallelism); err != nil {
+			return err
+		}
+
+		// The rows of each shard are already in order, so they're merged as sorted runs
+		rows := reflect.MakeSlice(sliceType, 0, limit)
+		for _, result := range results {
+			rows = reflect.AppendSlice(rows, result.Elem())
+		}
+		mergeSeriesRows(rows, order, clusteringKeyOrder(mm.idField))
+		if limit > 0 && rows.Len() > limit {
+			rows = rows.Slice(0, limit)
+		}
+
+		out := reflect.ValueOf(pointerToASlice)
+		for out.Kind() == reflect.Ptr {
+			out = out.Elem()
+		}
+		out.Set(rows)
+		return nil
+	})
+}
+
+// clusteringKeyOrder returns the order of rows by the value of a single clustering column
+func clusteringKeyOrder(field string) seriesOrder {
+	return seriesOrder{
+		position: func(row map[string]interface{}) (SeriesCursor, bool) {
+			id, ok := columnValue(row, field)
+			return SeriesCursor{ID: id}, ok
+		},
+		compareIDs: timeSeriesOrder("", []string{field}).compareIDs,
+	}
+}
+
+func (mm *shardedMultimapT) WithOptions(o Options) MultimapTable {
+	return &shardedMultimapT{
+		t:              mm.Table().WithOptions(o),
+		fieldToIndexBy: mm.fieldToIndexBy,
+		idField:        mm.idField,
+		shards:         mm.shards,
+		versionField:   mm.versionField,
+		options:        mm.options.Merge(o),
+	}
+}
+
+// unversioned returns a copy of the table whose writes aren't compare-and-set on the rows' version field
+func (mm *shardedMultimapT) unversioned() MultimapTable {
+	c := *mm
+	c.versionField = ""
+	return &c
+}
+
+// CopyMultimapPartition copies the rows of a partition of one MultimapTable holding rows of type T to another, a page
+// of pageSize rows at a time. It backfills a partition when moving between tables with different layouts, such as
+// from a MultimapTable to a sharded one holding the same rows.
+func CopyMultimapPartition[T any](ctx context.Context, from, to MultimapTable, partitionKey interface{}, pageSize int) error {
+	if pageSize <= 0 {
+		return fmt.Errorf("page size must be positive, got %d", pageSize)
+	}
+	opts := ListOptions{Limit: pageSize}
+	for {
+		rows := []T{}
+		if err := from.ListRange(partitionKey, opts, &rows).RunWithContext(ctx); err != nil {
+			return err
+		}
+		writes := Noop()
+		for _, row := range rows {
+			writes = writes.Add(to.Set(row))
+		}
+		if err := writes.RunWithContext(ctx); err != nil {
+			return err
+		}
+
+		next, more := from.NextPage(opts, &rows)
+		if !more {
+			return nil
+		}
+		opts = next
+	}
+}
@@ -0,0 +1,220 @@
+package gocassa
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShardedMultimapStatements(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		stmts []Statement
+	)
+	qe := funcQE{
+		fn: func(opts Options, stmt Statement) error {
+			mu.Lock()
+			defer mu.Unlock()
+			stmts = append(stmts, stmt)
+			return nil
+		},
+		query: func(stmt Statement, scanner Scanner) error {
+			mu.Lock()
+			stmts = append(stmts, stmt)
+			mu.Unlock()
+			_, err := scanner.ScanIter(newMockIterator(nil, stmt.(SelectStatement).fields))
+			return err
+		},
+	}
+	members := NewConnection(qe).KeySpace("ks").ShardedMultimapTable("member", "Team", "Id", 4, member{})
+
+	stmt, err := members.CreateStatement()
+	require.NoError(t, err)
+	assert.Contains(t, stmt.Query(), "CREATE TABLE ks.member_multimap_Team_Id_sharded4 (")
+	assert.Contains(t, stmt.Query(), "PRIMARY KEY ((team, shard), id)")
+
+	shard, err := shardOf("m1", 4)
+	require.NoError(t, err)
+	require.NoError(t, members.Set(member{Id: "m1", Email: "joe@example.com", Team: "core"}).Run())
+	assert.Equal(t, "UPDATE ks.member_multimap_Team_Id_sharded4 SET email = ? WHERE team = ? AND shard = ? AND id = ?", stmts[0].Query())
+	assert.Equal(t, []interface{}{"joe@example.com", "core", shard, "m1"}, stmts[0].Values())
+
+	var m member
+	assert.IsType(t, RowNotFoundError{}, members.Read("core", "m1", &m).Run())
+	assert.Equal(t, "SELECT email, id, team, shard FROM ks.member_multimap_Team_Id_sharded4 WHERE team = ? AND shard = ? AND id = ?", stmts[1].Query())
+
+	stmts = nil
+	var ms []member
+	require.NoError(t, members.List("core", "m1", 10, &ms).Run())
+	require.Len(t, stmts, 4)
+	read := []interface{}{}
+	for _, stmt := range stmts {
+		assert.Equal(t, "SELECT email, id, team, shard FROM ks.member_multimap_Team_Id_sharded4 WHERE team = ? AND shard = ? AND id >= ? ORDER BY Id ASC LIMIT ?", stmt.Query())
+		read = append(read, stmt.Values()[1])
+	}
+	assert.ElementsMatch(t, []interface{}{0, 1, 2, 3}, read)
+
+	stmts = nil
+	require.NoError(t, members.DeleteAll("core").Run())
+	assert.Equal(t, "DELETE FROM ks.member_multimap_Team_Id_sharded4 WHERE team = ? AND shard IN ?", stmts[0].Query())
+}
+
+func TestShardOfIntegers(t *testing.T) {
+	want, err := shardOf(int64(5), 7)
+	require.NoError(t, err)
+	for _, id := range []interface{}{5, int8(5), int32(5), uint16(5), uint64(5)} {
+		shard, err := shardOf(id, 7)
+		require.NoError(t, err)
+		assert.Equal(t, want, shard, "%T", id)
+	}
+}
+
+func TestMockShardedMultimapTable(t *testing.T) {
+	members := NewMockKeySpace().ShardedMultimapTable("member", "Team", "Id", 4, member{})
+
+	ids := []string{}
+	shards := map[int]bool{}
+	for i := 0; i < 20; i++ {
+		id := fmt.Sprintf("m%02d", i)
+		ids = append(ids, id)
+		shard, err := shardOf(id, 4)
+		require.NoError(t, err)
+		shards[shard] = true
+		require.NoError(t, members.Set(member{Id: id, Team: "core"}).Run())
+	}
+	require.NoError(t, members.Set(member{Id: "m99", Team: "ops"}).Run())
+	assert.Len(t, shards, 4)
+
+	listed := func(ms []member) []string {
+		ids := make([]string, len(ms))
+		for i, m := range ms {
+			ids[i] = m.Id
+		}
+		return ids
+	}
+	var ms []member
+	require.NoError(t, members.List("core", nil, 0, &ms).Run())
+	assert.Equal(t, ids, listed(ms))
+	require.NoError(t, members.List("core", "m05", 3, &ms).Run())
+	assert.Equal(t, []string{"m05", "m06", "m07"}, listed(ms))
+
+	// Pages are listed in order across the shards
+	var paged []string
+	opts := ListOptions{Order: DESC, Limit: 6}
+	for more := true; more; {
+		require.NoError(t, members.ListRange("core", opts, &ms).Run())
+		paged = append(paged, listed(ms)...)
+		opts, more = members.NextPage(opts, &ms)
+	}
+	desc := append([]string{}, ids...)
+	sort.Sort(sort.Reverse(sort.StringSlice(desc)))
+	assert.Equal(t, desc, paged)
+
+	require.NoError(t, members.Update("core", "m03", map[string]interface{}{"Email": "jim@example.com"}).Run())
+	var m member
+	require.NoError(t, members.Read("core", "m03", &m).Run())
+	assert.Equal(t, member{Id: "m03", Email: "jim@example.com", Team: "core"}, m)
+
+	require.NoError(t, members.Delete("core", "m03").Run())
+	assert.IsType(t, RowNotFoundError{}, members.Read("core", "m03", &m).Run())
+	require.NoError(t, members.DeleteAll("core").Run())
+	require.NoError(t, members.List("core", nil, 0, &ms).Run())
+	assert.Empty(t, ms)
+	require.NoError(t, members.List("ops", nil, 0, &ms).Run())
+	assert.Equal(t, []string{"m99"}, listed(ms))
+}
+
+func TestCopyMultimapPartition(t *testing.T) {
+	ctx := context.Background()
+	ks := NewMockKeySpace()
+	unsharded := ks.MultimapTable("member", "Team", "Id", member{})
+	sharded := ks.ShardedMultimapTable("member", "Team", "Id", 3, member{})
+
+	want := []member{}
+	for i := 0; i < 10; i++ {
+		m := member{Id: fmt.Sprintf("m%02d", i), Email: fmt.Sprintf("%d@example.com", i), Team: "core"}
+		want = append(want, m)
+		require.NoError(t, unsharded.Set(m).Run())
+	}
+	require.NoError(t, unsharded.Set(member{Id: "m99", Team: "ops"}).Run())
+
+	require.NoError(t, CopyMultimapPartition[member](ctx, unsharded, sharded, "core", 3))
+	var ms []member
+	require.NoError(t, sharded.List("core", nil, 0, &ms).Run())
+	assert.Equal(t, want, ms)
+	require.NoError(t, sharded.List("ops", nil, 0, &ms).Run())
+	assert.Empty(t, ms)
+
+	assert.Error(t, CopyMultimapPartition[member](ctx, unsharded, sharded, "core", 0))
+
+	// Resharding copies partitions to a table with the new number of shards, which is a table of its own
+	resharded := ks.ShardedMultimapTable("member", "Team", "Id", 5, member{})
+	assert.NotEqual(t, sharded.Name(), resharded.Name())
+	require.NoError(t, CopyMultimapPartition[member](ctx, sharded, resharded, "core", 4))
+	require.NoError(t, resharded.List("core", nil, 0, &ms).Run())
+	assert.Equal(t, want, ms)
+	var m member
+	require.NoError(t, resharded.Read("core", "m07", &m).Run())
+	assert.Equal(t, want[7], m)
+}
+
+type reading struct {
+	Sensor string
+	Seq    int32
+	Value  string
+}
+
+func TestMigrateToShardedMultimapTable(t *testing.T) {
+	ctx := context.Background()
+	ks := NewMockKeySpace()
+	unsharded := ks.MultimapTable("reading", "Sensor", "Seq", reading{})
+	sharded := ks.ShardedMultimapTable("reading", "Sensor", "Seq", 4, reading{})
+
+	sensors := []string{"a", "b", "c"}
+	for _, sensor := range sensors {
+		for seq := int32(0); seq < 25; seq++ {
+			require.NoError(t, unsharded.Set(reading{Sensor: sensor, Seq: seq, Value: fmt.Sprint(sensor, seq)}).Run())
+		}
+	}
+	for _, sensor := range sensors {
+		require.NoError(t, CopyMultimapPartition[reading](ctx, unsharded, sharded, sensor, 4))
+	}
+
+	for _, sensor := range sensors {
+		var want, got []reading
+		require.NoError(t, unsharded.List(sensor, nil, 0, &want).Run())
+		require.NoError(t, sharded.List(sensor, nil, 0, &got).Run())
+		require.Len(t, got, 25)
+		assert.Equal(t, want, got)
+
+		// Rows are read from their shards
+		var r reading
+		require.NoError(t, sharded.Read(sensor, int32(7), &r).Run())
+		assert.Equal(t, reading{Sensor: sensor, Seq: 7, Value: fmt.Sprint(sensor, 7)}, r)
+
+		// Paging through the sharded partition gives the rows of the unsharded one
+		var paged []reading
+		opts := ListOptions{Order: DESC, Limit: 6}
+		for more := true; more; {
+			var page []reading
+			require.NoError(t, sharded.ListRange(sensor, opts, &page).Run())
+			paged = append(paged, page...)
+			opts, more = sharded.NextPage(opts, &page)
+		}
+		require.Len(t, paged, len(want))
+		for i := range paged {
+			assert.Equal(t, want[len(want)-1-i], paged[i])
+		}
+	}
+
+	// Copying again leaves the sharded table as it was
+	require.NoError(t, CopyMultimapPartition[reading](ctx, unsharded, sharded, "a", 10))
+	var got []reading
+	require.NoError(t, sharded.List("a", nil, 0, &got).Run())
+	assert.Len(t, got, 25)
+}
@@ -196,6 +196,7 @@ type InsertStatement struct {
 	ttl                  time.Duration          // ttl of the row
 	keys                 Keys                   // partition / clustering keys for table
 	allowClusterSentinel bool                   // whether we should enable our clustering sentinel
+	ifNotExists          bool                   // whether the row is only inserted if it doesn't exist
 }
 
 // NewInsertStatement adds the ability to craft a new InsertStatement
@@ -254,6 +255,10 @@ func (s InsertStatement) QueryAndValues() (string, []interface{}) {
 	query = append(query, "("+strings.Join(fieldNames, ", ")+")")
 	query = append(query, "VALUES ("+strings.Join(placeholders, ", ")+")")
 
+	if s.IfNotExists() {
+		query = append(query, "IF NOT EXISTS")
+	}
+
 	// Determine if we need to set a TTL
 	if s.TTL() > time.Duration(0) {
 		query = append(query, "USING TTL ?")
@@ -310,6 +315,19 @@ func (s InsertStatement) WithClusteringSentinel(enabled bool) InsertStatement {
 	return s
 }
 
+// IfNotExists returns whether the row is only inserted if it doesn't exist
+// already (IF NOT EXISTS), which makes this a conditional statement
+func (s InsertStatement) IfNotExists() bool {
+	return s.ifNotExists
+}
+
+// WithIfNotExists allows toggling of whether the row is only inserted if it
+// doesn't exist already
+func (s InsertStatement) WithIfNotExists(enabled bool) InsertStatement {
+	s.ifNotExists = enabled
+	return s
+}
+
 // UpdateStatement represents an UPDATE query to update some data in C*
 // It satisfies the Statement interface
 type UpdateStatement struct {
@@ -320,6 +338,8 @@ type UpdateStatement struct {
 	ttl                  time.Duration          // ttl of the row
 	keys                 Keys                   // partition / clustering keys for table
 	allowClusterSentinel bool                   // whether we should enable our clustering sentinel
+	ifExists             bool                   // whether the row is only updated if it exists
+	conditions           []Relation             // conditions the row must meet to be updated
 }
 
 // NewUpdateStatement adds the ability to craft a new UpdateStatement
@@ -382,6 +402,12 @@ func (s UpdateStatement) QueryAndValues() (string, []interface{}) {
 		query = append(query, "WHERE", whereCQL)
 		values = append(values, whereValues...)
 	}
+
+	ifCQL, ifValues := generateIfCQL(s.IfExists(), s.Conditions())
+	if ifCQL != "" {
+		query = append(query, "IF", ifCQL)
+		values = append(values, ifValues...)
+	}
 	return strings.Join(query, " "), values
 }
 
@@ -438,6 +464,30 @@ func (s UpdateStatement) WithClusteringSentinel(enabled bool) UpdateStatement {
 	return s
 }
 
+// IfExists returns whether the row is only updated if it exists (IF EXISTS),
+// which makes this a conditional statement
+func (s UpdateStatement) IfExists() bool {
+	return s.ifExists
+}
+
+// WithIfExists allows toggling of whether the row is only updated if it exists
+func (s UpdateStatement) WithIfExists(enabled bool) UpdateStatement {
+	s.ifExists = enabled
+	return s
+}
+
+// Conditions provides the IF clause Relation items the row must meet to be
+// updated, which make this a conditional statement
+func (s UpdateStatement) Conditions() []Relation {
+	return s.conditions
+}
+
+// WithConditions sets the conditions (IF clauses) for this statement
+func (s UpdateStatement) WithConditions(rel []Relation) UpdateStatement {
+	s.conditions = rel
+	return s
+}
+
 // DeleteStatement represents a DELETE query to delete some data in C*
 // It satisfies the Statement interface
 type DeleteStatement struct {
@@ -446,6 +496,8 @@ type DeleteStatement struct {
 	where                []Relation // where filter clauses
 	keys                 Keys       // partition / clustering keys for table
 	allowClusterSentinel bool       // whether we should enable our clustering sentinel
+	ifExists             bool       // whether the row is only deleted if it exists
+	conditions           []Relation // conditions the row must meet to be deleted
 }
 
 // NewDeleteStatement adds the ability to craft a new DeleteStatement
@@ -490,6 +542,12 @@ func (s DeleteStatement) QueryAndValues() (string, []interface{}) {
 	if whereCQL != "" {
 		query += " WHERE " + whereCQL
 	}
+
+	ifCQL, ifValues := generateIfCQL(s.IfExists(), s.Conditions())
+	if ifCQL != "" {
+		query += " IF " + ifCQL
+		whereValues = append(whereValues, ifValues...)
+	}
 	return query, whereValues
 }
 
@@ -521,6 +579,30 @@ func (s DeleteStatement) WithClusteringSentinel(enabled bool) DeleteStatement {
 	return s
 }
 
+// IfExists returns whether the row is only deleted if it exists (IF EXISTS),
+// which makes this a conditional statement
+func (s DeleteStatement) IfExists() bool {
+	return s.ifExists
+}
+
+// WithIfExists allows toggling of whether the row is only deleted if it exists
+func (s DeleteStatement) WithIfExists(enabled bool) DeleteStatement {
+	s.ifExists = enabled
+	return s
+}
+
+// Conditions provides the IF clause Relation items the row must meet to be
+// deleted, which make this a conditional statement
+func (s DeleteStatement) Conditions() []Relation {
+	return s.conditions
+}
+
+// WithConditions sets the conditions (IF clauses) for this statement
+func (s DeleteStatement) WithConditions(rel []Relation) DeleteStatement {
+	s.conditions = rel
+	return s
+}
+
 // cqlStatement represents a statement that executes raw CQL
 type cqlStatement struct {
 	query  string
@@ -573,6 +655,96 @@ func generateWhereCQL(rs []Relation, keys Keys, clusteringSentinelsEnabled bool)
 	return strings.Join(clauses, " AND "), values
 }
 
+// generateIfCQL generates the CQL for the IF clause of a conditional
+// statement. An expected output may be something like:
+//   - "EXISTS", {}
+//   - "foo = ? AND bar > ?", {1, 2}
+func generateIfCQL(ifExists bool, conditions []Relation) (string, []interface{}) {
+	if ifExists {
+		return "EXISTS", nil
+	}
+	return generateWhereCQL(conditions, Keys{}, false)
+}
+
+// isConditional returns whether stmt is a conditional (lightweight transaction) statement, whose result says whether
+// it was applied
+func isConditional(stmt Statement) bool {
+	switch s := stmt.(type) {
+	case InsertStatement:
+		return s.IfNotExists()
+	case UpdateStatement:
+		return s.IfExists() || len(s.Conditions()) > 0
+	case DeleteStatement:
+		return s.IfExists() || len(s.Conditions()) > 0
+	}
+	return false
+}
+
+// samePartition returns whether the write statements all write to the same partition of the same table
+func samePartition(stmts []Statement) bool {
+	var first string
+	for i, stmt := range stmts {
+		partition, ok := statementPartition(stmt)
+		if !ok {
+			return false
+		}
+		if i == 0 {
+			first = partition
+		} else if partition != first {
+			return false
+		}
+	}
+	return true
+}
+
+// statementPartition returns a key identifying the table and partition a write statement writes to, or false if it
+// isn't confined to a single partition
+func statementPartition(stmt Statement) (string, bool) {
+	var keyspace, table string
+	var keys Keys
+	var value func(field string) (interface{}, bool)
+	switch s := stmt.(type) {
+	case InsertStatement:
+		keyspace, table, keys = s.keyspace, s.table, s.keys
+		value = func(field string) (interface{}, bool) {
+			for k, v := range s.fieldMap {
+				if strings.EqualFold(k, field) {
+					return v, true
+				}
+			}
+			return nil, false
+		}
+	case UpdateStatement:
+		keyspace, table, keys = s.keyspace, s.table, s.keys
+		value = func(field string) (interface{}, bool) { return equalityTerm(s.where, field) }
+	case DeleteStatement:
+		keyspace, table, keys = s.keyspace, s.table, s.keys
+		value = func(field string) (interface{}, bool) { return equalityTerm(s.where, field) }
+	default:
+		return "", false
+	}
+
+	values := make([]interface{}, len(keys.PartitionKeys))
+	for i, field := range keys.PartitionKeys {
+		v, ok := value(field)
+		if !ok {
+			return "", false
+		}
+		values[i] = v
+	}
+	return fmt.Sprintf("%s.%s%#v", keyspace, table, values), true
+}
+
+// equalityTerm returns the term field is equal to among the relations, if there is one
+func equalityTerm(relations []Relation, field string) (interface{}, bool) {
+	for _, rel := range relations {
+		if rel.Comparator() == CmpEquality && strings.EqualFold(rel.Field(), field) {
+			return rel.Terms()[0], true
+		}
+	}
+	return nil, false
+}
+
 func generateRelationCQL(rel Relation, keys Keys, clusteringSentinelsEnabled bool) (string, []interface{}) {
 	field := strings.ToLower(rel.Field())
 	switch rel.Comparator() {
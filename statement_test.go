@@ -54,6 +54,11 @@ func TestInsertStatement(t *testing.T) {
 	stmt = stmt.WithTTL(1 * time.Hour)
 	assert.Equal(t, "INSERT INTO ks1.tbl1 (a, c) VALUES (?, ?) USING TTL ?", stmt.Query())
 	assert.Equal(t, []interface{}{"b", "d", 3600}, stmt.Values())
+
+	stmt = stmt.WithIfNotExists(true)
+	assert.True(t, isConditional(stmt))
+	assert.Equal(t, "INSERT INTO ks1.tbl1 (a, c) VALUES (?, ?) IF NOT EXISTS USING TTL ?", stmt.Query())
+	assert.Equal(t, []interface{}{"b", "d", 3600}, stmt.Values())
 }
 
 func TestUpdateStatement(t *testing.T) {
@@ -89,6 +94,19 @@ func TestUpdateStatement(t *testing.T) {
 	stmt = stmt.WithTTL(1 * time.Hour)
 	assert.Equal(t, "UPDATE ks1.tbl1 USING TTL ? SET a = ?, c = ? WHERE foo = ? AND baz IN ?", stmt.Query())
 	assert.Equal(t, []interface{}{3600, "b", "d", "bar", []interface{}{"a", "b", "c"}}, stmt.Values())
+	assert.False(t, isConditional(stmt))
+
+	stmt, err = NewUpdateStatement("ks1", "tbl1", map[string]interface{}{"a": "b"}, []Relation{Eq("foo", "bar")}, keys)
+	assert.NoError(t, err)
+	stmt = stmt.WithIfExists(true)
+	assert.True(t, isConditional(stmt))
+	assert.Equal(t, "UPDATE ks1.tbl1 SET a = ? WHERE foo = ? IF EXISTS", stmt.Query())
+	assert.Equal(t, []interface{}{"b", "bar"}, stmt.Values())
+
+	stmt = stmt.WithIfExists(false).WithConditions([]Relation{Eq("a", "c"), LT("Version", 2)})
+	assert.True(t, isConditional(stmt))
+	assert.Equal(t, "UPDATE ks1.tbl1 SET a = ? WHERE foo = ? IF a = ? AND version < ?", stmt.Query())
+	assert.Equal(t, []interface{}{"b", "bar", "c", 2}, stmt.Values())
 }
 
 func TestDeleteStatement(t *testing.T) {
@@ -107,6 +125,18 @@ func TestDeleteStatement(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, "DELETE FROM ks1.tbl1 WHERE foo = ? AND baz IN ?", stmt.Query())
 	assert.Equal(t, []interface{}{"bar", []interface{}{"a", "b", "c"}}, stmt.Values())
+	assert.False(t, isConditional(stmt))
+
+	stmt, err = NewDeleteStatement("ks1", "tbl1", []Relation{Eq("foo", "bar")}, keys)
+	assert.NoError(t, err)
+	stmt = stmt.WithIfExists(true)
+	assert.True(t, isConditional(stmt))
+	assert.Equal(t, "DELETE FROM ks1.tbl1 WHERE foo = ? IF EXISTS", stmt.Query())
+	assert.Equal(t, []interface{}{"bar"}, stmt.Values())
+
+	stmt = stmt.WithIfExists(false).WithConditions([]Relation{Eq("owner", "me")})
+	assert.Equal(t, "DELETE FROM ks1.tbl1 WHERE foo = ? IF owner = ?", stmt.Query())
+	assert.Equal(t, []interface{}{"bar", "me"}, stmt.Values())
 }
 
 func TestStatementsWithSentinel(t *testing.T) {
@@ -147,6 +147,15 @@ func (t t) Set(i interface{}) Op {
 	}, updateOpType, updFields)
 }
 
+func (t t) SetIfNotExists(i interface{}) Op {
+	m, ok := toMap(i)
+	if !ok {
+		panic("SetIfNotExists: Incompatible type")
+	}
+	// Only an INSERT can be made conditional on the row not existing, so unlike Set this never becomes an UPDATE
+	return newConditionalWriteOp(t.keySpace.qe, filter{t: t}, insertOpType, m, nil)
+}
+
 func (t t) Create() error {
 	if stmt, err := t.CreateStatement(); err != nil {
 		return err
@@ -185,6 +194,7 @@ func (t t) CreateStatement() (Statement, error) {
 		t.info.keys.Compound,
 		t.options.CompactStorage,
 		t.options.Compressor,
+		t.options.TableOptions,
 	)
 }
 
@@ -199,9 +209,22 @@ func (t t) CreateIfNotExistStatement() (Statement, error) {
 		t.info.keys.Compound,
 		t.options.CompactStorage,
 		t.options.Compressor,
+		t.options.TableOptions,
 	)
 }
 
+func (t t) AlterOptions() error {
+	if stmt, err := t.AlterOptionsStatement(); err != nil {
+		return err
+	} else {
+		return t.keySpace.qe.Execute(stmt)
+	}
+}
+
+func (t t) AlterOptionsStatement() (Statement, error) {
+	return alterTableOptions(t.keySpace.name, t.Name(), t.options.TableOptions)
+}
+
 func (t t) Name() string {
 	if len(t.options.TableName) > 0 {
 		return t.options.TableName
@@ -216,3 +239,13 @@ func (table t) WithOptions(o Options) Table {
 		options:  table.options.Merge(o),
 	}
 }
+
+// tableKeys returns the keys the table was defined with
+func (table t) tableKeys() Keys {
+	return table.info.keys
+}
+
+// tableFields returns the fields of the table's rows, and a value of the type of each
+func (table t) tableFields() ([]string, []interface{}) {
+	return table.info.fields, table.info.fieldValues
+}
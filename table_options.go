@@ -0,0 +1,200 @@
+package gocassa
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TableOptions holds the properties of a table which can be set when it is created, or changed afterwards with
+// Table.AlterOptions. Fields which aren't set are left at Cassandra's defaults when creating a table, and unchanged
+// when altering one.
+type TableOptions struct {
+	// Compaction configures the table's compaction strategy
+	Compaction *CompactionOptions
+	// Compression configures how the table's SSTables are compressed
+	Compression *CompressionOptions
+	// DefaultTTL is applied to writes which don't set a TTL of their own. It is truncated to second precision
+	DefaultTTL time.Duration
+	// GCGrace is how long tombstones are kept before they may be purged. As zero is a valid value, it is only set if
+	// not nil. It is truncated to second precision
+	GCGrace *time.Duration
+	// Caching configures which of the table's keys and rows are cached
+	Caching *CachingOptions
+	// BloomFilterFPChance is the false positive probability targeted by the table's bloom filters
+	BloomFilterFPChance float64
+	// Comment describes the table
+	Comment string
+}
+
+// CompactionOptions configures a table's compaction strategy.
+type CompactionOptions struct {
+	// Class is the compaction strategy, eg. "LeveledCompactionStrategy"
+	Class string
+	// Options holds any sub-options of the strategy
+	Options map[string]string
+}
+
+// SizeTieredCompaction returns the options for Cassandra's default size tiered compaction strategy.
+func SizeTieredCompaction() *CompactionOptions {
+	return &CompactionOptions{Class: "SizeTieredCompactionStrategy"}
+}
+
+// LeveledCompaction returns the options for the leveled compaction strategy. If sstableSizeMB is zero, the
+// strategy's default SSTable size is used.
+func LeveledCompaction(sstableSizeMB int) *CompactionOptions {
+	opts := &CompactionOptions{Class: "LeveledCompactionStrategy"}
+	if sstableSizeMB > 0 {
+		opts.Options = map[string]string{"sstable_size_in_mb": strconv.Itoa(sstableSizeMB)}
+	}
+	return opts
+}
+
+// TimeWindowCompaction returns the options for the time window compaction strategy, which suits time series data
+// written with a TTL. window is expressed in the largest of days, hours or minutes which divides it exactly.
+func TimeWindowCompaction(window time.Duration) *CompactionOptions {
+	unit, size := "MINUTES", int64(window/time.Minute)
+	switch {
+	case window%(24*time.Hour) == 0:
+		unit, size = "DAYS", int64(window/(24*time.Hour))
+	case window%time.Hour == 0:
+		unit, size = "HOURS", int64(window/time.Hour)
+	}
+	return &CompactionOptions{
+		Class: "TimeWindowCompactionStrategy",
+		Options: map[string]string{
+			"compaction_window_unit": unit,
+			"compaction_window_size": strconv.FormatInt(size, 10),
+		},
+	}
+}
+
+// CompressionOptions configures how a table's SSTables are compressed.
+type CompressionOptions struct {
+	// Class is the compressor to use, eg. "LZ4Compressor" or "ZstdCompressor"
+	Class string
+	// ChunkLengthKB is the size of the blocks data is compressed in, in kilobytes
+	ChunkLengthKB int
+	// Disabled turns compression off. Class and ChunkLengthKB are ignored if it is set
+	Disabled bool
+}
+
+// empty returns whether o sets nothing, in which case the table's compression is left alone, as Cassandra rejects an
+// empty compression map
+func (o *CompressionOptions) empty() bool {
+	return o == nil || (o.Class == "" && o.ChunkLengthKB <= 0 && !o.Disabled)
+}
+
+// CachingOptions configures which of a table's keys and rows are cached.
+type CachingOptions struct {
+	// Keys is either "ALL" or "NONE"
+	Keys string
+	// RowsPerPartition is "ALL", "NONE" or the number of rows of each partition to cache
+	RowsPerPartition string
+}
+
+// validate returns an error if o can't be rendered as table properties Cassandra accepts
+func (o TableOptions) validate() error {
+	if o.Compaction != nil && o.Compaction.Class == "" && len(o.Compaction.Options) > 0 {
+		return fmt.Errorf("compaction options %v need the class of the strategy they configure", o.Compaction.Options)
+	}
+	return nil
+}
+
+// properties returns the table properties set by o, each in the form "name = value", ordered by name. Options which
+// set nothing are left out, as Cassandra rejects empty maps for them.
+func (o TableOptions) properties() []string {
+	props := []string{}
+	if o.BloomFilterFPChance > 0 {
+		props = append(props, fmt.Sprintf("bloom_filter_fp_chance = %s", strconv.FormatFloat(o.BloomFilterFPChance, 'f', -1, 64)))
+	}
+	if o.Caching != nil {
+		caching := map[string]string{}
+		if o.Caching.Keys != "" {
+			caching["keys"] = o.Caching.Keys
+		}
+		if o.Caching.RowsPerPartition != "" {
+			caching["rows_per_partition"] = o.Caching.RowsPerPartition
+		}
+		if len(caching) > 0 {
+			props = append(props, fmt.Sprintf("caching = %s", cqlMapLiteral("", caching)))
+		}
+	}
+	if o.Comment != "" {
+		props = append(props, fmt.Sprintf("comment = %s", cqlStringLiteral(o.Comment)))
+	}
+	if o.Compaction != nil && o.Compaction.Class != "" {
+		props = append(props, fmt.Sprintf("compaction = %s", cqlMapLiteral(o.Compaction.Class, o.Compaction.Options)))
+	}
+	if !o.Compression.empty() {
+		compression := map[string]string{}
+		class := o.Compression.Class
+		if o.Compression.Disabled {
+			class = ""
+			compression["enabled"] = "false"
+		} else if o.Compression.ChunkLengthKB > 0 {
+			compression["chunk_length_in_kb"] = strconv.Itoa(o.Compression.ChunkLengthKB)
+		}
+		props = append(props, fmt.Sprintf("compression = %s", cqlMapLiteral(class, compression)))
+	}
+	if o.DefaultTTL > 0 {
+		props = append(props, fmt.Sprintf("default_time_to_live = %d", int64(o.DefaultTTL/time.Second)))
+	}
+	if o.GCGrace != nil {
+		props = append(props, fmt.Sprintf("gc_grace_seconds = %d", int64(*o.GCGrace/time.Second)))
+	}
+	return props
+}
+
+// cqlMapLiteral renders a map of table property options, with the class (if any) first and the rest ordered by key
+func cqlMapLiteral(class string, m map[string]string) string {
+	entries := []string{}
+	if class != "" {
+		entries = append(entries, fmt.Sprintf("'class': %s", cqlStringLiteral(class)))
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		entries = append(entries, fmt.Sprintf("%s: %s", cqlStringLiteral(k), cqlStringLiteral(m[k])))
+	}
+	return "{" + strings.Join(entries, ", ") + "}"
+}
+
+func cqlStringLiteral(s string) string {
+	return "'" + strings.Replace(s, "'", "''", -1) + "'"
+}
+
+// alterTableOptions returns the statement which changes the properties of an existing table to those set in opts
+func alterTableOptions(keySpace, cf string, opts *TableOptions) (Statement, error) {
+	if opts == nil {
+		return nil, fmt.Errorf("no table options to alter %s.%s with", keySpace, cf)
+	}
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+	props := opts.properties()
+	if len(props) == 0 {
+		return nil, fmt.Errorf("no table options to alter %s.%s with", keySpace, cf)
+	}
+	lines := []string{fmt.Sprintf("ALTER TABLE %v.%v", keySpace, cf)}
+	lines = append(lines, withClauses(props)...)
+	return cqlStatement{query: strings.Join(lines, "\n")}, nil
+}
+
+// withClauses joins table properties into the lines of a WITH ... AND ... clause
+func withClauses(props []string) []string {
+	lines := make([]string, len(props))
+	for i, prop := range props {
+		if i == 0 {
+			lines[i] = "WITH " + prop
+		} else {
+			lines[i] = "AND " + prop
+		}
+	}
+	return lines
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/gocql/gocql"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func createIf(cs TableChanger, tes *testing.T) {
@@ -195,6 +196,94 @@ func TestCreateStatement(t *testing.T) {
 	}
 }
 
+func TestCreateStatementTableOptions(t *testing.T) {
+	gcGrace := 6 * time.Hour
+	cs := NewConnection(funcQE{}).KeySpace("ks").Table("table_options", Customer{}, Keys{
+		PartitionKeys:     []string{"Id"},
+		ClusteringColumns: []string{"Name"},
+	}).WithOptions(Options{
+		ClusteringOrder: []ClusteringOrderColumn{{Column: "Name", Direction: DESC}},
+		Compressor:      "DeflateCompressor",
+		TableOptions: &TableOptions{
+			Compaction:          TimeWindowCompaction(24 * time.Hour),
+			Compression:         &CompressionOptions{Class: "LZ4Compressor", ChunkLengthKB: 16},
+			DefaultTTL:          30 * 24 * time.Hour,
+			GCGrace:             &gcGrace,
+			Caching:             &CachingOptions{Keys: "ALL", RowsPerPartition: "10"},
+			BloomFilterFPChance: 0.01,
+			Comment:             "customers' names",
+		},
+	})
+	stmt, err := cs.CreateStatement()
+	assert.NoError(t, err)
+	assert.Equal(t, strings.Join([]string{
+		"CREATE TABLE ks.table_options__Id__Name (",
+		"    id varchar,",
+		"    name varchar,",
+		"    PRIMARY KEY ((id), name)",
+		")",
+		"WITH CLUSTERING ORDER BY (name DESC)",
+		"AND bloom_filter_fp_chance = 0.01",
+		"AND caching = {'keys': 'ALL', 'rows_per_partition': '10'}",
+		"AND comment = 'customers'' names'",
+		"AND compaction = {'class': 'TimeWindowCompactionStrategy', 'compaction_window_size': '1', 'compaction_window_unit': 'DAYS'}",
+		"AND compression = {'class': 'LZ4Compressor', 'chunk_length_in_kb': '16'}",
+		"AND default_time_to_live = 2592000",
+		"AND gc_grace_seconds = 21600",
+		";",
+	}, "\n"), stmt.Query())
+
+	// Without any table options, the existing clauses are unchanged
+	stmt, err = cs.WithOptions(Options{TableOptions: &TableOptions{}}).CreateStatement()
+	assert.NoError(t, err)
+	assert.True(t, strings.HasSuffix(stmt.Query(), "WITH CLUSTERING ORDER BY (name DESC)\nAND compression = {'sstable_compression': 'DeflateCompressor'}\n;"), stmt.Query())
+
+	// Options which set nothing are left out rather than rendered as empty maps, which Cassandra rejects
+	stmt, err = cs.WithOptions(Options{TableOptions: &TableOptions{
+		Caching:     &CachingOptions{},
+		Compaction:  &CompactionOptions{},
+		Compression: &CompressionOptions{},
+	}}).CreateStatement()
+	assert.NoError(t, err)
+	assert.True(t, strings.HasSuffix(stmt.Query(), "WITH CLUSTERING ORDER BY (name DESC)\nAND compression = {'sstable_compression': 'DeflateCompressor'}\n;"), stmt.Query())
+	_, err = alterTableOptions("ks", "customers", &TableOptions{Caching: &CachingOptions{}, Compression: &CompressionOptions{}})
+	assert.Error(t, err)
+
+	// Compaction options without the class of their strategy would be rendered as a map Cassandra rejects
+	noClass := &TableOptions{Compaction: &CompactionOptions{Options: map[string]string{"sstable_size_in_mb": "160"}}}
+	_, err = cs.WithOptions(Options{TableOptions: noClass}).CreateStatement()
+	assert.Error(t, err)
+	_, err = cs.WithOptions(Options{TableOptions: noClass}).CreateIfNotExistStatement()
+	assert.Error(t, err)
+	_, err = alterTableOptions("ks", "customers", noClass)
+	assert.Error(t, err)
+}
+
+func TestAlterOptions(t *testing.T) {
+	var stmts []Statement
+	qe := funcQE{fn: func(opts Options, stmt Statement) error {
+		stmts = append(stmts, stmt)
+		return nil
+	}}
+	tbl := NewConnection(qe).KeySpace("ks").MapTable("customers", "Id", Customer{})
+
+	// There is nothing to alter until table options are set
+	_, err := tbl.AlterOptionsStatement()
+	assert.Error(t, err)
+
+	tbl = tbl.WithOptions(Options{TableOptions: &TableOptions{
+		Compaction:  LeveledCompaction(160),
+		Compression: &CompressionOptions{Disabled: true},
+	}})
+	assert.NoError(t, tbl.AlterOptions())
+	require.Len(t, stmts, 1)
+	assert.Equal(t, strings.Join([]string{
+		"ALTER TABLE ks.customers_map_Id",
+		"WITH compaction = {'class': 'LeveledCompactionStrategy', 'sstable_size_in_mb': '160'}",
+		"AND compression = {'enabled': 'false'}",
+	}, "\n"), stmts[0].Query())
+}
+
 func TestAllowFiltering(t *testing.T) {
 	name := "allow_filtering"
 	cs := ns.Table(name, Customer2{}, Keys{
@@ -7,10 +7,11 @@ import (
 const bucketFieldName = "bucket"
 
 type timeSeriesT struct {
-	t          Table
-	timeField  string
-	idField    string
-	bucketSize time.Duration
+	t         Table
+	timeField string
+	idField   string
+	buckets   BucketStrategy
+	options   Options
 }
 
 func (o *timeSeriesT) Table() Table                        { return o.t }
@@ -22,6 +23,10 @@ func (o *timeSeriesT) CreateStatement() (Statement, error) { return o.Table().Cr
 func (o *timeSeriesT) CreateIfNotExistStatement() (Statement, error) {
 	return o.Table().CreateIfNotExistStatement()
 }
+func (o *timeSeriesT) AlterOptions() error { return o.Table().AlterOptions() }
+func (o *timeSeriesT) AlterOptionsStatement() (Statement, error) {
+	return o.Table().AlterOptionsStatement()
+}
 
 func (o *timeSeriesT) Set(v interface{}) Op {
 	m, ok := toMap(v)
@@ -31,13 +36,13 @@ func (o *timeSeriesT) Set(v interface{}) Op {
 	if tim, ok := m[o.timeField].(time.Time); !ok {
 		panic("timeField is not actually a time.Time")
 	} else {
-		m[bucketFieldName] = bucket(tim, o.bucketSize)
+		m[bucketFieldName] = o.buckets.Bucket(tim)
 	}
 	return o.Table().Set(m)
 }
 
 func (o *timeSeriesT) Update(timeStamp time.Time, id interface{}, m map[string]interface{}) Op {
-	bucket := bucket(timeStamp, o.bucketSize)
+	bucket := o.buckets.Bucket(timeStamp)
 	return o.Table().
 		Where(Eq(bucketFieldName, bucket),
 			Eq(o.timeField, timeStamp),
@@ -46,7 +51,7 @@ func (o *timeSeriesT) Update(timeStamp time.Time, id interface{}, m map[string]i
 }
 
 func (o *timeSeriesT) Delete(timeStamp time.Time, id interface{}) Op {
-	bucket := bucket(timeStamp, o.bucketSize)
+	bucket := o.buckets.Bucket(timeStamp)
 	return o.Table().
 		Where(Eq(bucketFieldName, bucket),
 			Eq(o.timeField, timeStamp),
@@ -55,7 +60,7 @@ func (o *timeSeriesT) Delete(timeStamp time.Time, id interface{}) Op {
 }
 
 func (o *timeSeriesT) Read(timeStamp time.Time, id, pointer interface{}) Op {
-	bucket := bucket(timeStamp, o.bucketSize)
+	bucket := o.buckets.Bucket(timeStamp)
 	return o.Table().
 		Where(Eq(bucketFieldName, bucket),
 			Eq(o.timeField, timeStamp),
@@ -68,26 +73,66 @@ func (o *timeSeriesT) List(startTime time.Time, endTime time.Time, pointerToASli
 	for bucket := o.Buckets(startTime); bucket.Bucket().Before(endTime); bucket = bucket.Next() {
 		buckets = append(buckets, bucket.Bucket())
 	}
-	return o.Table().
+	read := o.Table().
 		Where(In(bucketFieldName, buckets...),
 			GTE(o.timeField, startTime),
 			LTE(o.timeField, endTime)).
 		Read(pointerToASlice)
+	return mergedSeriesRead(read, o.options, o.timeField, timeSeriesOrder(o.timeField, []string{o.idField}), pointerToASlice)
+}
+
+func (o *timeSeriesT) ListWithLimit(startTime, endTime time.Time, opts SeriesListOptions, pointerToASlice interface{}) Op {
+	return seriesWalk{
+		buckets:   o.Buckets,
+		timeField: o.timeField,
+		idFields:  []string{o.idField},
+		start:     startTime,
+		end:       endTime,
+	}.list(opts, pointerToASlice)
+}
+
+func (o *timeSeriesT) NextPage(opts SeriesListOptions, pointerToASlice interface{}) (SeriesListOptions, bool) {
+	return nextSeriesPage(opts, pointerToASlice, timeSeriesOrder(o.timeField, []string{o.idField}))
+}
+
+func (o *timeSeriesT) ListSince(timeStamp time.Time, id interface{}, window time.Duration, pointerToASlice interface{}) Op {
+	return seriesReadSince(o.Table(), o.options, window, func(now time.Time) Op {
+		relations, err := seriesWindow(o.Buckets, o.timeField, []string{o.idField}, SeriesCursor{Time: timeStamp, ID: id}, window, now, ASC)
+		if err != nil {
+			return errOp{err: err}
+		}
+		read := o.Table().
+			Where(relations...).
+			Read(pointerToASlice)
+		return mergedSeriesRead(read, o.options, o.timeField, timeSeriesOrder(o.timeField, []string{o.idField}), pointerToASlice)
+	})
+}
+
+func (o *timeSeriesT) ListBefore(timeStamp time.Time, id interface{}, window time.Duration, pointerToASlice interface{}) Op {
+	relations, err := seriesWindow(o.Buckets, o.timeField, []string{o.idField}, SeriesCursor{Time: timeStamp, ID: id}, window, time.Time{}, DESC)
+	if err != nil {
+		return errOp{err: err}
+	}
+	read := o.Table().
+		Where(relations...).
+		Read(pointerToASlice)
+	return mergedSeriesRead(read, o.options, o.timeField, timeSeriesOrder(o.timeField, []string{o.idField}), pointerToASlice)
 }
 
 func (o *timeSeriesT) Buckets(start time.Time) Buckets {
 	return bucketIter{
 		v:         start,
-		step:      o.bucketSize,
+		strategy:  o.buckets,
 		field:     bucketFieldName,
 		invariant: o.Table().Where()}
 }
 
 func (o *timeSeriesT) WithOptions(opt Options) TimeSeriesTable {
 	return &timeSeriesT{
-		t:          o.Table().WithOptions(opt),
-		timeField:  o.timeField,
-		idField:    o.idField,
-		bucketSize: o.bucketSize,
+		t:         o.Table().WithOptions(opt),
+		timeField: o.timeField,
+		idField:   o.idField,
+		buckets:   o.buckets,
+		options:   o.options.Merge(opt),
 	}
 }
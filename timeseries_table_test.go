@@ -2,6 +2,7 @@ package gocassa
 
 import (
 	"fmt"
+	"reflect"
 	"testing"
 	"time"
 
@@ -83,3 +84,162 @@ func TestOptions(t *testing.T) {
 	assert.Len(t, res, 6)
 	assert.Len(t, res1, 6)
 }
+
+func TestTimeSeriesListWithLimitStatements(t *testing.T) {
+	start := parse("2006 Jan 2 15:00:00")
+	var queries []string
+	qe := funcQE{query: func(stmt Statement, scanner Scanner) error {
+		queries = append(queries, stmt.Query())
+		// Every bucket holds a single trip
+		bucket := stmt.Values()[0].(time.Time).UTC()
+		rows := []map[string]interface{}{{"id": bucket.Format(time.RFC3339), "time": bucket}}
+		_, err := scanner.ScanIter(newMockIterator(rows, stmt.(SelectStatement).fields))
+		return err
+	}}
+	tbl := NewConnection(qe).KeySpace("ks").TimeSeriesTable("trips", "Time", "Id", time.Hour, Trip{})
+
+	// The latest buckets are queried one at a time until the limit is met
+	opts := SeriesListOptions{Order: DESC, Limit: 2}
+	trips := []Trip{}
+	require.NoError(t, tbl.ListWithLimit(start, start.Add(24*time.Hour), opts, &trips).Run())
+	require.Equal(t, []Trip{
+		{Id: "2006-01-03T15:00:00Z", Time: start.Add(24 * time.Hour)},
+		{Id: "2006-01-03T14:00:00Z", Time: start.Add(23 * time.Hour)},
+	}, trips)
+	require.Equal(t, []string{
+		"SELECT id, time, bucket FROM ks.trips_timeSeries_Time_Id_1h0m0s WHERE bucket = ? AND time >= ? AND time <= ? ORDER BY Time DESC, Id DESC LIMIT ?",
+		"SELECT id, time, bucket FROM ks.trips_timeSeries_Time_Id_1h0m0s WHERE bucket = ? AND time >= ? AND time <= ? ORDER BY Time DESC, Id DESC LIMIT ?",
+	}, queries)
+
+	// Resuming from the cursor bounds its bucket by the clustering columns
+	opts, more := tbl.NextPage(opts, &trips)
+	require.True(t, more)
+	queries = nil
+	require.NoError(t, tbl.ListWithLimit(start, start.Add(24*time.Hour), opts, &trips).Run())
+	require.Equal(t, "SELECT id, time, bucket FROM ks.trips_timeSeries_Time_Id_1h0m0s WHERE bucket = ? AND (time, id) < (?,?) AND (time) >= (?) ORDER BY Time DESC, Id DESC LIMIT ?", queries[0])
+}
+
+func TestTimeSeriesListSinceStatements(t *testing.T) {
+	var queries []string
+	qe := funcQE{query: func(stmt Statement, scanner Scanner) error {
+		queries = append(queries, stmt.Query())
+		_, err := scanner.ScanIter(newMockIterator(nil, stmt.(SelectStatement).fields))
+		return err
+	}}
+	tbl := NewConnection(qe).KeySpace("ks").TimeSeriesTable("trips", "Time", "Id", time.Hour, Trip{})
+
+	trips := []Trip{}
+	at := parse("2006 Jan 2 15:30:00")
+	require.NoError(t, tbl.ListSince(at, "trip-1", time.Hour, &trips).Run())
+	require.NoError(t, tbl.ListBefore(at, "trip-1", time.Hour, &trips).Run())
+	require.Equal(t, []string{
+		"SELECT id, time, bucket FROM ks.trips_timeSeries_Time_Id_1h0m0s WHERE bucket IN ? AND (time, id) > (?,?) AND (time) < (?)",
+		"SELECT id, time, bucket FROM ks.trips_timeSeries_Time_Id_1h0m0s WHERE bucket IN ? AND (time) >= (?) AND (time, id) < (?,?)",
+	}, queries)
+}
+
+func TestTimeSeriesListMergesBuckets(t *testing.T) {
+	start := parse("2006 Jan 2 15:00:00")
+	at := func(d time.Duration) time.Time { return start.Add(d) }
+	// Cassandra returns each partition's rows in order, but the partitions in token order
+	partitions := []map[string]interface{}{
+		{"id": "c", "time": at(2 * time.Hour)}, {"id": "d", "time": at(2*time.Hour + time.Minute)},
+		{"id": "a", "time": at(0)}, {"id": "b", "time": at(time.Minute)},
+		{"id": "b2", "time": at(time.Hour)},
+	}
+	qe := funcQE{query: func(stmt Statement, scanner Scanner) error {
+		_, err := scanner.ScanIter(newMockIterator(partitions, stmt.(SelectStatement).fields))
+		return err
+	}}
+	tbl := NewConnection(qe).KeySpace("ks").TimeSeriesTable("trips", "Time", "Id", time.Hour, Trip{})
+	mockTbl := NewMockKeySpace().TimeSeriesTable("trips", "Time", "Id", time.Hour, Trip{})
+	for _, row := range partitions {
+		require.NoError(t, mockTbl.Set(Trip{Id: row["id"].(string), Time: row["time"].(time.Time)}).Run())
+	}
+
+	ids := func(tbl TimeSeriesTable) []string {
+		trips := []Trip{}
+		require.NoError(t, tbl.List(start, at(3*time.Hour), &trips).Run())
+		ids := []string{}
+		for _, trip := range trips {
+			ids = append(ids, trip.Id)
+		}
+		return ids
+	}
+	assert.Equal(t, []string{"a", "b", "b2", "c", "d"}, ids(tbl))
+	assert.Equal(t, ids(tbl), ids(mockTbl))
+
+	// Rows are merged in the clustering order of the time column
+	desc := Options{ClusteringOrder: []ClusteringOrderColumn{{Direction: DESC, Column: "Time"}, {Direction: DESC, Column: "Id"}}}
+	descPartitions := partitions
+	partitions = []map[string]interface{}{partitions[4], partitions[1], partitions[0], partitions[3], partitions[2]}
+	assert.Equal(t, []string{"d", "c", "b2", "b", "a"}, ids(tbl.WithOptions(desc)))
+	descMock := NewMockKeySpace().TimeSeriesTable("trips", "Time", "Id", time.Hour, Trip{}).WithOptions(desc)
+	for _, row := range descPartitions {
+		require.NoError(t, descMock.Set(Trip{Id: row["id"].(string), Time: row["time"].(time.Time)}).Run())
+	}
+	assert.Equal(t, ids(tbl.WithOptions(desc)), ids(descMock))
+}
+
+func TestMergeSeriesRows(t *testing.T) {
+	at := func(minute int) time.Time {
+		return parse("2006 Jan 2 15:00:00").Add(time.Duration(minute) * time.Minute)
+	}
+	// Runs of rows in order, with rows sharing a time ordered by ID
+	rows := []Trip{
+		{Id: "e", Time: at(3)}, {Id: "f", Time: at(4)},
+		{Id: "a", Time: at(1)}, {Id: "c", Time: at(2)},
+		{Id: "b", Time: at(2)}, {Id: "d", Time: at(2)}, {Id: "g", Time: at(5)},
+	}
+	value := reflect.ValueOf(rows)
+	mergeSeriesRows(value, ASC, timeSeriesOrder("Time", []string{"Id"}))
+
+	ids := ""
+	for _, row := range rows {
+		ids += row.Id
+	}
+	assert.Equal(t, "abcdefg", ids)
+}
+
+func TestTimeSeriesListStatement(t *testing.T) {
+	ks := NewConnection(funcQE{}).KeySpace("ks")
+	tbl := ks.TimeSeriesTable("trips", "Time", "Id", time.Minute, Trip{})
+
+	// Listing is described by the read it runs, even though the rows it reads are merged once it has run
+	trips := []Trip{}
+	op := tbl.List(parse("2006 Jan 2 15:03:58"), parse("2006 Jan 2 15:04:02"), &trips)
+	assert.Equal(t, "SELECT id, time, bucket FROM ks.trips_timeSeries_Time_Id_1m0s WHERE bucket IN ? AND time >= ? AND time <= ?", op.GenerateStatement().Query())
+	assert.NoError(t, op.Preflight())
+
+	flakes := ks.FlakeSeriesTable("flakes", "Id", time.Minute, Trip{})
+	op = flakes.ListSince(timeToFlake(t, "2006 Jan 2 15:03:59"), time.Minute, &trips)
+	assert.Equal(t, "SELECT id, time, bucket, flake_created FROM ks.flakes_flakeSeries_Id_1m0s WHERE bucket IN ? AND flake_created >= ? AND flake_created < ?", op.GenerateStatement().Query())
+}
+
+func TestMockSeriesListSinceClock(t *testing.T) {
+	ks := NewMockKeySpace()
+	tbl := ks.TimeSeriesTable("trips", "Time", "Id", time.Minute, Trip{})
+	flakes := ks.FlakeSeriesTable("flakes", "Id", time.Minute, Trip{})
+	times := []string{"2006 Jan 2 15:03:59", "2006 Jan 2 15:04:00", "2006 Jan 2 15:04:30", "2006 Jan 2 15:20:00"}
+	ids := make([]string, len(times))
+	for i, at := range times {
+		ids[i] = timeToFlake(t, at)
+		require.NoError(t, tbl.Set(Trip{Id: ids[i], Time: parse(at)}).Run())
+		require.NoError(t, flakes.Set(Trip{Id: ids[i]}).Run())
+	}
+
+	// Without a window, rows are listed up until five minutes past the time told by the op's clock
+	clock := NewMockClock(parse("2006 Jan 2 15:04:10"))
+	opts := Options{Clock: clock}
+	trips := []Trip{}
+	require.NoError(t, tbl.ListSince(parse(times[0]), ids[0], 0, &trips).WithOptions(opts).Run())
+	assert.Equal(t, []Trip{{Id: ids[1], Time: parse(times[1])}, {Id: ids[2], Time: parse(times[2])}}, trips)
+	require.NoError(t, flakes.ListSince(ids[0], 0, &trips).WithOptions(opts).Run())
+	assert.Equal(t, []Trip{{Id: ids[1]}, {Id: ids[2]}}, trips)
+
+	// The time is told when the op runs
+	op := flakes.ListSince(ids[0], 0, &trips).WithOptions(opts)
+	clock.Advance(time.Hour)
+	require.NoError(t, op.Run())
+	assert.Equal(t, []Trip{{Id: ids[1]}, {Id: ids[2]}, {Id: ids[3]}}, trips)
+}
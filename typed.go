@@ -0,0 +1,727 @@
+package gocassa
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	r "github.com/monzo/gocassa/reflect"
+)
+
+// The typed tables wrap each recipe for rows of type T, so rows are read and written without passing pointers as
+// interface{} values and mismatched types fail to compile. T must be the struct type (or a struct type with the same
+// columns as) the recipe was defined with, which is checked when the typed table is created. Rows are decoded into
+// values of type T by the recipe's Scanner on each read, just as they are by the untyped recipe.
+
+// readRow runs the read returned by op, which populates a single row, and returns the row
+func readRow[T any](ctx context.Context, op func(pointer interface{}) Op) (T, error) {
+	var row T
+	err := op(&row).RunWithContext(ctx)
+	return row, err
+}
+
+// readRows runs the read returned by op, which populates a slice of rows, and returns the rows
+func readRows[T any](ctx context.Context, op func(pointerToASlice interface{}) Op) ([]T, error) {
+	rows := []T{}
+	err := op(&rows).RunWithContext(ctx)
+	return rows, err
+}
+
+// nextPage returns the options listing the next page, or nil if there are no more rows
+func nextPage[O any](opts O, more bool) *O {
+	if !more {
+		return nil
+	}
+	return &opts
+}
+
+// mustBeRowType panics if T isn't a struct whose columns all belong to the table, as Set would otherwise fail (or
+// silently drop columns) at runtime
+func mustBeRowType[T any](table Table) {
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	fieldMap, err := r.StructFieldMap(typ, true)
+	if err != nil {
+		panic(fmt.Sprintf("can't use %v as the row type of %s: %v", typ, table.Name(), err))
+	}
+
+	columns, ok := tableColumns(table)
+	if !ok {
+		return
+	}
+	defined := make(map[string]bool, len(columns))
+	for _, c := range columns {
+		defined[strings.ToLower(c)] = true
+	}
+	names := make([]string, 0, len(fieldMap))
+	for name := range fieldMap {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if !defined[name] {
+			panic(fmt.Sprintf("can't use %v as the row type of %s: table has no column %s", typ, table.Name(), name))
+		}
+	}
+}
+
+// tableColumns returns the columns a table was defined with, if known
+func tableColumns(table Table) ([]string, bool) {
+	switch tbl := table.(type) {
+	case t:
+		return tbl.info.fields, true
+	case *t:
+		return tbl.info.fields, true
+	case *MockTable:
+		return tbl.fields, true
+	}
+	return nil, false
+}
+
+// TypedMapTable is a MapTable holding rows of type T.
+type TypedMapTable[T any] struct {
+	table MapTable
+}
+
+// TypedMap returns a typed view of a MapTable defined with rows of type T.
+func TypedMap[T any](table MapTable) TypedMapTable[T] {
+	mustBeRowType[T](table.Table())
+	return TypedMapTable[T]{table: table}
+}
+
+// Table returns the underlying MapTable.
+func (tt TypedMapTable[T]) Table() MapTable { return tt.table }
+
+// WithOptions returns a copy of the table with the options applied.
+func (tt TypedMapTable[T]) WithOptions(o Options) TypedMapTable[T] {
+	return TypedMapTable[T]{table: tt.table.WithOptions(o)}
+}
+
+// Set inserts, or replaces, a row.
+func (tt TypedMapTable[T]) Set(ctx context.Context, row T) error {
+	return tt.table.Set(row).RunWithContext(ctx)
+}
+
+// Read returns the row with the given partition key.
+func (tt TypedMapTable[T]) Read(ctx context.Context, partitionKey interface{}) (T, error) {
+	return readRow[T](ctx, func(pointer interface{}) Op { return tt.table.Read(partitionKey, pointer) })
+}
+
+// MultiRead returns the rows with the given partition keys.
+func (tt TypedMapTable[T]) MultiRead(ctx context.Context, partitionKeys ...interface{}) ([]T, error) {
+	return readRows[T](ctx, func(pointer interface{}) Op { return tt.table.MultiRead(partitionKeys, pointer) })
+}
+
+// Update sets the given values on the row with the given partition key.
+func (tt TypedMapTable[T]) Update(ctx context.Context, partitionKey interface{}, values map[string]interface{}) error {
+	return tt.table.Update(partitionKey, values).RunWithContext(ctx)
+}
+
+// UpdateWithRetry reads the row with the given partition key, changes it with update and writes it back, as long as
+// it hasn't been written since it was read. Otherwise it starts again from the row as it's been written, a few times
+// before failing with a ConcurrentModificationError. T must have a version field, and update mustn't change the
+// row's keys. The row is returned as it was written.
+func (tt TypedMapTable[T]) UpdateWithRetry(ctx context.Context, partitionKey interface{}, update func(*T) error) (T, error) {
+	return updateWithRetry(ctx,
+		func(pointer interface{}) Op { return tt.table.Read(partitionKey, pointer) },
+		func(row T) Op { return tt.table.Set(row) },
+		update)
+}
+
+// Delete removes the row with the given partition key.
+func (tt TypedMapTable[T]) Delete(ctx context.Context, partitionKey interface{}) error {
+	return tt.table.Delete(partitionKey).RunWithContext(ctx)
+}
+
+// TypedMultimapTable is a MultimapTable holding rows of type T.
+type TypedMultimapTable[T any] struct {
+	table MultimapTable
+}
+
+// TypedMultimap returns a typed view of a MultimapTable defined with rows of type T.
+func TypedMultimap[T any](table MultimapTable) TypedMultimapTable[T] {
+	mustBeRowType[T](table.Table())
+	return TypedMultimapTable[T]{table: table}
+}
+
+// Table returns the underlying MultimapTable.
+func (tt TypedMultimapTable[T]) Table() MultimapTable { return tt.table }
+
+// WithOptions returns a copy of the table with the options applied.
+func (tt TypedMultimapTable[T]) WithOptions(o Options) TypedMultimapTable[T] {
+	return TypedMultimapTable[T]{table: tt.table.WithOptions(o)}
+}
+
+// Set inserts, or replaces, a row.
+func (tt TypedMultimapTable[T]) Set(ctx context.Context, row T) error {
+	return tt.table.Set(row).RunWithContext(ctx)
+}
+
+// Read returns the row with the given keys.
+func (tt TypedMultimapTable[T]) Read(ctx context.Context, partitionKey, clusteringKey interface{}) (T, error) {
+	return readRow[T](ctx, func(pointer interface{}) Op { return tt.table.Read(partitionKey, clusteringKey, pointer) })
+}
+
+// List returns up to limit rows of the partition, starting from the clustering key given. To start from the
+// beginning of the partition set clusteringKey to nil, and to disable the limit set it to 0.
+func (tt TypedMultimapTable[T]) List(ctx context.Context, partitionKey, clusteringKey interface{}, limit int) ([]T, error) {
+	return readRows[T](ctx, func(pointer interface{}) Op {
+		return tt.table.List(partitionKey, clusteringKey, limit, pointer)
+	})
+}
+
+// ListRange returns the rows of the partition within the bounds of opts, along with the options listing the next
+// page of rows, which is nil if there are no more.
+func (tt TypedMultimapTable[T]) ListRange(ctx context.Context, partitionKey interface{}, opts ListOptions) ([]T, *ListOptions, error) {
+	rows, err := readRows[T](ctx, func(pointer interface{}) Op { return tt.table.ListRange(partitionKey, opts, pointer) })
+	if err != nil {
+		return nil, nil, err
+	}
+	return rows, nextPage(tt.table.NextPage(opts, &rows)), nil
+}
+
+// Update sets the given values on the row with the given keys.
+func (tt TypedMultimapTable[T]) Update(ctx context.Context, partitionKey, clusteringKey interface{}, values map[string]interface{}) error {
+	return tt.table.Update(partitionKey, clusteringKey, values).RunWithContext(ctx)
+}
+
+// UpdateWithRetry reads the row with the given keys, changes it with update and writes it back, retrying if it's
+// written concurrently. See TypedMapTable.UpdateWithRetry.
+func (tt TypedMultimapTable[T]) UpdateWithRetry(ctx context.Context, partitionKey, clusteringKey interface{}, update func(*T) error) (T, error) {
+	return updateWithRetry(ctx,
+		func(pointer interface{}) Op { return tt.table.Read(partitionKey, clusteringKey, pointer) },
+		func(row T) Op { return tt.table.Set(row) },
+		update)
+}
+
+// Delete removes the row with the given keys.
+func (tt TypedMultimapTable[T]) Delete(ctx context.Context, partitionKey, clusteringKey interface{}) error {
+	return tt.table.Delete(partitionKey, clusteringKey).RunWithContext(ctx)
+}
+
+// DeleteAll removes all the rows of the partition.
+func (tt TypedMultimapTable[T]) DeleteAll(ctx context.Context, partitionKey interface{}) error {
+	return tt.table.DeleteAll(partitionKey).RunWithContext(ctx)
+}
+
+// TypedMultimapMkTable is a MultimapMkTable holding rows of type T.
+type TypedMultimapMkTable[T any] struct {
+	table MultimapMkTable
+}
+
+// TypedMultimapMk returns a typed view of a MultimapMkTable defined with rows of type T.
+func TypedMultimapMk[T any](table MultimapMkTable) TypedMultimapMkTable[T] {
+	mustBeRowType[T](table.Table())
+	return TypedMultimapMkTable[T]{table: table}
+}
+
+// Table returns the underlying MultimapMkTable.
+func (tt TypedMultimapMkTable[T]) Table() MultimapMkTable { return tt.table }
+
+// WithOptions returns a copy of the table with the options applied.
+func (tt TypedMultimapMkTable[T]) WithOptions(o Options) TypedMultimapMkTable[T] {
+	return TypedMultimapMkTable[T]{table: tt.table.WithOptions(o)}
+}
+
+// Set inserts, or replaces, a row.
+func (tt TypedMultimapMkTable[T]) Set(ctx context.Context, row T) error {
+	return tt.table.Set(row).RunWithContext(ctx)
+}
+
+// Read returns the row with the given keys.
+func (tt TypedMultimapMkTable[T]) Read(ctx context.Context, v, id map[string]interface{}) (T, error) {
+	return readRow[T](ctx, func(pointer interface{}) Op { return tt.table.Read(v, id, pointer) })
+}
+
+// MultiRead returns the rows of the partition matching the clustering keys given.
+func (tt TypedMultimapMkTable[T]) MultiRead(ctx context.Context, v, id map[string]interface{}) ([]T, error) {
+	return readRows[T](ctx, func(pointer interface{}) Op { return tt.table.MultiRead(v, id, pointer) })
+}
+
+// List returns up to limit rows of the partition, starting from the clustering keys given. To disable the limit set
+// it to 0.
+func (tt TypedMultimapMkTable[T]) List(ctx context.Context, v, startID map[string]interface{}, limit int) ([]T, error) {
+	return readRows[T](ctx, func(pointer interface{}) Op { return tt.table.List(v, startID, limit, pointer) })
+}
+
+// ListRange returns the rows of the partition within the bounds of opts, along with the options listing the next
+// page of rows, which is nil if there are no more.
+func (tt TypedMultimapMkTable[T]) ListRange(ctx context.Context, v map[string]interface{}, opts ListOptions) ([]T, *ListOptions, error) {
+	rows, err := readRows[T](ctx, func(pointer interface{}) Op { return tt.table.ListRange(v, opts, pointer) })
+	if err != nil {
+		return nil, nil, err
+	}
+	return rows, nextPage(tt.table.NextPage(opts, &rows)), nil
+}
+
+// Update sets the given values on the row with the given keys.
+func (tt TypedMultimapMkTable[T]) Update(ctx context.Context, v, id map[string]interface{}, values map[string]interface{}) error {
+	return tt.table.Update(v, id, values).RunWithContext(ctx)
+}
+
+// Delete removes the row with the given keys.
+func (tt TypedMultimapMkTable[T]) Delete(ctx context.Context, v, id map[string]interface{}) error {
+	return tt.table.Delete(v, id).RunWithContext(ctx)
+}
+
+// DeleteAll removes all the rows of the partition.
+func (tt TypedMultimapMkTable[T]) DeleteAll(ctx context.Context, v map[string]interface{}) error {
+	return tt.table.DeleteAll(v).RunWithContext(ctx)
+}
+
+// TypedTimeSeriesTable is a TimeSeriesTable holding rows of type T.
+type TypedTimeSeriesTable[T any] struct {
+	table TimeSeriesTable
+}
+
+// TypedTimeSeries returns a typed view of a TimeSeriesTable defined with rows of type T.
+func TypedTimeSeries[T any](table TimeSeriesTable) TypedTimeSeriesTable[T] {
+	mustBeRowType[T](table.Table())
+	return TypedTimeSeriesTable[T]{table: table}
+}
+
+// Table returns the underlying TimeSeriesTable.
+func (tt TypedTimeSeriesTable[T]) Table() TimeSeriesTable { return tt.table }
+
+// WithOptions returns a copy of the table with the options applied.
+func (tt TypedTimeSeriesTable[T]) WithOptions(o Options) TypedTimeSeriesTable[T] {
+	return TypedTimeSeriesTable[T]{table: tt.table.WithOptions(o)}
+}
+
+// Set inserts, or replaces, a row.
+func (tt TypedTimeSeriesTable[T]) Set(ctx context.Context, row T) error {
+	return tt.table.Set(row).RunWithContext(ctx)
+}
+
+// Read returns the row with the given time and ID.
+func (tt TypedTimeSeriesTable[T]) Read(ctx context.Context, timeStamp time.Time, id interface{}) (T, error) {
+	return readRow[T](ctx, func(pointer interface{}) Op { return tt.table.Read(timeStamp, id, pointer) })
+}
+
+// ListWithLimit returns up to opts.Limit rows between start and end, walking the buckets in the order opts
+// specifies, along with the options resuming the listing after them, which is nil if there are no more.
+func (tt TypedTimeSeriesTable[T]) ListWithLimit(ctx context.Context, start, end time.Time, opts SeriesListOptions) ([]T, *SeriesListOptions, error) {
+	rows, err := readRows[T](ctx, func(pointer interface{}) Op { return tt.table.ListWithLimit(start, end, opts, pointer) })
+	if err != nil {
+		return nil, nil, err
+	}
+	return rows, nextPage(tt.table.NextPage(opts, &rows)), nil
+}
+
+// List returns the rows between start and end.
+func (tt TypedTimeSeriesTable[T]) List(ctx context.Context, start, end time.Time) ([]T, error) {
+	return readRows[T](ctx, func(pointer interface{}) Op { return tt.table.List(start, end, pointer) })
+}
+
+// ListSince returns the rows after the row at timeStamp with the given ID and within the window. See
+// TimeSeriesTable.ListSince.
+func (tt TypedTimeSeriesTable[T]) ListSince(ctx context.Context, timeStamp time.Time, id interface{}, window time.Duration) ([]T, error) {
+	return readRows[T](ctx, func(pointer interface{}) Op { return tt.table.ListSince(timeStamp, id, window, pointer) })
+}
+
+// ListBefore returns the rows before the row at timeStamp with the given ID and within the window. See
+// TimeSeriesTable.ListBefore.
+func (tt TypedTimeSeriesTable[T]) ListBefore(ctx context.Context, timeStamp time.Time, id interface{}, window time.Duration) ([]T, error) {
+	return readRows[T](ctx, func(pointer interface{}) Op { return tt.table.ListBefore(timeStamp, id, window, pointer) })
+}
+
+// Update sets the given values on the row with the given time and ID.
+func (tt TypedTimeSeriesTable[T]) Update(ctx context.Context, timeStamp time.Time, id interface{}, values map[string]interface{}) error {
+	return tt.table.Update(timeStamp, id, values).RunWithContext(ctx)
+}
+
+// Delete removes the row with the given time and ID.
+func (tt TypedTimeSeriesTable[T]) Delete(ctx context.Context, timeStamp time.Time, id interface{}) error {
+	return tt.table.Delete(timeStamp, id).RunWithContext(ctx)
+}
+
+// TypedMultiTimeSeriesTable is a MultiTimeSeriesTable holding rows of type T.
+type TypedMultiTimeSeriesTable[T any] struct {
+	table MultiTimeSeriesTable
+}
+
+// TypedMultiTimeSeries returns a typed view of a MultiTimeSeriesTable defined with rows of type T.
+func TypedMultiTimeSeries[T any](table MultiTimeSeriesTable) TypedMultiTimeSeriesTable[T] {
+	mustBeRowType[T](table.Table())
+	return TypedMultiTimeSeriesTable[T]{table: table}
+}
+
+// Table returns the underlying MultiTimeSeriesTable.
+func (tt TypedMultiTimeSeriesTable[T]) Table() MultiTimeSeriesTable { return tt.table }
+
+// WithOptions returns a copy of the table with the options applied.
+func (tt TypedMultiTimeSeriesTable[T]) WithOptions(o Options) TypedMultiTimeSeriesTable[T] {
+	return TypedMultiTimeSeriesTable[T]{table: tt.table.WithOptions(o)}
+}
+
+// Set inserts, or replaces, a row.
+func (tt TypedMultiTimeSeriesTable[T]) Set(ctx context.Context, row T) error {
+	return tt.table.Set(row).RunWithContext(ctx)
+}
+
+// Read returns the row of the series v with the given time and ID.
+func (tt TypedMultiTimeSeriesTable[T]) Read(ctx context.Context, v interface{}, timeStamp time.Time, id interface{}) (T, error) {
+	return readRow[T](ctx, func(pointer interface{}) Op { return tt.table.Read(v, timeStamp, id, pointer) })
+}
+
+// ListWithLimit returns up to opts.Limit rows between start and end, walking the buckets in the order opts
+// specifies, along with the options resuming the listing after them, which is nil if there are no more.
+func (tt TypedMultiTimeSeriesTable[T]) ListWithLimit(ctx context.Context, v interface{}, start, end time.Time, opts SeriesListOptions) ([]T, *SeriesListOptions, error) {
+	rows, err := readRows[T](ctx, func(pointer interface{}) Op { return tt.table.ListWithLimit(v, start, end, opts, pointer) })
+	if err != nil {
+		return nil, nil, err
+	}
+	return rows, nextPage(tt.table.NextPage(opts, &rows)), nil
+}
+
+// List returns the rows of the series v between start and end.
+func (tt TypedMultiTimeSeriesTable[T]) List(ctx context.Context, v interface{}, start, end time.Time) ([]T, error) {
+	return readRows[T](ctx, func(pointer interface{}) Op { return tt.table.List(v, start, end, pointer) })
+}
+
+// ListSince returns the rows of the series v after the row at timeStamp with the given ID and within the window. See
+// MultiTimeSeriesTable.ListSince.
+func (tt TypedMultiTimeSeriesTable[T]) ListSince(ctx context.Context, v interface{}, timeStamp time.Time, id interface{}, window time.Duration) ([]T, error) {
+	return readRows[T](ctx, func(pointer interface{}) Op { return tt.table.ListSince(v, timeStamp, id, window, pointer) })
+}
+
+// ListBefore returns the rows of the series v before the row at timeStamp with the given ID and within the window. See
+// MultiTimeSeriesTable.ListBefore.
+func (tt TypedMultiTimeSeriesTable[T]) ListBefore(ctx context.Context, v interface{}, timeStamp time.Time, id interface{}, window time.Duration) ([]T, error) {
+	return readRows[T](ctx, func(pointer interface{}) Op { return tt.table.ListBefore(v, timeStamp, id, window, pointer) })
+}
+
+// Update sets the given values on the row of the series v with the given time and ID.
+func (tt TypedMultiTimeSeriesTable[T]) Update(ctx context.Context, v interface{}, timeStamp time.Time, id interface{}, values map[string]interface{}) error {
+	return tt.table.Update(v, timeStamp, id, values).RunWithContext(ctx)
+}
+
+// Delete removes the row of the series v with the given time and ID.
+func (tt TypedMultiTimeSeriesTable[T]) Delete(ctx context.Context, v interface{}, timeStamp time.Time, id interface{}) error {
+	return tt.table.Delete(v, timeStamp, id).RunWithContext(ctx)
+}
+
+// TypedMultiKeyTimeSeriesTable is a MultiKeyTimeSeriesTable holding rows of type T.
+type TypedMultiKeyTimeSeriesTable[T any] struct {
+	table MultiKeyTimeSeriesTable
+}
+
+// TypedMultiKeyTimeSeries returns a typed view of a MultiKeyTimeSeriesTable defined with rows of type T.
+func TypedMultiKeyTimeSeries[T any](table MultiKeyTimeSeriesTable) TypedMultiKeyTimeSeriesTable[T] {
+	mustBeRowType[T](table.Table())
+	return TypedMultiKeyTimeSeriesTable[T]{table: table}
+}
+
+// Table returns the underlying MultiKeyTimeSeriesTable.
+func (tt TypedMultiKeyTimeSeriesTable[T]) Table() MultiKeyTimeSeriesTable { return tt.table }
+
+// WithOptions returns a copy of the table with the options applied.
+func (tt TypedMultiKeyTimeSeriesTable[T]) WithOptions(o Options) TypedMultiKeyTimeSeriesTable[T] {
+	return TypedMultiKeyTimeSeriesTable[T]{table: tt.table.WithOptions(o)}
+}
+
+// Set inserts, or replaces, a row.
+func (tt TypedMultiKeyTimeSeriesTable[T]) Set(ctx context.Context, row T) error {
+	return tt.table.Set(row).RunWithContext(ctx)
+}
+
+// Read returns the row of the series v with the given time and IDs.
+func (tt TypedMultiKeyTimeSeriesTable[T]) Read(ctx context.Context, v map[string]interface{}, timeStamp time.Time, id map[string]interface{}) (T, error) {
+	return readRow[T](ctx, func(pointer interface{}) Op { return tt.table.Read(v, timeStamp, id, pointer) })
+}
+
+// ListWithLimit returns up to opts.Limit rows between start and end, walking the buckets in the order opts
+// specifies, along with the options resuming the listing after them, which is nil if there are no more.
+func (tt TypedMultiKeyTimeSeriesTable[T]) ListWithLimit(ctx context.Context, v map[string]interface{}, start, end time.Time, opts SeriesListOptions) ([]T, *SeriesListOptions, error) {
+	rows, err := readRows[T](ctx, func(pointer interface{}) Op { return tt.table.ListWithLimit(v, start, end, opts, pointer) })
+	if err != nil {
+		return nil, nil, err
+	}
+	return rows, nextPage(tt.table.NextPage(opts, &rows)), nil
+}
+
+// List returns the rows of the series v between start and end.
+func (tt TypedMultiKeyTimeSeriesTable[T]) List(ctx context.Context, v map[string]interface{}, start, end time.Time) ([]T, error) {
+	return readRows[T](ctx, func(pointer interface{}) Op { return tt.table.List(v, start, end, pointer) })
+}
+
+// ListSince returns the rows of the series v after the row at timeStamp with the given ID and within the window. See
+// MultiKeyTimeSeriesTable.ListSince.
+func (tt TypedMultiKeyTimeSeriesTable[T]) ListSince(ctx context.Context, v map[string]interface{}, timeStamp time.Time, id map[string]interface{}, window time.Duration) ([]T, error) {
+	return readRows[T](ctx, func(pointer interface{}) Op { return tt.table.ListSince(v, timeStamp, id, window, pointer) })
+}
+
+// ListBefore returns the rows of the series v before the row at timeStamp with the given ID and within the window. See
+// MultiKeyTimeSeriesTable.ListBefore.
+func (tt TypedMultiKeyTimeSeriesTable[T]) ListBefore(ctx context.Context, v map[string]interface{}, timeStamp time.Time, id map[string]interface{}, window time.Duration) ([]T, error) {
+	return readRows[T](ctx, func(pointer interface{}) Op { return tt.table.ListBefore(v, timeStamp, id, window, pointer) })
+}
+
+// Update sets the given values on the row of the series v with the given time and IDs.
+func (tt TypedMultiKeyTimeSeriesTable[T]) Update(ctx context.Context, v map[string]interface{}, timeStamp time.Time, id map[string]interface{}, values map[string]interface{}) error {
+	return tt.table.Update(v, timeStamp, id, values).RunWithContext(ctx)
+}
+
+// Delete removes the row of the series v with the given time and IDs.
+func (tt TypedMultiKeyTimeSeriesTable[T]) Delete(ctx context.Context, v map[string]interface{}, timeStamp time.Time, id map[string]interface{}) error {
+	return tt.table.Delete(v, timeStamp, id).RunWithContext(ctx)
+}
+
+// TypedFlakeSeriesTable is a FlakeSeriesTable holding rows of type T.
+type TypedFlakeSeriesTable[T any] struct {
+	table FlakeSeriesTable
+}
+
+// TypedFlakeSeries returns a typed view of a FlakeSeriesTable defined with rows of type T.
+func TypedFlakeSeries[T any](table FlakeSeriesTable) TypedFlakeSeriesTable[T] {
+	mustBeRowType[T](table.Table())
+	return TypedFlakeSeriesTable[T]{table: table}
+}
+
+// Table returns the underlying FlakeSeriesTable.
+func (tt TypedFlakeSeriesTable[T]) Table() FlakeSeriesTable { return tt.table }
+
+// WithOptions returns a copy of the table with the options applied.
+func (tt TypedFlakeSeriesTable[T]) WithOptions(o Options) TypedFlakeSeriesTable[T] {
+	return TypedFlakeSeriesTable[T]{table: tt.table.WithOptions(o)}
+}
+
+// Set inserts, or replaces, a row.
+func (tt TypedFlakeSeriesTable[T]) Set(ctx context.Context, row T) error {
+	return tt.table.Set(row).RunWithContext(ctx)
+}
+
+// Read returns the row with the given ID.
+func (tt TypedFlakeSeriesTable[T]) Read(ctx context.Context, id string) (T, error) {
+	return readRow[T](ctx, func(pointer interface{}) Op { return tt.table.Read(id, pointer) })
+}
+
+// ListWithLimit returns up to opts.Limit rows between start and end, walking the buckets in the order opts
+// specifies, along with the options resuming the listing after them, which is nil if there are no more.
+func (tt TypedFlakeSeriesTable[T]) ListWithLimit(ctx context.Context, start, end time.Time, opts SeriesListOptions) ([]T, *SeriesListOptions, error) {
+	rows, err := readRows[T](ctx, func(pointer interface{}) Op { return tt.table.ListWithLimit(start, end, opts, pointer) })
+	if err != nil {
+		return nil, nil, err
+	}
+	return rows, nextPage(tt.table.NextPage(opts, &rows)), nil
+}
+
+// List returns the rows between start and end.
+func (tt TypedFlakeSeriesTable[T]) List(ctx context.Context, start, end time.Time) ([]T, error) {
+	return readRows[T](ctx, func(pointer interface{}) Op { return tt.table.List(start, end, pointer) })
+}
+
+// ListSince returns the rows after the given ID and within the window. See FlakeSeriesTable.ListSince.
+func (tt TypedFlakeSeriesTable[T]) ListSince(ctx context.Context, id string, window time.Duration) ([]T, error) {
+	return readRows[T](ctx, func(pointer interface{}) Op { return tt.table.ListSince(id, window, pointer) })
+}
+
+// ListBefore returns the rows before the given ID and within the window. See FlakeSeriesTable.ListBefore.
+func (tt TypedFlakeSeriesTable[T]) ListBefore(ctx context.Context, id string, window time.Duration) ([]T, error) {
+	return readRows[T](ctx, func(pointer interface{}) Op { return tt.table.ListBefore(id, window, pointer) })
+}
+
+// Update sets the given values on the row with the given ID.
+func (tt TypedFlakeSeriesTable[T]) Update(ctx context.Context, id string, values map[string]interface{}) error {
+	return tt.table.Update(id, values).RunWithContext(ctx)
+}
+
+// Delete removes the row with the given ID.
+func (tt TypedFlakeSeriesTable[T]) Delete(ctx context.Context, id string) error {
+	return tt.table.Delete(id).RunWithContext(ctx)
+}
+
+// TypedMultiFlakeSeriesTable is a MultiFlakeSeriesTable holding rows of type T.
+type TypedMultiFlakeSeriesTable[T any] struct {
+	table MultiFlakeSeriesTable
+}
+
+// TypedMultiFlakeSeries returns a typed view of a MultiFlakeSeriesTable defined with rows of type T.
+func TypedMultiFlakeSeries[T any](table MultiFlakeSeriesTable) TypedMultiFlakeSeriesTable[T] {
+	mustBeRowType[T](table.Table())
+	return TypedMultiFlakeSeriesTable[T]{table: table}
+}
+
+// Table returns the underlying MultiFlakeSeriesTable.
+func (tt TypedMultiFlakeSeriesTable[T]) Table() MultiFlakeSeriesTable { return tt.table }
+
+// WithOptions returns a copy of the table with the options applied.
+func (tt TypedMultiFlakeSeriesTable[T]) WithOptions(o Options) TypedMultiFlakeSeriesTable[T] {
+	return TypedMultiFlakeSeriesTable[T]{table: tt.table.WithOptions(o)}
+}
+
+// Set inserts, or replaces, a row.
+func (tt TypedMultiFlakeSeriesTable[T]) Set(ctx context.Context, row T) error {
+	return tt.table.Set(row).RunWithContext(ctx)
+}
+
+// Read returns the row of the series v with the given ID.
+func (tt TypedMultiFlakeSeriesTable[T]) Read(ctx context.Context, v interface{}, id string) (T, error) {
+	return readRow[T](ctx, func(pointer interface{}) Op { return tt.table.Read(v, id, pointer) })
+}
+
+// ListWithLimit returns up to opts.Limit rows between start and end, walking the buckets in the order opts
+// specifies, along with the options resuming the listing after them, which is nil if there are no more.
+func (tt TypedMultiFlakeSeriesTable[T]) ListWithLimit(ctx context.Context, v interface{}, start, end time.Time, opts SeriesListOptions) ([]T, *SeriesListOptions, error) {
+	rows, err := readRows[T](ctx, func(pointer interface{}) Op { return tt.table.ListWithLimit(v, start, end, opts, pointer) })
+	if err != nil {
+		return nil, nil, err
+	}
+	return rows, nextPage(tt.table.NextPage(opts, &rows)), nil
+}
+
+// List returns the rows of the series v between start and end.
+func (tt TypedMultiFlakeSeriesTable[T]) List(ctx context.Context, v interface{}, start, end time.Time) ([]T, error) {
+	return readRows[T](ctx, func(pointer interface{}) Op { return tt.table.List(v, start, end, pointer) })
+}
+
+// ListSince returns the rows of the series v after the given ID and within the window. See
+// MultiFlakeSeriesTable.ListSince.
+func (tt TypedMultiFlakeSeriesTable[T]) ListSince(ctx context.Context, v interface{}, id string, window time.Duration) ([]T, error) {
+	return readRows[T](ctx, func(pointer interface{}) Op { return tt.table.ListSince(v, id, window, pointer) })
+}
+
+// ListBefore returns the rows of the series v before the given ID and within the window. See
+// MultiFlakeSeriesTable.ListBefore.
+func (tt TypedMultiFlakeSeriesTable[T]) ListBefore(ctx context.Context, v interface{}, id string, window time.Duration) ([]T, error) {
+	return readRows[T](ctx, func(pointer interface{}) Op { return tt.table.ListBefore(v, id, window, pointer) })
+}
+
+// Update sets the given values on the row of the series v with the given ID.
+func (tt TypedMultiFlakeSeriesTable[T]) Update(ctx context.Context, v interface{}, id string, values map[string]interface{}) error {
+	return tt.table.Update(v, id, values).RunWithContext(ctx)
+}
+
+// Delete removes the row of the series v with the given ID.
+func (tt TypedMultiFlakeSeriesTable[T]) Delete(ctx context.Context, v interface{}, id string) error {
+	return tt.table.Delete(v, id).RunWithContext(ctx)
+}
+
+// TypedMultiKeyFlakeSeriesTable is a MultiKeyFlakeSeriesTable holding rows of type T.
+type TypedMultiKeyFlakeSeriesTable[T any] struct {
+	table MultiKeyFlakeSeriesTable
+}
+
+// TypedMultiKeyFlakeSeries returns a typed view of a MultiKeyFlakeSeriesTable defined with rows of type T.
+func TypedMultiKeyFlakeSeries[T any](table MultiKeyFlakeSeriesTable) TypedMultiKeyFlakeSeriesTable[T] {
+	mustBeRowType[T](table.Table())
+	return TypedMultiKeyFlakeSeriesTable[T]{table: table}
+}
+
+// Table returns the underlying MultiKeyFlakeSeriesTable.
+func (tt TypedMultiKeyFlakeSeriesTable[T]) Table() MultiKeyFlakeSeriesTable { return tt.table }
+
+// WithOptions returns a copy of the table with the options applied.
+func (tt TypedMultiKeyFlakeSeriesTable[T]) WithOptions(o Options) TypedMultiKeyFlakeSeriesTable[T] {
+	return TypedMultiKeyFlakeSeriesTable[T]{table: tt.table.WithOptions(o)}
+}
+
+// Set inserts, or replaces, a row.
+func (tt TypedMultiKeyFlakeSeriesTable[T]) Set(ctx context.Context, row T) error {
+	return tt.table.Set(row).RunWithContext(ctx)
+}
+
+// Read returns the row of the series v with the given ID.
+func (tt TypedMultiKeyFlakeSeriesTable[T]) Read(ctx context.Context, v map[string]interface{}, id string) (T, error) {
+	return readRow[T](ctx, func(pointer interface{}) Op { return tt.table.Read(v, id, pointer) })
+}
+
+// ListWithLimit returns up to opts.Limit rows between start and end, walking the buckets in the order opts
+// specifies, along with the options resuming the listing after them, which is nil if there are no more.
+func (tt TypedMultiKeyFlakeSeriesTable[T]) ListWithLimit(ctx context.Context, v map[string]interface{}, start, end time.Time, opts SeriesListOptions) ([]T, *SeriesListOptions, error) {
+	rows, err := readRows[T](ctx, func(pointer interface{}) Op { return tt.table.ListWithLimit(v, start, end, opts, pointer) })
+	if err != nil {
+		return nil, nil, err
+	}
+	return rows, nextPage(tt.table.NextPage(opts, &rows)), nil
+}
+
+// List returns the rows of the series v between start and end.
+func (tt TypedMultiKeyFlakeSeriesTable[T]) List(ctx context.Context, v map[string]interface{}, start, end time.Time) ([]T, error) {
+	return readRows[T](ctx, func(pointer interface{}) Op { return tt.table.List(v, start, end, pointer) })
+}
+
+// ListSince returns the rows of the series v after the given ID and within the window. See
+// MultiKeyFlakeSeriesTable.ListSince.
+func (tt TypedMultiKeyFlakeSeriesTable[T]) ListSince(ctx context.Context, v map[string]interface{}, id string, window time.Duration) ([]T, error) {
+	return readRows[T](ctx, func(pointer interface{}) Op { return tt.table.ListSince(v, id, window, pointer) })
+}
+
+// ListBefore returns the rows of the series v before the given ID and within the window. See
+// MultiKeyFlakeSeriesTable.ListBefore.
+func (tt TypedMultiKeyFlakeSeriesTable[T]) ListBefore(ctx context.Context, v map[string]interface{}, id string, window time.Duration) ([]T, error) {
+	return readRows[T](ctx, func(pointer interface{}) Op { return tt.table.ListBefore(v, id, window, pointer) })
+}
+
+// Update sets the given values on the row of the series v with the given ID.
+func (tt TypedMultiKeyFlakeSeriesTable[T]) Update(ctx context.Context, v map[string]interface{}, id string, values map[string]interface{}) error {
+	return tt.table.Update(v, id, values).RunWithContext(ctx)
+}
+
+// Delete removes the row of the series v with the given ID.
+func (tt TypedMultiKeyFlakeSeriesTable[T]) Delete(ctx context.Context, v map[string]interface{}, id string) error {
+	return tt.table.Delete(v, id).RunWithContext(ctx)
+}
+
+// TypedCacheTable is a CacheTable holding values of type T, which are encoded as JSON. Unlike the other typed tables,
+// T can be any type which can be encoded as JSON.
+type TypedCacheTable[T any] struct {
+	cache CacheTable
+}
+
+// TypedCache returns a typed view of a CacheTable holding values of type T.
+func TypedCache[T any](cache CacheTable) TypedCacheTable[T] {
+	return TypedCacheTable[T]{cache: cache}
+}
+
+// Cache returns the underlying CacheTable.
+func (tc TypedCacheTable[T]) Cache() CacheTable { return tc.cache }
+
+// WithOptions returns a copy of the cache with the options applied.
+func (tc TypedCacheTable[T]) WithOptions(o Options) TypedCacheTable[T] {
+	return TypedCacheTable[T]{cache: tc.cache.WithOptions(o)}
+}
+
+// Get returns the cached value of key, calling the loader on a miss. See CacheTable.Get.
+func (tc TypedCacheTable[T]) Get(ctx context.Context, key string, loader func() (T, error)) (T, error) {
+	var value T
+	data, err := tc.cache.Get(ctx, key, func() ([]byte, error) {
+		loaded, err := loader()
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(loaded)
+	})
+	if data == nil {
+		return value, err
+	}
+	if uerr := json.Unmarshal(data, &value); uerr != nil {
+		return value, fmt.Errorf("can't decode the cached value of %s: %w", key, uerr)
+	}
+	return value, err
+}
+
+// Set caches the value of key.
+func (tc TypedCacheTable[T]) Set(ctx context.Context, key string, value T) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return tc.cache.Set(key, data).RunWithContext(ctx)
+}
+
+// Touch restarts the TTL of key's entry. See CacheTable.Touch.
+func (tc TypedCacheTable[T]) Touch(ctx context.Context, key string) error {
+	return tc.cache.Touch(ctx, key)
+}
+
+// Delete removes key's entry.
+func (tc TypedCacheTable[T]) Delete(ctx context.Context, key string) error {
+	return tc.cache.Delete(key).RunWithContext(ctx)
+}
@@ -0,0 +1,142 @@
+package gocassa
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTypedMap(t *testing.T) {
+	ctx := context.Background()
+	ks := NewMockKeySpace()
+	customers := TypedMap[Customer](ks.MapTable("customer", "Id", Customer{}))
+
+	require.NoError(t, customers.Set(ctx, Customer{Id: "1", Name: "Joe"}))
+	require.NoError(t, customers.Set(ctx, Customer{Id: "2", Name: "Jane"}))
+
+	customer, err := customers.Read(ctx, "1")
+	require.NoError(t, err)
+	assert.Equal(t, Customer{Id: "1", Name: "Joe"}, customer)
+
+	multi, err := customers.MultiRead(ctx, "1", "2")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []Customer{{Id: "1", Name: "Joe"}, {Id: "2", Name: "Jane"}}, multi)
+
+	require.NoError(t, customers.Update(ctx, "1", map[string]interface{}{"Name": "Joseph"}))
+	customer, err = customers.Read(ctx, "1")
+	require.NoError(t, err)
+	assert.Equal(t, "Joseph", customer.Name)
+
+	require.NoError(t, customers.Delete(ctx, "1"))
+	_, err = customers.Read(ctx, "1")
+	assert.IsType(t, RowNotFoundError{}, err)
+}
+
+func TestTypedCache(t *testing.T) {
+	ctx := context.Background()
+	customers := TypedCache[Customer](NewMockKeySpace().CacheTable("customer", time.Minute, time.Minute))
+
+	customer, err := customers.Get(ctx, "1", func() (Customer, error) { return Customer{Id: "1", Name: "Joe"}, nil })
+	require.NoError(t, err)
+	assert.Equal(t, Customer{Id: "1", Name: "Joe"}, customer)
+	customer, err = customers.Get(ctx, "1", func() (Customer, error) { return Customer{}, ErrValueNotFound })
+	require.NoError(t, err)
+	assert.Equal(t, Customer{Id: "1", Name: "Joe"}, customer)
+
+	_, err = customers.Get(ctx, "2", func() (Customer, error) { return Customer{}, ErrValueNotFound })
+	assert.Equal(t, ErrValueNotFound, err)
+	require.NoError(t, customers.Set(ctx, "2", Customer{Id: "2", Name: "Jane"}))
+	customer, err = customers.Get(ctx, "2", func() (Customer, error) { return Customer{}, ErrValueNotFound })
+	require.NoError(t, err)
+	assert.Equal(t, Customer{Id: "2", Name: "Jane"}, customer)
+
+	require.NoError(t, customers.Touch(ctx, "2"))
+	require.NoError(t, customers.Delete(ctx, "2"))
+	assert.Equal(t, ErrNotCached, customers.Touch(ctx, "2"))
+}
+
+func TestTypedMultimap(t *testing.T) {
+	ctx := context.Background()
+	ks := NewMockKeySpace()
+	users := TypedMultimap[*user](ks.MultimapTable("users", "Pk1", "Ck1", &user{}))
+
+	for i := 1; i <= 3; i++ {
+		require.NoError(t, users.Set(ctx, &user{Pk1: 1, Ck1: i, Name: "user"}))
+	}
+
+	u, err := users.Read(ctx, 1, 2)
+	require.NoError(t, err)
+	assert.Equal(t, &user{Pk1: 1, Ck1: 2, Name: "user"}, u)
+
+	list, err := users.List(ctx, 1, nil, 2)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, 1, list[0].Ck1)
+	assert.Equal(t, 2, list[1].Ck1)
+
+	page, next, err := users.ListRange(ctx, 1, ListOptions{Order: DESC, Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, page, 2)
+	assert.Equal(t, 3, page[0].Ck1)
+	require.NotNil(t, next)
+	page, next, err = users.ListRange(ctx, 1, *next)
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	assert.Equal(t, 1, page[0].Ck1)
+	assert.Nil(t, next)
+
+	require.NoError(t, users.Delete(ctx, 1, 2))
+	list, err = users.List(ctx, 1, nil, 0)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, 1, list[0].Ck1)
+	assert.Equal(t, 3, list[1].Ck1)
+}
+
+func TestTypedTimeSeries(t *testing.T) {
+	ctx := context.Background()
+	ks := NewMockKeySpace()
+	points := TypedTimeSeries[point](ks.TimeSeriesTable("points", "Time", "Id", time.Hour, point{}))
+
+	start := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		require.NoError(t, points.Set(ctx, point{Time: start.Add(time.Duration(i) * time.Hour), Id: i, X: float64(i)}))
+	}
+
+	p, err := points.Read(ctx, start.Add(time.Hour), 1)
+	require.NoError(t, err)
+	assert.Equal(t, 1.0, p.X)
+
+	list, err := points.List(ctx, start, start.Add(90*time.Minute))
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, 0, list[0].Id)
+	assert.Equal(t, 1, list[1].Id)
+
+	latest, next, err := points.ListWithLimit(ctx, start, start.Add(3*time.Hour), SeriesListOptions{Order: DESC, Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, latest, 2)
+	assert.Equal(t, 2, latest[0].Id)
+	assert.Equal(t, 1, latest[1].Id)
+	require.NotNil(t, next)
+	latest, next, err = points.ListWithLimit(ctx, start, start.Add(3*time.Hour), *next)
+	require.NoError(t, err)
+	require.Len(t, latest, 1)
+	assert.Equal(t, 0, latest[0].Id)
+	assert.Nil(t, next)
+}
+
+func TestTypedRowTypeMismatch(t *testing.T) {
+	ks := NewMockKeySpace()
+	customers := ks.MapTable("customer", "Id", Customer{})
+
+	assert.PanicsWithValue(t, "can't use gocassa.user as the row type of customer_map_Id: table has no column ck1", func() {
+		TypedMap[user](customers)
+	})
+	assert.Panics(t, func() {
+		TypedMap[string](customers)
+	})
+}
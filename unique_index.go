@@ -0,0 +1,212 @@
+package gocassa
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// UniqueClaim is a row of the lookup table of a UniqueIndex, recording which entity holds a value.
+type UniqueClaim struct {
+	Value string
+	// Owner is the ID of the entity holding the value
+	Owner string
+	// Claimed is when the value was claimed
+	Claimed time.Time
+}
+
+type uniqueIndexT struct {
+	claims      Table
+	primary     MapTable
+	idField     string
+	uniqueField string
+	rowType     reflect.Type
+}
+
+func (u *uniqueIndexT) Primary() MapTable {
+	return u.primary
+}
+
+func (u *uniqueIndexT) Claims() Table {
+	return u.claims
+}
+
+// clock returns the clock of an op's options, or the system clock if they have none
+func (u *uniqueIndexT) clock(opts Options) Clock {
+	if opts.Clock == nil {
+		return SystemClock()
+	}
+	return opts.Clock
+}
+
+// claim claims value for owner, which succeeds if owner holds it already. A claim made again is claimed anew, so a
+// value an entity is still taking isn't repaired from under it.
+func (u *uniqueIndexT) claim(opts Options, value, owner string) error {
+	claim := UniqueClaim{Value: value, Owner: owner, Claimed: u.clock(opts).Now()}
+	err := u.claims.SetIfNotExists(claim).WithOptions(opts).Run()
+	cerr, ok := err.(ConditionFailedError)
+	if !ok {
+		return err
+	}
+	if current := fmt.Sprint(cerr.Current["owner"]); current != owner {
+		return UniqueViolationError{Value: value, Owner: current}
+	}
+
+	err = u.claims.Where(Eq("Value", value)).UpdateIf(map[string]interface{}{"Claimed": claim.Claimed},
+		Eq("Owner", owner)).WithOptions(opts).Run()
+	if cerr, ok := err.(ConditionFailedError); ok {
+		// The value was released and claimed by another entity in the meantime
+		return UniqueViolationError{Value: value, Owner: fmt.Sprint(cerr.Current["owner"])}
+	}
+	return err
+}
+
+// release releases owner's claim on value, which succeeds if owner doesn't hold it
+func (u *uniqueIndexT) release(opts Options, value, owner string) error {
+	err := u.claims.Where(Eq("Value", value)).DeleteIf(Eq("Owner", owner)).WithOptions(opts).Run()
+	if _, ok := err.(ConditionFailedError); ok {
+		return nil
+	}
+	return err
+}
+
+// releaseOrphan releases an orphaned claim unless it's been claimed again since it was found
+func (u *uniqueIndexT) releaseOrphan(opts Options, claim UniqueClaim) error {
+	err := u.claims.Where(Eq("Value", claim.Value)).DeleteIf(Eq("Owner", claim.Owner), Eq("Claimed", claim.Claimed)).
+		WithOptions(opts).Run()
+	if _, ok := err.(ConditionFailedError); ok {
+		return nil
+	}
+	return err
+}
+
+// previous reads the value held by the entity with the given ID, returning whether the entity exists
+func (u *uniqueIndexT) previous(opts Options, id string) (string, bool, error) {
+	prev, err := readMapRow(u.primary, opts, id, u.rowType)
+	if err != nil || prev == nil {
+		return "", false, err
+	}
+	value, _ := prev[u.uniqueField].(string)
+	return value, true, nil
+}
+
+func (u *uniqueIndexT) Claim(value, owner string) Op {
+	return newLazyOp(u.claims.Set(UniqueClaim{}).QueryExecutor(), func(opts Options) error {
+		return u.claim(opts, value, owner)
+	})
+}
+
+func (u *uniqueIndexT) Release(value, owner string) Op {
+	return newLazyOp(u.claims.Set(UniqueClaim{}).QueryExecutor(), func(opts Options) error {
+		return u.release(opts, value, owner)
+	})
+}
+
+func (u *uniqueIndexT) ReadClaim(value string, claim *UniqueClaim) Op {
+	return u.claims.Where(Eq("Value", value)).ReadOne(claim)
+}
+
+func (u *uniqueIndexT) Set(row interface{}) Op {
+	m, ok := toMap(row)
+	if !ok {
+		panic("Can't set: not able to convert")
+	}
+	id, ok := m[u.idField].(string)
+	if !ok {
+		panic(fmt.Sprintf("Id field (%s) is not present or is not a string", u.idField))
+	}
+	value, ok := m[u.uniqueField].(string)
+	if !ok {
+		panic(fmt.Sprintf("Unique field (%s) is not present or is not a string", u.uniqueField))
+	}
+
+	return newLazyOp(u.primary.Set(row).QueryExecutor(), func(opts Options) error {
+		prev, _, err := u.previous(opts, id)
+		if err != nil {
+			return err
+		}
+
+		// The value is claimed even if the entity held it already, so a claim which has been lost is made again
+		if value != "" {
+			if err := u.claim(opts, value, id); err != nil {
+				return err
+			}
+		}
+		if err := u.primary.Set(row).WithOptions(opts).Run(); err != nil {
+			return err
+		}
+		if prev != "" && prev != value {
+			return u.release(opts, prev, id)
+		}
+		return nil
+	})
+}
+
+func (u *uniqueIndexT) Delete(id string) Op {
+	return newLazyOp(u.primary.Delete(id).QueryExecutor(), func(opts Options) error {
+		prev, _, err := u.previous(opts, id)
+		if err != nil {
+			return err
+		}
+		if err := u.primary.Delete(id).WithOptions(opts).Run(); err != nil {
+			return err
+		}
+		if prev != "" {
+			return u.release(opts, prev, id)
+		}
+		return nil
+	})
+}
+
+func (u *uniqueIndexT) Read(id string, pointer interface{}) Op {
+	return u.primary.Read(id, pointer)
+}
+
+// orphans returns the claims made before cutoff whose owner doesn't exist or holds another value
+func (u *uniqueIndexT) orphans(opts Options, cutoff time.Time) ([]UniqueClaim, error) {
+	var claims []UniqueClaim
+	if err := u.claims.Where().Read(&claims).WithOptions(opts).Run(); err != nil {
+		return nil, err
+	}
+
+	orphans := []UniqueClaim{}
+	for _, claim := range claims {
+		if !claim.Claimed.Before(cutoff) {
+			continue
+		}
+		value, ok, err := u.previous(opts, claim.Owner)
+		if err != nil {
+			return nil, err
+		}
+		if !ok || value != claim.Value {
+			orphans = append(orphans, claim)
+		}
+	}
+	return orphans, nil
+}
+
+func (u *uniqueIndexT) Orphans(olderThan time.Duration, claims *[]UniqueClaim) Op {
+	return newLazyOp(u.claims.Set(UniqueClaim{}).QueryExecutor(), func(opts Options) error {
+		orphans, err := u.orphans(opts, u.clock(opts).Now().Add(-olderThan))
+		if err != nil {
+			return err
+		}
+		*claims = orphans
+		return nil
+	})
+}
+
+func (u *uniqueIndexT) Repair(olderThan time.Duration) Op {
+	return newLazyOp(u.claims.Set(UniqueClaim{}).QueryExecutor(), func(opts Options) error {
+		orphans, err := u.orphans(opts, u.clock(opts).Now().Add(-olderThan))
+		if err != nil {
+			return err
+		}
+		for _, claim := range orphans {
+			if err := u.releaseOrphan(opts, claim); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
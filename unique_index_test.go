@@ -0,0 +1,114 @@
+package gocassa
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newMemberEmails(ks KeySpace) UniqueIndex {
+	return ks.UniqueIndex("members", ks.MapTable("members", "Id", member{}), "Id", "Email", member{})
+}
+
+func TestMockUniqueIndex(t *testing.T) {
+	members := newMemberEmails(NewMockKeySpace())
+
+	require.NoError(t, members.Set(member{Id: "1", Email: "joe@example.com"}).Run())
+	require.NoError(t, members.Set(member{Id: "1", Email: "joe@example.com", Team: "payments"}).Run())
+	err := members.Set(member{Id: "2", Email: "joe@example.com"}).Run()
+	assert.Equal(t, UniqueViolationError{Value: "joe@example.com", Owner: "1"}, err)
+	var m member
+	assert.IsType(t, RowNotFoundError{}, members.Read("2", &m).Run())
+
+	// Changing the value releases the one held before
+	require.NoError(t, members.Set(member{Id: "1", Email: "joe@example.org"}).Run())
+	var claim UniqueClaim
+	assert.IsType(t, RowNotFoundError{}, members.ReadClaim("joe@example.com", &claim).Run())
+	require.NoError(t, members.ReadClaim("joe@example.org", &claim).Run())
+	assert.Equal(t, "1", claim.Owner)
+	require.NoError(t, members.Set(member{Id: "2", Email: "joe@example.com"}).Run())
+
+	// Deleting the entity releases its value
+	require.NoError(t, members.Delete("1").Run())
+	assert.IsType(t, RowNotFoundError{}, members.ReadClaim("joe@example.org", &claim).Run())
+	require.NoError(t, members.Set(member{Id: "3", Email: "joe@example.org"}).Run())
+
+	// Owners can't release each other's values
+	require.NoError(t, members.Release("joe@example.org", "2").Run())
+	require.NoError(t, members.ReadClaim("joe@example.org", &claim).Run())
+	assert.Equal(t, "3", claim.Owner)
+}
+
+func TestMockUniqueIndexRepair(t *testing.T) {
+	members := newMemberEmails(NewMockKeySpace())
+
+	require.NoError(t, members.Set(member{Id: "1", Email: "joe@example.com"}).Run())
+	// Claims left by writes which failed part way through: one whose entity was never written, and one whose entity
+	// moved on to another value
+	require.NoError(t, members.Claim("jane@example.com", "2").Run())
+	require.NoError(t, members.Claim("joe@example.org", "1").Run())
+
+	var orphans []UniqueClaim
+	require.NoError(t, members.Orphans(time.Hour, &orphans).Run())
+	assert.Empty(t, orphans)
+	require.NoError(t, members.Orphans(0, &orphans).Run())
+	require.Len(t, orphans, 2)
+	values := []string{orphans[0].Value, orphans[1].Value}
+	assert.ElementsMatch(t, []string{"jane@example.com", "joe@example.org"}, values)
+
+	require.NoError(t, members.Repair(0).Run())
+	require.NoError(t, members.Orphans(0, &orphans).Run())
+	assert.Empty(t, orphans)
+	var claim UniqueClaim
+	require.NoError(t, members.ReadClaim("joe@example.com", &claim).Run())
+	require.NoError(t, members.Set(member{Id: "3", Email: "jane@example.com"}).Run())
+}
+
+func TestMockUniqueIndexClock(t *testing.T) {
+	members := newMemberEmails(NewMockKeySpace())
+	clock := NewMockClock(time.Date(2020, 3, 4, 5, 6, 7, 0, time.UTC))
+	opts := Options{Clock: clock}
+
+	require.NoError(t, members.Claim("jane@example.com", "2").WithOptions(opts).Run())
+	var claim UniqueClaim
+	require.NoError(t, members.ReadClaim("jane@example.com", &claim).Run())
+	assert.True(t, claim.Claimed.Equal(clock.Now()))
+
+	// The claim is only orphaned once it's older than olderThan by the clock
+	var orphans []UniqueClaim
+	require.NoError(t, members.Orphans(time.Hour, &orphans).WithOptions(opts).Run())
+	assert.Empty(t, orphans)
+	clock.Advance(2 * time.Hour)
+	require.NoError(t, members.Orphans(time.Hour, &orphans).WithOptions(opts).Run())
+	require.Len(t, orphans, 1)
+	assert.Equal(t, "jane@example.com", orphans[0].Value)
+}
+
+func TestMockUniqueIndexReclaim(t *testing.T) {
+	members := newMemberEmails(NewMockKeySpace())
+	clock := NewMockClock(time.Date(2020, 3, 4, 5, 6, 7, 0, time.UTC))
+	opts := Options{Clock: clock}
+
+	require.NoError(t, members.Claim("jane@example.com", "2").WithOptions(opts).Run())
+	clock.Advance(2 * time.Hour)
+	var orphans []UniqueClaim
+	require.NoError(t, members.Orphans(time.Hour, &orphans).WithOptions(opts).Run())
+	require.Len(t, orphans, 1)
+
+	// Claiming the value again refreshes the claim, so a repair which found it orphaned before leaves it alone
+	require.NoError(t, members.Claim("jane@example.com", "2").WithOptions(opts).Run())
+	var claim UniqueClaim
+	require.NoError(t, members.ReadClaim("jane@example.com", &claim).Run())
+	assert.True(t, claim.Claimed.Equal(clock.Now()))
+	require.NoError(t, members.(*uniqueIndexT).releaseOrphan(opts, orphans[0]))
+	require.NoError(t, members.ReadClaim("jane@example.com", &claim).Run())
+	assert.Equal(t, "2", claim.Owner)
+
+	require.NoError(t, members.Orphans(time.Hour, &orphans).WithOptions(opts).Run())
+	assert.Empty(t, orphans)
+	clock.Advance(2 * time.Hour)
+	require.NoError(t, members.Repair(time.Hour).WithOptions(opts).Run())
+	assert.IsType(t, RowNotFoundError{}, members.ReadClaim("jane@example.com", &claim).Run())
+}
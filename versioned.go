@@ -0,0 +1,199 @@
+package gocassa
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	r "github.com/monzo/gocassa/reflect"
+)
+
+// updateAttempts is how many times UpdateWithRetry reads, changes and writes a row before giving up on writing it
+// while it's being written concurrently
+const updateAttempts = 5
+
+// versionField returns the field of the row's type tagged with the "version" option, or "" if there isn't one
+func versionField(row interface{}) string {
+	rowType := reflect.TypeOf(row)
+	for rowType != nil && rowType.Kind() == reflect.Ptr {
+		rowType = rowType.Elem()
+	}
+	if rowType == nil {
+		return ""
+	}
+	field, _ := r.VersionField(rowType)
+	return field
+}
+
+// mustBeVersionField returns the version field of the row's type like versionField, panicking if it isn't an integer
+// as every write would otherwise fail
+func mustBeVersionField(row interface{}) string {
+	field := versionField(row)
+	if field == "" {
+		return ""
+	}
+	rowType := reflect.TypeOf(row)
+	for rowType.Kind() == reflect.Ptr {
+		rowType = rowType.Elem()
+	}
+	fields, _ := r.StructFieldMap(rowType, false)
+	switch kind := fields[field].Type().Kind(); kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+	default:
+		panic(fmt.Sprintf("Version field %s of %v must be an integer, not %v", field, rowType, kind))
+	}
+	return field
+}
+
+// versionOf returns the value of a version column as an int64
+func versionOf(value interface{}) (int64, error) {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int(), nil
+	}
+	return 0, fmt.Errorf("a version must be an integer, got %T", value)
+}
+
+// nextVersion returns the version after value, which has the same type
+func nextVersion(value interface{}) (interface{}, int64, error) {
+	version, err := versionOf(value)
+	if err != nil {
+		return nil, 0, err
+	}
+	next := reflect.New(reflect.TypeOf(value)).Elem()
+	next.SetInt(version + 1)
+	return next.Interface(), version, nil
+}
+
+// versionCondition returns the condition that the stored version of a row is expected, which is version of the given
+// type. Version 0 is expected of rows with no version (null), such as rows written before the table had a version
+// field, and of rows which don't exist yet.
+func versionCondition(field string, expected interface{}, version int64) Relation {
+	if version == 0 {
+		return Eq(field, nil)
+	}
+	return Eq(field, expected)
+}
+
+// versionedSet returns the op writing the row m of tbl, which is keyed by keys, if the version in m is the stored
+// one, bumping it. A row with version 0 is written if there isn't one already or it has no version.
+func versionedSet(tbl Table, keys []string, field string, m map[string]interface{}) Op {
+	next, version, err := nextVersion(m[field])
+	if err != nil {
+		return errOp{err: err}
+	}
+
+	row := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		row[k] = v
+	}
+	row[field] = next
+
+	relations := make([]Relation, 0, len(keys))
+	for _, key := range keys {
+		relations = append(relations, Eq(key, row[key]))
+		delete(row, key)
+	}
+	return versionConflict(tbl.Where(relations...).UpdateIf(row, versionCondition(field, m[field], version)), field, version)
+}
+
+// versionedUpdate returns the op updating the row of tbl selected by relations with the values if the version among
+// them is the stored one, bumping it
+func versionedUpdate(tbl Table, relations []Relation, field string, values map[string]interface{}) Op {
+	expected, ok := values[field]
+	if !ok {
+		return errOp{err: fmt.Errorf("an update of %s must include the version (%s) it's based on", tbl.Name(), field)}
+	}
+	next, version, err := nextVersion(expected)
+	if err != nil {
+		return errOp{err: err}
+	}
+
+	m := make(map[string]interface{}, len(values))
+	for k, v := range values {
+		m[k] = v
+	}
+	m[field] = next
+	return versionConflict(tbl.Where(relations...).UpdateIf(m, versionCondition(field, expected, version)), field, version)
+}
+
+// An unversionedMultimap is a MultimapTable which can return a copy of itself whose writes are plain writes, for tables
+// holding copies of rows whose versions are checked elsewhere
+type unversionedMultimap interface {
+	unversioned() MultimapTable
+}
+
+// An unversionedMap is a MapTable which can return a copy of itself whose writes are plain writes, like an
+// unversionedMultimap
+type unversionedMap interface {
+	unversioned() MapTable
+}
+
+// A conditionalOp is a conditional write whose error can be replaced when its conditions don't hold
+type conditionalOp interface {
+	onConditionFailed(conditionFailed func(ConditionFailedError) error) Op
+}
+
+// versionConflict returns op, failing with a ConcurrentModificationError rather than a ConditionFailedError when the
+// stored version isn't the expected one. The op is still a conditional write, so it can be batched with other writes
+// to the same partition.
+func versionConflict(op Op, field string, expected int64) Op {
+	cop, ok := op.(conditionalOp)
+	if !ok {
+		return op
+	}
+	return cop.onConditionFailed(func(cerr ConditionFailedError) error {
+		current := int64(0)
+		if value, ok := cerr.Current[strings.ToLower(field)]; ok && value != nil {
+			current, _ = versionOf(value)
+		}
+		return ConcurrentModificationError{Expected: expected, Current: current}
+	})
+}
+
+// setVersion sets the version field of the row pointed to by pointer
+func setVersion(pointer interface{}, field string, version int64) {
+	row := reflect.Indirect(reflect.ValueOf(pointer))
+	fields, err := r.StructFieldMap(row.Type(), false)
+	if err != nil {
+		return
+	}
+	if f, ok := fields[field]; ok {
+		row.FieldByIndex(f.Index()).SetInt(version)
+	}
+}
+
+// updateWithRetry reads a row with read, changes it with update and writes it with write, which must be a versioned
+// write, starting again from the row as it's been written if it was written concurrently
+func updateWithRetry[T any](ctx context.Context, read func(pointer interface{}) Op, write func(row T) Op, update func(*T) error) (T, error) {
+	var zero T
+	field := versionField(zero)
+	if field == "" {
+		return zero, fmt.Errorf("can't update %T with retries: it has no version field", zero)
+	}
+
+	var err error
+	for attempt := 0; attempt < updateAttempts; attempt++ {
+		row, rerr := readRow[T](ctx, read)
+		if rerr != nil {
+			return zero, rerr
+		}
+		if uerr := update(&row); uerr != nil {
+			return zero, uerr
+		}
+
+		err = write(row).RunWithContext(ctx)
+		if _, ok := err.(ConcurrentModificationError); ok {
+			continue
+		} else if err != nil {
+			return zero, err
+		}
+		m, _ := toMap(row)
+		version, _ := versionOf(m[field])
+		setVersion(&row, field, version+1)
+		return row, nil
+	}
+	return zero, err
+}
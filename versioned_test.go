@@ -0,0 +1,235 @@
+package gocassa
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type account struct {
+	Id      string
+	Owner   string
+	Balance int
+	Version int `cql:"version,version"`
+}
+
+func TestVersionedStatements(t *testing.T) {
+	var stmts []Statement
+	qe := casQE{
+		funcQE: funcQE{fn: func(opts Options, stmt Statement) error { return nil }},
+		cas: func(stmt Statement) (bool, map[string]interface{}, error) {
+			stmts = append(stmts, stmt)
+			return len(stmts) < 3, map[string]interface{}{"id": "1", "version": 5}, nil
+		},
+	}
+	accounts := NewConnection(qe).KeySpace("ks").MapTable("account", "Id", account{})
+
+	require.NoError(t, accounts.Set(account{Id: "1", Balance: 10}).Run())
+	// Version 0 is expected of a row with no version, which is what a row which doesn't exist yet has
+	assert.Equal(t, "UPDATE ks.account_map_Id SET Balance = ?, Owner = ?, version = ? WHERE id = ? IF version = ?", stmts[0].Query())
+	assert.Equal(t, []interface{}{10, "", 1, "1", nil}, stmts[0].Values())
+
+	require.NoError(t, accounts.Set(account{Id: "1", Balance: 20, Version: 1}).Run())
+	assert.Equal(t, "UPDATE ks.account_map_Id SET Balance = ?, Owner = ?, version = ? WHERE id = ? IF version = ?", stmts[1].Query())
+	assert.Equal(t, []interface{}{20, "", 2, "1", 1}, stmts[1].Values())
+
+	err := accounts.Update("1", map[string]interface{}{"Balance": 30, "version": 3}).Run()
+	assert.Equal(t, ConcurrentModificationError{Expected: 3, Current: 5}, err)
+	assert.Equal(t, "UPDATE ks.account_map_Id SET Balance = ?, version = ? WHERE id = ? IF version = ?", stmts[2].Query())
+
+	assert.Error(t, accounts.Update("1", map[string]interface{}{"Balance": 30}).Run())
+	assert.Len(t, stmts, 3)
+}
+
+func TestVersionedBatch(t *testing.T) {
+	var stmts []Statement
+	applied := true
+	qe := casQE{
+		funcQE: funcQE{fn: func(opts Options, stmt Statement) error {
+			stmts = append(stmts, stmt)
+			return nil
+		}},
+		cas: func(stmt Statement) (bool, map[string]interface{}, error) {
+			stmts = append(stmts, stmt)
+			return applied, map[string]interface{}{"id": "1", "version": 4}, nil
+		},
+	}
+	ks := NewConnection(qe).KeySpace("ks")
+	accounts := ks.MapTable("account", "Id", account{})
+	byOwner := ks.MultimapTable("account", "Owner", "Id", account{})
+	owners := ks.MapTable("owner", "Id", Customer{})
+
+	// Versioned writes to a single partition are run as a conditional batch
+	op := byOwner.Set(account{Id: "1", Owner: "joe", Version: 1}).Add(byOwner.Set(account{Id: "2", Owner: "joe"}))
+	require.NoError(t, op.RunAtomically())
+	require.Len(t, stmts, 1)
+	assert.Equal(t, "BEGIN BATCH\n"+
+		"UPDATE ks.account_multimap_Owner_Id SET Balance = ?, version = ? WHERE owner = ? AND id = ? IF version = ?;\n"+
+		"UPDATE ks.account_multimap_Owner_Id SET Balance = ?, version = ? WHERE owner = ? AND id = ? IF version = ?;\n"+
+		"APPLY BATCH", stmts[0].Query())
+
+	// If it isn't applied, it fails as its first versioned write would
+	applied = false
+	assert.Equal(t, ConcurrentModificationError{Expected: 1, Current: 4}, op.RunAtomically())
+
+	// Batches of versioned writes to several partitions or tables fail rather than being dropped
+	stmts = nil
+	op = owners.Set(Customer{Id: "joe"}).Add(accounts.Set(account{Id: "1", Owner: "joe", Version: 1}))
+	assert.Equal(t, errConditionalBatch, op.RunAtomically())
+	op = accounts.Set(account{Id: "1", Version: 1}).Add(accounts.Set(account{Id: "2", Version: 1}))
+	assert.Equal(t, errConditionalBatch, op.WithOptions(Options{BatchType: UnloggedBatch}).RunLoggedBatchWithContext(context.Background()))
+	assert.Empty(t, stmts)
+
+	// An IndexedEntity whose primary table is versioned writes the primary table before its indexes
+	applied = true
+	entity := NewIndexedEntity(accounts, "Id", account{}, EntityIndex{Name: "owner", Field: "Owner", Table: byOwner})
+	require.NoError(t, entity.Set(account{Id: "1", Owner: "joe"}).Run())
+	require.Len(t, stmts, 3)
+	assert.Contains(t, stmts[1].Query(), "UPDATE ks.account_map_Id")
+	// The indexes hold plain copies of the entity, with the version written to the primary table
+	assert.Contains(t, stmts[2].Query(), "UPDATE ks.account_multimap_Owner_Id SET balance = ?, version = ? WHERE owner = ? AND id = ?;")
+	assert.NotContains(t, stmts[2].Query(), " IF ")
+	assert.Contains(t, stmts[2].Values(), 1)
+
+	applied = false
+	stmts = nil
+	assert.Equal(t, ConcurrentModificationError{Expected: 0, Current: 4}, entity.Set(account{Id: "1", Owner: "jane"}).Run())
+	assert.Len(t, stmts, 2)
+
+	mock := NewMockKeySpace()
+	entity = NewIndexedEntity(mock.MapTable("account", "Id", account{}), "Id", account{},
+		EntityIndex{Name: "owner", Field: "Owner", Table: mock.MultimapTable("account", "Owner", "Id", account{})})
+	require.NoError(t, entity.Set(account{Id: "1", Owner: "joe"}).Run())
+	var res []account
+	require.NoError(t, entity.ReadBy("owner", "joe", &res).Run())
+	assert.Len(t, res, 1)
+	assert.IsType(t, ConcurrentModificationError{}, entity.Set(account{Id: "1", Owner: "jane"}).Run())
+}
+
+func TestMockVersionedTables(t *testing.T) {
+	ks := NewMockKeySpace()
+	accounts := ks.MapTable("account", "Id", account{})
+
+	require.NoError(t, accounts.Set(account{Id: "1", Balance: 10}).Run())
+	assert.Equal(t, ConcurrentModificationError{Expected: 0, Current: 1}, accounts.Set(account{Id: "1", Balance: 20}).Run())
+	require.NoError(t, accounts.Set(account{Id: "1", Balance: 20, Version: 1}).Run())
+	require.NoError(t, accounts.Update("1", map[string]interface{}{"Balance": 30, "version": 2}).Run())
+	assert.Equal(t, ConcurrentModificationError{Expected: 2, Current: 3},
+		accounts.Update("1", map[string]interface{}{"Balance": 40, "version": 2}).Run())
+	assert.Equal(t, ConcurrentModificationError{Expected: 1, Current: 0},
+		accounts.Update("2", map[string]interface{}{"Balance": 40, "version": 1}).Run())
+
+	var a account
+	require.NoError(t, accounts.Read("1", &a).Run())
+	assert.Equal(t, account{Id: "1", Balance: 30, Version: 3}, a)
+
+	byOwner := ks.MultimapTable("account", "Owner", "Id", account{})
+	require.NoError(t, byOwner.Set(account{Id: "1", Owner: "joe"}).Run())
+	require.NoError(t, byOwner.Update("joe", "1", map[string]interface{}{"Balance": 10, "version": 1}).Run())
+	assert.IsType(t, ConcurrentModificationError{}, byOwner.Set(account{Id: "1", Owner: "joe", Version: 1}).Run())
+	require.NoError(t, byOwner.Read("joe", "1", &a).Run())
+	assert.Equal(t, account{Id: "1", Owner: "joe", Balance: 10, Version: 2}, a)
+}
+
+func TestMockVersionedTablesWithoutVersions(t *testing.T) {
+	ctx := context.Background()
+	accounts := NewMockKeySpace().MapTable("account", "Id", account{})
+	// Rows written before the table had a version field have no version, and are taken to be at version 0
+	for _, id := range []string{"1", "2", "3"} {
+		require.NoError(t, accounts.Table().Where(Eq("Id", id)).Update(map[string]interface{}{"Balance": 10}).Run())
+	}
+	var a account
+	require.NoError(t, accounts.Read("3", &a).Run())
+	assert.Equal(t, account{Id: "3", Balance: 10}, a)
+
+	require.NoError(t, accounts.Set(account{Id: "1", Balance: 20}).Run())
+	assert.Equal(t, ConcurrentModificationError{Expected: 0, Current: 1}, accounts.Set(account{Id: "1", Balance: 30}).Run())
+	require.NoError(t, accounts.Update("2", map[string]interface{}{"Balance": 20, "version": 0}).Run())
+
+	require.NoError(t, accounts.Read("1", &a).Run())
+	assert.Equal(t, account{Id: "1", Balance: 20, Version: 1}, a)
+	require.NoError(t, accounts.Read("2", &a).Run())
+	assert.Equal(t, account{Id: "2", Balance: 20, Version: 1}, a)
+
+	updated, err := TypedMap[account](accounts).UpdateWithRetry(ctx, "3", func(a *account) error {
+		a.Balance += 5
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, account{Id: "3", Balance: 15, Version: 1}, updated)
+}
+
+func TestTypedUpdateWithRetry(t *testing.T) {
+	ctx := context.Background()
+	ks := NewMockKeySpace()
+	accounts := TypedMap[account](ks.MapTable("account", "Id", account{}))
+	require.NoError(t, accounts.Set(ctx, account{Id: "1", Balance: 10}))
+
+	// The first attempt is overtaken by a concurrent write, so the update is made again on top of it
+	attempts := 0
+	updated, err := accounts.UpdateWithRetry(ctx, "1", func(a *account) error {
+		attempts++
+		if attempts == 1 {
+			require.NoError(t, accounts.Set(ctx, account{Id: "1", Balance: 100, Version: a.Version}))
+		}
+		a.Balance += 5
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, attempts)
+	assert.Equal(t, account{Id: "1", Balance: 105, Version: 3}, updated)
+	stored, err := accounts.Read(ctx, "1")
+	require.NoError(t, err)
+	assert.Equal(t, updated, stored)
+
+	// Retries are bounded
+	attempts = 0
+	_, err = accounts.UpdateWithRetry(ctx, "1", func(a *account) error {
+		attempts++
+		return accounts.Set(ctx, *a)
+	})
+	assert.IsType(t, ConcurrentModificationError{}, err)
+	assert.Equal(t, updateAttempts, attempts)
+
+	updateErr := errors.New("insufficient funds")
+	_, err = accounts.UpdateWithRetry(ctx, "1", func(a *account) error { return updateErr })
+	assert.Equal(t, updateErr, err)
+	_, err = accounts.UpdateWithRetry(ctx, "2", func(a *account) error { return nil })
+	assert.IsType(t, RowNotFoundError{}, err)
+
+	byOwner := TypedMultimap[account](ks.MultimapTable("account", "Owner", "Id", account{}))
+	require.NoError(t, byOwner.Set(ctx, account{Id: "1", Owner: "joe"}))
+	updated, err = byOwner.UpdateWithRetry(ctx, "joe", "1", func(a *account) error {
+		a.Balance = 20
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, account{Id: "1", Owner: "joe", Balance: 20, Version: 2}, updated)
+
+	customers := TypedMap[Customer](ks.MapTable("customer", "Id", Customer{}))
+	_, err = customers.UpdateWithRetry(ctx, "1", func(c *Customer) error { return nil })
+	assert.Error(t, err)
+}
+
+func TestVersionFieldMustBeAnInteger(t *testing.T) {
+	type stringVersion struct {
+		Id      string
+		Owner   string
+		Version string `cql:"version,version"`
+	}
+	type uintVersion struct {
+		Id      string
+		Version uint `cql:"version,version"`
+	}
+	ks := NewMockKeySpace()
+
+	assert.PanicsWithValue(t, "Version field version of gocassa.uintVersion must be an integer, not uint", func() {
+		ks.MapTable("account", "Id", uintVersion{})
+	})
+	assert.Panics(t, func() { ks.MultimapTable("account", "Owner", "Id", stringVersion{}) })
+	assert.Panics(t, func() { ks.ShardedMultimapTable("account", "Owner", "Id", 2, &stringVersion{}) })
+	assert.NotPanics(t, func() { ks.MapTable("account", "Id", account{}) })
+}